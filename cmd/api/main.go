@@ -10,9 +10,15 @@ import (
 	"hotel-booking-api/internal/service"
 	"hotel-booking-api/pkg/config"
 	"hotel-booking-api/pkg/database"
+	"hotel-booking-api/pkg/jwtkeys"
 	"hotel-booking-api/pkg/logger"
+	"hotel-booking-api/pkg/mailer"
+	"hotel-booking-api/pkg/ratelimit"
+	"hotel-booking-api/pkg/storage"
+	"hotel-booking-api/pkg/util"
 	"hotel-booking-api/pkg/validator"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -21,6 +27,7 @@ import (
 
 	"github.com/labstack/echo/v4"
 	echomiddleware "github.com/labstack/echo/v4/middleware"
+	"github.com/redis/go-redis/v9"
 	echoSwagger "github.com/swaggo/echo-swagger"
 )
 
@@ -43,6 +50,11 @@ import (
 // @in header
 // @name Authorization
 // @description Type "Bearer" followed by a space and JWT token.
+
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+// @description API key for partner and internal integrations.
 func main() {
 	cfg, err := config.Load()
 	if err != nil {
@@ -61,30 +73,108 @@ func main() {
 	if err := database.AutoMigrate(db); err != nil {
 		logger.Fatal("Failed to migrate database", "error", err)
 	}
+	if err := repository.MigrateSearchIndexes(db, cfg.Search.Trigram); err != nil {
+		logger.Fatal("Failed to migrate database", "error", err)
+	}
+
+	jwtKeys, err := jwtkeys.Load(jwtkeys.Config{
+		PrivateKeyFile:    cfg.JWT.PrivateKeyFile,
+		PublicKeyFiles:    cfg.JWT.PublicKeyFiles,
+		Secret:            cfg.JWT.SecretKey,
+		LegacySecretUntil: cfg.JWT.LegacySecretUntil,
+		Issuer:            cfg.JWT.Issuer,
+		Audience:          cfg.JWT.Audience,
+	})
+	if err != nil {
+		logger.Fatal("Failed to load JWT keys", "error", err)
+	}
+	util.SetJWTKeys(jwtKeys)
+
+	mediaStorage, err := storage.NewLocalStorage(cfg.Storage.LocalDir, cfg.Storage.BaseURL)
+	if err != nil {
+		logger.Fatal("Failed to initialise media storage", "error", err)
+	}
+
+	exportStorage, err := storage.NewLocalStorage(cfg.Storage.ExportDir, "")
+	if err != nil {
+		logger.Fatal("Failed to initialise export storage", "error", err)
+	}
+
+	mail, err := newMailer(cfg.Mail)
+	if err != nil {
+		logger.Fatal("Failed to initialise mailer", "error", err)
+	}
+
+	rateLimits, err := newRateLimitStore(cfg.RateLimit)
+	if err != nil {
+		logger.Fatal("Failed to initialise rate limit store", "error", err)
+	}
 
 	// Init validator
 	validate := validator.New()
 
 	// Init repo
 	userRepo := repository.NewUserRepository(db)
-	hotelRepo := repository.NewHotelRepository(db)
+	hotelRepo := repository.NewHotelRepository(db, cfg.Search.Trigram)
 	roomRepo := repository.NewRoomRepository(db)
 	bookingRepo := repository.NewBookingRepository(db)
 	paymentRepo := repository.NewPaymentRepository(db)
+	guestRepo := repository.NewGuestRepository(db)
+	amenityRepo := repository.NewAmenityRepository(db)
+	photoRepo := repository.NewPhotoRepository(db)
+	reviewRepo := repository.NewReviewRepository(db)
+	staffRepo := repository.NewHotelStaffRepository(db)
+	roleRepo := repository.NewRoleRepository(db)
+	sessionRepo := repository.NewSessionRepository(db)
+	userTokenRepo := repository.NewUserTokenRepository(db)
+	loginAttemptRepo := repository.NewLoginAttemptRepository(db)
+	recoveryCodeRepo := repository.NewRecoveryCodeRepository(db)
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	dataExportRepo := repository.NewDataExportRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
 
 	// Init service
-	authService := service.NewAuthService(userRepo, validate)
-	hotelService := service.NewHotelService(hotelRepo)
-	roomService := service.NewRoomService(roomRepo, hotelRepo)
-	bookingService := service.NewBookingService(db, bookingRepo, roomRepo, paymentRepo)
+	authService := service.NewAuthService(userRepo, sessionRepo, loginAttemptRepo, recoveryCodeRepo, validate, service.AuthOptions{
+		AccessTTL:             cfg.JWT.AccessTTL,
+		RefreshTTL:            cfg.JWT.RefreshTTL,
+		TOTPIssuer:            cfg.App.Name,
+		RequireAdminTwoFactor: cfg.Auth.RequireAdminTwoFactor,
+		AuditHashKey:          []byte(cfg.Auth.AuditHashKey),
+	})
+	accountService := service.NewAccountService(userRepo, userTokenRepo, sessionRepo, mail, cfg.App.PublicURL)
+	hotelService := service.NewHotelService(hotelRepo, bookingRepo, staffRepo)
+	roomService := service.NewRoomService(roomRepo, hotelRepo, bookingRepo, staffRepo)
+	bookingService := service.NewBookingService(db, bookingRepo, roomRepo, paymentRepo, guestRepo, staffRepo)
 	paymentService := service.NewPaymentService(bookingRepo, paymentRepo, roomRepo)
+	amenityService := service.NewAmenityService(amenityRepo, hotelRepo, roomRepo)
+	reviewService := service.NewReviewService(reviewRepo, bookingRepo, staffRepo)
+	staffService := service.NewStaffService(staffRepo, hotelRepo, userRepo)
+	roleService := service.NewRoleService(roleRepo, userRepo)
+	userService := service.NewUserService(db, userRepo, staffRepo, hotelRepo, sessionRepo)
+	profileService := service.NewProfileService(userRepo, bookingRepo)
+	dataExportService := service.NewDataExportService(dataExportRepo, userRepo, exportStorage, mail, cfg.App.PublicURL)
+	auditService := service.NewAuditService(auditRepo)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo, roleRepo, hotelRepo, staffRepo)
+	photoService := service.NewPhotoService(photoRepo, hotelRepo, roomRepo, mediaStorage, cfg.Storage.MaxUploadMB<<20)
 
 	// Init handlers
-	authHandler := handler.NewAuthHandler(authService)
+	authHandler := handler.NewAuthHandler(authService, accountService)
 	hotelHandler := handler.NewHotelHandler(hotelService)
 	roomHandler := handler.NewRoomHandler(roomService)
 	bookingHandler := handler.NewBookingHandler(bookingService)
 	paymentHandler := handler.NewPaymentHandler(paymentService)
+	guestBookingHandler := handler.NewGuestBookingHandler(bookingService, paymentService)
+	amenityHandler := handler.NewAmenityHandler(amenityService)
+	photoHandler := handler.NewPhotoHandler(photoService)
+	reviewHandler := handler.NewReviewHandler(reviewService)
+	staffHandler := handler.NewStaffHandler(staffService)
+	roleHandler := handler.NewRoleHandler(roleService)
+	userHandler := handler.NewUserHandler(userService)
+	profileHandler := handler.NewProfileHandler(profileService, accountService)
+	dataExportHandler := handler.NewDataExportHandler(dataExportService)
+	jwksHandler := handler.NewJWKSHandler(jwtKeys)
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService)
+	auditHandler := handler.NewAuditHandler(auditService)
 
 	// Init echo
 	e := echo.New()
@@ -94,28 +184,61 @@ func main() {
 	// Custom error handler
 	e.HTTPErrorHandler = middleware.ErrorHandler
 
+	// Client address, as seen through trusted proxies
+	e.IPExtractor = echo.ExtractIPFromXFFHeader(trustOptions(cfg.Server.TrustedProxies)...)
+
 	// Global middleware
 	e.Use(echomiddleware.Recover())
 	e.Use(echomiddleware.CORSWithConfig(echomiddleware.CORSConfig{
-		AllowOrigins: []string{"http://localhost:3000", "http://localhost:8080"},
-		AllowMethods: []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete, http.MethodPatch},
-		AllowHeaders: []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization},
+		AllowOrigins:  []string{"http://localhost:3000", "http://localhost:8080"},
+		AllowMethods:  []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete, http.MethodPatch},
+		AllowHeaders:  []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization, "X-API-Key", "If-Match", "If-None-Match"},
+		ExposeHeaders: []string{"RateLimit-Policy", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", echo.HeaderRetryAfter, "ETag"},
 	}))
 	e.Use(middleware.RequestLogger())
+	e.Use(middleware.RateLimit(rateLimits, "default", cfg.RateLimit.Default, middleware.KeyByIP))
 
 	// Swagger documentation
 	e.GET("/swagger/*", echoSwagger.WrapHandler)
 
+	// Uploaded media
+	e.Static("/media", cfg.Storage.LocalDir)
+
 	// Health check
 	e.GET("/health", healthCheck(cfg))
 
+	// Token verification keys
+	router.SetupJWKSRoutes(e.Group("/.well-known"), jwksHandler)
+
 	// Setup routes
 	api := e.Group("/api/v1")
-	router.SetupAuthRoutes(api, authHandler)
-	router.SetupHotelRoutes(api, hotelHandler, middleware.AuthMiddleware())
-	router.SetupRoomRoutes(api, roomHandler, middleware.AuthMiddleware())
-	router.SetupBookingRoutes(api, bookingHandler, middleware.AuthMiddleware())
-	router.SetupPaymentRoutes(api, paymentHandler)
+	auth := middleware.AuthMiddleware(authService, apiKeyService)
+	// Account and access management is for people, not API keys
+	userAuth := middleware.AuthMiddleware(authService, nil)
+	perm := middleware.NewRequirePermission(roleService, cfg.Auth.RequireAdminTwoFactor)
+	authLimit := middleware.RateLimit(rateLimits, "auth", cfg.RateLimit.Auth, middleware.KeyByIP)
+	bookingLimit := middleware.RateLimit(rateLimits, "booking", cfg.RateLimit.Booking, middleware.KeyByClient)
+	webhookLimit := middleware.RateLimit(rateLimits, "webhook", cfg.RateLimit.Webhook, middleware.KeyByIP)
+	router.SetupAuthRoutes(api, authHandler, userAuth, perm, authLimit)
+	router.SetupHotelRoutes(api, hotelHandler, auth, perm)
+	router.SetupRoomRoutes(api, roomHandler, auth, perm)
+	router.SetupAmenityRoutes(api, amenityHandler, auth, perm)
+	router.SetupPhotoRoutes(api, photoHandler, auth, perm)
+	router.SetupReviewRoutes(api, reviewHandler, auth, perm)
+	router.SetupBookingRoutes(api, bookingHandler, auth, perm, middleware.RequireVerifiedEmail(accountService, cfg.Auth.RequireVerifiedEmailForBooking), bookingLimit)
+	router.SetupStaffRoutes(api, staffHandler, auth, perm)
+	router.SetupRoleRoutes(api, roleHandler, userAuth, perm)
+	router.SetupUserRoutes(api, userHandler, userAuth, perm)
+	router.SetupProfileRoutes(api, profileHandler, userAuth, perm)
+	router.SetupDataExportRoutes(api, dataExportHandler, userAuth, perm)
+	router.SetupAPIKeyRoutes(api, apiKeyHandler, userAuth, perm)
+	router.SetupAuditRoutes(api, auditHandler, userAuth, perm)
+	router.SetupGuestBookingRoutes(api, guestBookingHandler, bookingLimit)
+	router.SetupPaymentRoutes(api, paymentHandler, webhookLimit)
+
+	// Background workers
+	workers, stopWorkers := context.WithCancel(context.Background())
+	go dataExportService.Run(workers)
 
 	// goroutine server
 	go func() {
@@ -132,6 +255,7 @@ func main() {
 	<-quit
 
 	logger.Info("Shutting down server...")
+	stopWorkers()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -143,6 +267,45 @@ func main() {
 	logger.Info("Server stopped")
 }
 
+func newMailer(cfg config.MailConfig) (mailer.Mailer, error) {
+	switch cfg.Driver {
+	case "smtp":
+		return mailer.NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.From), nil
+	case "file":
+		return mailer.NewFileMailer(cfg.Dir, cfg.From)
+	default:
+		return mailer.NewLogMailer(), nil
+	}
+}
+
+func newRateLimitStore(cfg config.RateLimitConfig) (ratelimit.Store, error) {
+	switch cfg.Store {
+	case "redis":
+		opts, err := redis.ParseURL(cfg.RedisURL)
+		if err != nil {
+			return nil, err
+		}
+		return ratelimit.NewRedisStore(redis.NewClient(opts), "ratelimit:"), nil
+	default:
+		return ratelimit.NewMemoryStore(), nil
+	}
+}
+
+// trustOptions trusts private and loopback proxies, as echo does by default,
+// plus the configured ranges.
+func trustOptions(cidrs []string) []echo.TrustOption {
+	var opts []echo.TrustOption
+	for _, cidr := range cidrs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			logger.Fatal("Invalid trusted proxy", "cidr", cidr, "error", err)
+		}
+		opts = append(opts, echo.TrustIPRange(ipNet))
+	}
+
+	return opts
+}
+
 // @Summary Health Check
 // @Description Check if the API is running
 // @Tags health
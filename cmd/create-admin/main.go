@@ -0,0 +1,100 @@
+// Command create-admin provisions the first administrator account. Public
+// registration only creates customers, so a fresh deployment needs this to
+// get an admin who can then create further staff and admin accounts through
+// the API:
+//
+//	go run ./cmd/create-admin -name "Jane Doe" -email admin@example.com
+//
+// The password is taken from ADMIN_PASSWORD, or read from standard input.
+// The command refuses to run while an active admin exists unless -force is
+// given.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"hotel-booking-api/internal/domain"
+	"hotel-booking-api/internal/repository"
+	"hotel-booking-api/internal/service"
+	"hotel-booking-api/pkg/config"
+	"hotel-booking-api/pkg/database"
+	"hotel-booking-api/pkg/validator"
+	"log"
+	"os"
+	"strings"
+)
+
+func main() {
+	name := flag.String("name", "", "admin's full name")
+	email := flag.String("email", "", "admin's email address")
+	force := flag.Bool("force", false, "create the admin even if one already exists")
+	flag.Parse()
+
+	password := os.Getenv("ADMIN_PASSWORD")
+	if password == "" {
+		fmt.Fprint(os.Stderr, "Password: ")
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			log.Fatalf("Failed to read password: %v", err)
+		}
+		password = strings.TrimRight(line, "\r\n")
+	}
+
+	validator.New()
+	input := struct {
+		Name     string `validate:"required,min=3"`
+		Email    string `validate:"required,email"`
+		Password string `validate:"required,min=6"`
+	}{*name, *email, password}
+	if errs := validator.Validate(&input); len(errs) > 0 {
+		log.Fatalf("Invalid admin details: %v", errs)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	db, err := database.InitPostgres(cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	if err := database.AutoMigrate(db); err != nil {
+		log.Fatalf("Failed to migrate database: %v", err)
+	}
+	if err := repository.MigrateSearchIndexes(db, cfg.Search.Trigram); err != nil {
+		log.Fatalf("Failed to migrate database: %v", err)
+	}
+
+	userRepo := repository.NewUserRepository(db)
+
+	admins, err := userRepo.CountActiveAdmins()
+	if err != nil {
+		log.Fatalf("Failed to check for existing admins: %v", err)
+	}
+	if admins > 0 && !*force {
+		log.Fatalf("An admin account already exists; use the admin API or pass -force")
+	}
+
+	userService := service.NewUserService(
+		db,
+		userRepo,
+		repository.NewHotelStaffRepository(db),
+		repository.NewHotelRepository(db, cfg.Search.Trigram),
+		repository.NewSessionRepository(db),
+	)
+
+	user, err := userService.CreateUser(service.Actor{Role: domain.RoleAdmin}, service.NewUserInput{
+		Name:     input.Name,
+		Email:    input.Email,
+		Password: input.Password,
+		Role:     domain.RoleAdmin,
+	})
+	if err != nil {
+		log.Fatalf("Failed to create admin: %v", err)
+	}
+
+	fmt.Printf("Created admin %s (%s)\n", user.Email, user.ID)
+}
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Amenity is an entry in the managed facilities catalogue (wifi, pool, king
+// bed, sea view, ...). Hotels and rooms link to amenities by ID; clients
+// filter by Code.
+type Amenity struct {
+	ID           uuid.UUID         `gorm:"type:uuid;default:uuid_generate_v4;primaryKey" json:"id"`
+	Code         string            `gorm:"type:varchar(50);uniqueIndex;not null" json:"code"`
+	Name         string            `gorm:"not null" json:"name"`
+	Category     string            `gorm:"type:varchar(30);index;not null" json:"category"`
+	Icon         string            `gorm:"type:varchar(100)" json:"icon"`
+	Translations map[string]string `gorm:"type:jsonb;serializer:json" json:"translations"`
+	CreatedAt    time.Time         `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time         `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// LocalizedName returns the translation for lang, falling back to Name.
+func (a *Amenity) LocalizedName(lang string) string {
+	if name, ok := a.Translations[lang]; ok && name != "" {
+		return name
+	}
+
+	return a.Name
+}
@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIKey grants a partner or internal tool machine access with a fixed set of
+// permissions, optionally limited to some hotels. Only the SHA-256 hash of
+// the key is stored; Prefix is the non-secret start of the key, shown in
+// listings so a key can be recognised.
+type APIKey struct {
+	ID          uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4;primaryKey" json:"id"`
+	Name        string     `gorm:"type:varchar(100);not null" json:"name"`
+	Prefix      string     `gorm:"type:varchar(16);uniqueIndex;not null" json:"prefix"`
+	KeyHash     string     `gorm:"type:char(64);uniqueIndex;not null" json:"-"`
+	CreatedByID uuid.UUID  `gorm:"type:uuid;not null;index" json:"created_by_id"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+
+	CreatedBy   User         `gorm:"foreignKey:CreatedByID;constraint:OnDelete:CASCADE;" json:"-"`
+	Permissions []Permission `gorm:"many2many:api_key_permissions;constraint:OnDelete:CASCADE;" json:"permissions,omitempty"`
+	Hotels      []Hotel      `gorm:"many2many:api_key_hotels;constraint:OnDelete:CASCADE;" json:"hotels,omitempty"`
+}
+
+// PermissionCodes returns the codes of the key's permissions.
+func (k *APIKey) PermissionCodes() []string {
+	codes := make([]string, len(k.Permissions))
+	for i, permission := range k.Permissions {
+		codes[i] = permission.Code
+	}
+
+	return codes
+}
+
+// HotelIDs returns the hotels the key is limited to; none means every hotel.
+func (k *APIKey) HotelIDs() []string {
+	ids := make([]string, len(k.Hotels))
+	for i, hotel := range k.Hotels {
+		ids[i] = hotel.ID.String()
+	}
+
+	return ids
+}
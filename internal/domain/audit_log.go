@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Audited entity types.
+const (
+	AuditEntityHotel   = "hotel"
+	AuditEntityRoom    = "room"
+	AuditEntityBooking = "booking"
+	AuditEntityPayment = "payment"
+	AuditEntityUser    = "user"
+	AuditEntityRole    = "role"
+	AuditEntityAPIKey  = "api_key"
+	// AuditEntityLogin entries record lockouts of subjects that are not a
+	// user: "ip:<address>", or "account:<keyed hash>" for unknown emails.
+	AuditEntityLogin = "login"
+)
+
+// Audited actions, named entity.verb.
+const (
+	AuditHotelCreate     = "hotel.create"
+	AuditHotelUpdate     = "hotel.update"
+	AuditHotelDelete     = "hotel.delete"
+	AuditHotelRestore    = "hotel.restore"
+	AuditHotelPurge      = "hotel.purge"
+	AuditRoomCreate      = "room.create"
+	AuditRoomUpdate      = "room.update"
+	AuditRoomDelete      = "room.delete"
+	AuditRoomRestore     = "room.restore"
+	AuditRoomPurge       = "room.purge"
+	AuditBookingCancel   = "booking.cancel"
+	AuditBookingComplete = "booking.complete"
+	AuditPaymentSucceed  = "payment.succeed"
+	AuditPaymentFail     = "payment.fail"
+	AuditUserCreate      = "user.create"
+	AuditUserRoleChange  = "user.role_change"
+	AuditUserDeactivate  = "user.deactivate"
+	AuditUserReactivate  = "user.reactivate"
+	AuditUserRolesSet    = "user.roles_set"
+	AuditUserUnlock      = "user.unlock"
+	AuditRoleCreate      = "role.create"
+	AuditRoleUpdate      = "role.update"
+	AuditRoleDelete      = "role.delete"
+	AuditStaffAssign     = "hotel_staff.assign"
+	AuditStaffRemove     = "hotel_staff.remove"
+	AuditAPIKeyCreate    = "api_key.create"
+	AuditAPIKeyRevoke    = "api_key.revoke"
+	AuditLoginLock       = "login.lock"
+)
+
+// AuditLog records one administrative or financial change. Entries are
+// written in the transaction of the change they describe and are never
+// updated or deleted; the table rejects both.
+//
+// ActorID is empty for changes made by an API key or by an outside system
+// such as the payment provider. It is deliberately not a foreign key, so
+// entries outlive the accounts they mention.
+type AuditLog struct {
+	ID         uuid.UUID              `gorm:"type:uuid;default:uuid_generate_v4;primaryKey" json:"id"`
+	ActorID    *uuid.UUID             `gorm:"type:uuid;index" json:"actor_id,omitempty"`
+	ActorRole  string                 `gorm:"type:varchar(50)" json:"actor_role,omitempty"`
+	APIKeyID   *uuid.UUID             `gorm:"type:uuid;index" json:"api_key_id,omitempty"`
+	Action     string                 `gorm:"type:varchar(50);not null;index" json:"action"`
+	EntityType string                 `gorm:"type:varchar(50);not null;index:idx_audit_logs_entity" json:"entity_type"`
+	EntityID   string                 `gorm:"type:varchar(100);not null;index:idx_audit_logs_entity" json:"entity_id"`
+	Changes    map[string]AuditChange `gorm:"type:jsonb;serializer:json" json:"changes,omitempty"`
+	RequestID  string                 `gorm:"type:varchar(64);index" json:"request_id,omitempty"`
+	IP         string                 `gorm:"type:varchar(45)" json:"ip,omitempty"`
+	CreatedAt  time.Time              `gorm:"autoCreateTime;index" json:"created_at"`
+}
+
+// AuditChange is the value of one field before and after a change. From is
+// nil for creations and To is nil for deletions.
+type AuditChange struct {
+	From any `json:"from,omitempty"`
+	To   any `json:"to,omitempty"`
+}
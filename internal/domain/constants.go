@@ -2,8 +2,10 @@ package domain
 
 const (
 	// user roles
-	RoleCustomer = "CUSTOMER"
-	RoleAdmin    = "ADMIN"
+	RoleCustomer     = "CUSTOMER"
+	RoleAdmin        = "ADMIN"
+	RoleHotelManager = "HOTEL_MANAGER"
+	RoleStaff        = "STAFF"
 
 	BookingStatusPending   = "PENDING"
 	BookingStatusConfirmed = "CONFIRMED"
@@ -18,4 +20,26 @@ const (
 	PaymentMethodCreditCard   = "CREDIT_CARD"
 	PaymentMethodEWallet      = "E_WALLET"
 	PaymentMethodBankTransfer = "BANK_TRANSFER"
+
+	AmenityCategoryGeneral       = "GENERAL"
+	AmenityCategoryRoom          = "ROOM"
+	AmenityCategoryBathroom      = "BATHROOM"
+	AmenityCategoryFood          = "FOOD"
+	AmenityCategoryBed           = "BED"
+	AmenityCategoryView          = "VIEW"
+	AmenityCategoryAccessibility = "ACCESSIBILITY"
+
+	ReviewStatusVisible = "VISIBLE"
+	ReviewStatusHidden  = "HIDDEN"
+	ReviewStatusFlagged = "FLAGGED"
+
+	TokenPurposeEmailVerification = "EMAIL_VERIFICATION"
+	TokenPurposePasswordReset     = "PASSWORD_RESET"
+	TokenPurposeEmailChange       = "EMAIL_CHANGE"
+
+	ExportStatusPending    = "PENDING"
+	ExportStatusProcessing = "PROCESSING"
+	ExportStatusReady      = "READY"
+	ExportStatusFailed     = "FAILED"
+	ExportStatusExpired    = "EXPIRED"
 )
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DataExport is a request for a ZIP archive of everything stored about a
+// user. Archives are built in the background and kept until ExpiresAt.
+// RequestedByID is the user themselves or the admin who asked on their
+// behalf.
+type DataExport struct {
+	ID            uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4;primaryKey" json:"id"`
+	UserID        uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	RequestedByID uuid.UUID  `gorm:"type:uuid;not null" json:"requested_by_id"`
+	Status        string     `gorm:"type:varchar(20);not null;default:'PENDING';index" json:"status"`
+	FileKey       string     `gorm:"type:varchar(255)" json:"-"`
+	Size          int64      `gorm:"not null;default:0" json:"size"`
+	Error         string     `gorm:"type:text" json:"error,omitempty"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"`
+	CreatedAt     time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;" json:"-"`
+}
@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Guest groups the bookings made without an account under one email
+// address. Name and Phone are those given with the first booking and are
+// never changed afterwards: anyone can book with any email, so each booking
+// keeps the contact details it was made with instead.
+type Guest struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4;primaryKey" json:"id"`
+	Name      string    `gorm:"not null" json:"name"`
+	Email     string    `gorm:"uniqueIndex;not null" json:"email"`
+	Phone     string    `gorm:"type:varchar(30);not null" json:"phone"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	Bookings []Booking `gorm:"foreignKey:GuestID" json:"bookings,omitempty"`
+}
@@ -4,15 +4,58 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
+// Hotel is soft-deleted: a deleted hotel and its rooms are hidden from every
+// query that does not ask for them, while bookings keep pointing at them.
 type Hotel struct {
 	ID          uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4;primaryKey" json:"id"`
 	Name        string    `gorm:"not null" json:"name"`
 	Location    string    `gorm:"not null" json:"location"`
+	Address     string    `json:"address"`
+	City        string    `gorm:"index" json:"city"`
+	Country     string    `gorm:"index" json:"country"`
+	Latitude    *float64  `gorm:"index:idx_hotels_coordinates" json:"latitude,omitempty"`
+	Longitude   *float64  `gorm:"index:idx_hotels_coordinates" json:"longitude,omitempty"`
 	Description string    `gorm:"type:text" json:"description"`
-	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	AmenityText string    `gorm:"type:text" json:"-"`
 
-	Room []Room `gorm:"foreignKey:HotelID;constraint:OnDelete:CASCADE;" json:"rooms,omitempty"`
+	// AverageRating and ReviewCount summarise visible reviews and are
+	// recalculated whenever a review is added or moderated.
+	AverageRating float64 `gorm:"not null;default:0" json:"average_rating"`
+	ReviewCount   int     `gorm:"not null;default:0" json:"review_count"`
+
+	// Version goes up with every change to the row and serves as its ETag.
+	Version   int            `gorm:"not null;default:1" json:"version"`
+	CreatedAt time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+
+	Rooms     []Room    `gorm:"foreignKey:HotelID;constraint:OnDelete:CASCADE;" json:"rooms,omitempty"`
+	Amenities []Amenity `gorm:"many2many:hotel_amenities;constraint:OnDelete:CASCADE;" json:"amenities,omitempty"`
+	Photos    []Photo   `gorm:"foreignKey:HotelID;constraint:OnDelete:CASCADE;" json:"photos,omitempty"`
+}
+
+// HotelSearchResult is a hotel matched by availability search together with
+// the cheapest nightly rate among its rooms that fit the request.
+type HotelSearchResult struct {
+	Hotel          `gorm:"embedded"`
+	LowestPrice    float64  `json:"lowest_price"`
+	AvailableRooms int      `json:"available_rooms"`
+	DistanceKm     *float64 `json:"distance_km,omitempty"`
+
+	// Populated only for free-text searches.
+	Relevance          *float64 `json:"relevance,omitempty"`
+	NameHighlight      string   `json:"name_highlight,omitempty"`
+	DescriptionSnippet string   `json:"description_snippet,omitempty"`
+}
+
+// SearchSuggestion is an autocomplete entry: either a destination (city or
+// location text) or a specific hotel.
+type SearchSuggestion struct {
+	Type    string     `json:"type"`
+	HotelID *uuid.UUID `json:"hotel_id,omitempty"`
+	Text    string     `json:"text"`
+	Score   float64    `json:"score"`
 }
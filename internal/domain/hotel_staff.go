@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// HotelStaff links a HOTEL_MANAGER or STAFF user to a hotel they work at.
+// Role is the user's role at this hotel; a manager at one property may be
+// plain staff at another.
+type HotelStaff struct {
+	HotelID   uuid.UUID `gorm:"type:uuid;primaryKey" json:"hotel_id"`
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey;index" json:"user_id"`
+	Role      string    `gorm:"type:varchar(20);not null" json:"role"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+
+	Hotel Hotel `gorm:"foreignKey:HotelID;constraint:OnDelete:CASCADE;" json:"-"`
+	User  User  `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;" json:"user"`
+}
+
+func (HotelStaff) TableName() string {
+	return "hotel_staff"
+}
@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+// LoginAttempt counts recent failed logins for one throttling subject: an
+// account email ("account:<email>") or a client IP ("ip:<addr>"). Subjects
+// are keyed by email rather than user ID so unknown addresses are throttled
+// exactly like registered ones.
+type LoginAttempt struct {
+	Subject       string     `gorm:"type:varchar(330);primaryKey" json:"subject"`
+	Failures      int        `gorm:"not null;default:0" json:"failures"`
+	LastFailureAt time.Time  `gorm:"not null" json:"last_failure_at"`
+	LockedUntil   *time.Time `json:"locked_until,omitempty"`
+}
@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Permission codes checked by routes and services. Codes ending in ":any"
+// lift the usual ownership or hotel-assignment scope.
+const (
+	PermHotelCreate      = "hotel:create"
+	PermHotelUpdate      = "hotel:update"
+	PermHotelDelete      = "hotel:delete"
+	PermRoomWrite        = "room:write"
+	PermRateWrite        = "rate:write"
+	PermAmenityWrite     = "amenity:write"
+	PermPhotoWrite       = "photo:write"
+	PermBookingCreate    = "booking:create"
+	PermBookingRead      = "booking:read"
+	PermBookingReadAny   = "booking:read:any"
+	PermBookingCancel    = "booking:cancel"
+	PermBookingCancelAny = "booking:cancel:any"
+	PermBookingComplete  = "booking:complete"
+	PermReviewWrite      = "review:write"
+	PermReviewReply      = "review:reply"
+	PermReviewModerate   = "review:moderate"
+	PermStaffManage      = "staff:manage"
+	PermRoleManage       = "role:manage"
+	PermUserManage       = "user:manage"
+	PermAPIKeyManage     = "apikey:manage"
+	PermAuditRead        = "audit:read"
+	PermAccountManage    = "account:manage"
+)
+
+// PermissionCatalogue describes every permission; it is synced to the
+// permissions table at start-up.
+var PermissionCatalogue = map[string]string{
+	PermHotelCreate:      "Create hotels",
+	PermHotelUpdate:      "Edit details of assigned hotels",
+	PermHotelDelete:      "Delete, restore and purge hotels and rooms",
+	PermRoomWrite:        "Create and edit rooms of assigned hotels",
+	PermRateWrite:        "Set room prices of assigned hotels",
+	PermAmenityWrite:     "Manage the amenity catalogue and hotel and room amenities",
+	PermPhotoWrite:       "Upload and arrange hotel and room photos",
+	PermBookingCreate:    "Book rooms",
+	PermBookingRead:      "View own bookings and bookings at assigned hotels",
+	PermBookingReadAny:   "View and search every booking",
+	PermBookingCancel:    "Cancel own bookings and bookings at managed hotels",
+	PermBookingCancelAny: "Cancel any booking",
+	PermBookingComplete:  "Mark stays at assigned hotels as completed",
+	PermReviewWrite:      "Review completed stays",
+	PermReviewReply:      "Reply to reviews of managed hotels",
+	PermReviewModerate:   "Hide, flag and restore reviews",
+	PermStaffManage:      "Assign managers and staff to hotels",
+	PermRoleManage:       "Manage roles and user role assignments",
+	PermUserManage:       "Manage user accounts and unlock locked logins",
+	PermAPIKeyManage:     "Create, list and revoke API keys",
+	PermAuditRead:        "View the audit log",
+	PermAccountManage:    "Manage one's own profile, sign-in, two-factor settings and data exports",
+}
+
+// UserBoundPermissions let signed-in users act for themselves: booking a
+// room, reviewing their own stay or managing their own account. API keys have
+// no user and cannot hold them.
+var UserBoundPermissions = []string{PermBookingCreate, PermReviewWrite, PermAccountManage}
+
+// HotelScopedPermissions are checked against the hotel being acted on, so an
+// API key limited to hotels cannot use them elsewhere. Such keys may hold
+// only these.
+var HotelScopedPermissions = []string{
+	PermHotelUpdate, PermRoomWrite, PermRateWrite, PermBookingRead, PermBookingCancel,
+	PermBookingComplete, PermReviewReply,
+}
+
+// SystemRolePermissions are the permissions of the built-in roles named after
+// User.Role. They are restored at start-up and cannot be edited through the
+// API; ADMIN always holds every permission.
+var SystemRolePermissions = map[string][]string{
+	RoleCustomer: {
+		PermAccountManage, PermBookingCreate, PermBookingRead, PermBookingCancel, PermReviewWrite,
+	},
+	RoleStaff: {
+		PermAccountManage, PermBookingCreate, PermBookingRead, PermBookingCancel, PermReviewWrite,
+		PermBookingComplete,
+	},
+	RoleHotelManager: {
+		PermAccountManage, PermBookingCreate, PermBookingRead, PermBookingCancel, PermReviewWrite,
+		PermBookingComplete, PermHotelUpdate, PermRoomWrite, PermRateWrite, PermReviewReply,
+	},
+}
+
+type Permission struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4;primaryKey" json:"id"`
+	Code        string    `gorm:"type:varchar(100);uniqueIndex;not null" json:"code"`
+	Description string    `json:"description"`
+}
+
+// Role groups permissions. System roles mirror User.Role and apply to every
+// user with that role; custom roles are granted to individual users on top.
+type Role struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4;primaryKey" json:"id"`
+	Name        string    `gorm:"type:varchar(50);uniqueIndex;not null" json:"name"`
+	Description string    `json:"description"`
+	IsSystem    bool      `gorm:"not null;default:false" json:"is_system"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	Permissions []Permission `gorm:"many2many:role_permissions;constraint:OnDelete:CASCADE;" json:"permissions,omitempty"`
+}
+
+// PermissionCodes returns the codes of the role's permissions.
+func (r *Role) PermissionCodes() []string {
+	codes := make([]string, len(r.Permissions))
+	for i, permission := range r.Permissions {
+		codes[i] = permission.Code
+	}
+
+	return codes
+}
@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Photo is an uploaded hotel or room image. Hotel-level photos have no
+// RoomID; room photos carry both the room and its hotel.
+type Photo struct {
+	ID           uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4;primaryKey" json:"id"`
+	HotelID      uuid.UUID  `gorm:"type:uuid;not null;index" json:"hotel_id"`
+	RoomID       *uuid.UUID `gorm:"type:uuid;index" json:"room_id,omitempty"`
+	Key          string     `gorm:"not null" json:"-"`
+	ThumbnailKey string     `gorm:"not null" json:"-"`
+	URL          string     `gorm:"not null" json:"url"`
+	ThumbnailURL string     `gorm:"not null" json:"thumbnail_url"`
+	ContentType  string     `gorm:"type:varchar(50);not null" json:"content_type"`
+	Size         int64      `gorm:"not null" json:"size"`
+	Width        int        `gorm:"not null" json:"width"`
+	Height       int        `gorm:"not null" json:"height"`
+	Position     int        `gorm:"not null;default:0" json:"position"`
+	IsCover      bool       `gorm:"not null;default:false" json:"is_cover"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Review is a guest's rating of a completed stay. Each booking can be
+// reviewed once; only VISIBLE reviews count towards the hotel's rating.
+type Review struct {
+	ID             uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4;primaryKey" json:"id"`
+	BookingID      uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex" json:"booking_id"`
+	HotelID        uuid.UUID  `gorm:"type:uuid;not null;index" json:"hotel_id"`
+	UserID         uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	Rating         int        `gorm:"not null" json:"rating"`
+	Cleanliness    int        `gorm:"not null" json:"cleanliness"`
+	Location       int        `gorm:"not null" json:"location"`
+	Service        int        `gorm:"not null" json:"service"`
+	Comment        string     `gorm:"type:text" json:"comment"`
+	Reply          string     `gorm:"type:text" json:"reply,omitempty"`
+	RepliedAt      *time.Time `json:"replied_at,omitempty"`
+	Status         string     `gorm:"type:varchar(20);not null;default:'VISIBLE';index" json:"status"`
+	ModerationNote string     `gorm:"type:text" json:"moderation_note,omitempty"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+
+	Booking Booking `gorm:"foreignKey:BookingID;constraint:OnDelete:CASCADE;" json:"-"`
+	Hotel   Hotel   `gorm:"foreignKey:HotelID;constraint:OnDelete:CASCADE;" json:"-"`
+	User    User    `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;" json:"user"`
+}
@@ -4,17 +4,26 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
+// Room is soft-deleted like Hotel. Bookings restrict a permanent delete so
+// that purging a room can never take booking history with it.
+
 type Room struct {
-	ID            uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4;primaryKey" json:"id"`
-	HotelID       uuid.UUID `gorm:"type:uuid;not null" json:"hotel_id"`
-	RoomType      string    `gorm:"not null" json:"room_type"`
-	PricePerNight float64   `gorm:"not null" json:"price_per_night"`
-	Availability  int       `gorm:"not null;default:1" json:"availability"`
-	CreatedAt     time.Time `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt     time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	ID            uuid.UUID      `gorm:"type:uuid;default:uuid_generate_v4;primaryKey" json:"id"`
+	HotelID       uuid.UUID      `gorm:"type:uuid;not null" json:"hotel_id"`
+	RoomType      string         `gorm:"not null" json:"room_type"`
+	PricePerNight float64        `gorm:"not null" json:"price_per_night"`
+	Availability  int            `gorm:"not null;default:1" json:"availability"`
+	Capacity      int            `gorm:"not null;default:2" json:"capacity"`
+	Version       int            `gorm:"not null;default:1" json:"version"`
+	CreatedAt     time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"deleted_at"`
 
-	Hotel    Hotel     `gorm:"foreignKey:HotelID;constraint:OnDelete:CASCADE;" json:"hotel"`
-	Bookings []Booking `gorm:"foreignKey:RoomID;constraint:OnDelete:CASCADE;" json:"bookings,omitempty"`
+	Hotel     Hotel     `gorm:"foreignKey:HotelID;constraint:OnDelete:CASCADE;" json:"hotel"`
+	Bookings  []Booking `gorm:"foreignKey:RoomID;constraint:OnDelete:RESTRICT;" json:"bookings,omitempty"`
+	Amenities []Amenity `gorm:"many2many:room_amenities;constraint:OnDelete:CASCADE;" json:"amenities,omitempty"`
+	Photos    []Photo   `gorm:"foreignKey:RoomID;constraint:OnDelete:CASCADE;" json:"photos,omitempty"`
 }
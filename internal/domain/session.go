@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshToken is one link in a chain of rotating refresh tokens. Every token
+// issued from the same login shares a FamilyID, so presenting a token that has
+// already been rotated can revoke the whole chain. Only the SHA-256 hash of
+// the token is stored.
+type RefreshToken struct {
+	ID              uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4;primaryKey" json:"id"`
+	UserID          uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	FamilyID        uuid.UUID  `gorm:"type:uuid;not null;index" json:"family_id"`
+	TokenHash       string     `gorm:"type:char(64);uniqueIndex;not null" json:"-"`
+	AccessJTI       string     `gorm:"type:varchar(64);not null" json:"-"`
+	AccessExpiresAt time.Time  `gorm:"not null" json:"-"`
+	MFA             bool       `gorm:"not null;default:false" json:"mfa"`
+	ExpiresAt       time.Time  `gorm:"not null" json:"expires_at"`
+	RotatedAt       *time.Time `json:"rotated_at,omitempty"`
+	RevokedAt       *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt       time.Time  `gorm:"autoCreateTime" json:"created_at"`
+
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;" json:"-"`
+}
+
+// RevokedToken is a deny-list entry for an access token revoked before it
+// expired. Entries are useless once ExpiresAt has passed and may be purged.
+type RevokedToken struct {
+	JTI       string    `gorm:"type:varchar(64);primaryKey" json:"jti"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index" json:"user_id"`
+	ExpiresAt time.Time `gorm:"not null;index" json:"expires_at"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
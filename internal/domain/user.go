@@ -7,13 +7,24 @@ import (
 )
 
 type User struct {
-	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4;primaryKey" json:"id"`
-	Name      string    `gorm:"not null" json:"name"`
-	Email     string    `gorm:"uniqueIndex;not null" json:"email"`
-	Password  string    `gorm:"not null" json:"-"`
-	Role      string    `gorm:"not null;default:'CUSTOMER'" json:"role"`
-	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	ID                uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4;primaryKey" json:"id"`
+	Name              string     `gorm:"not null" json:"name"`
+	Email             string     `gorm:"uniqueIndex;not null" json:"email"`
+	PendingEmail      string     `gorm:"type:varchar(255)" json:"-"`
+	Password          string     `gorm:"not null" json:"-"`
+	Role              string     `gorm:"not null;default:'CUSTOMER'" json:"role"`
+	Phone             string     `gorm:"type:varchar(30)" json:"phone,omitempty"`
+	PreferredCurrency string     `gorm:"type:char(3)" json:"preferred_currency,omitempty"`
+	PreferredLanguage string     `gorm:"type:varchar(35)" json:"preferred_language,omitempty"`
+	EmailVerifiedAt   *time.Time `json:"email_verified_at,omitempty"`
+	TOTPSecret        string     `gorm:"type:varchar(64)" json:"-"`
+	TOTPEnabledAt     *time.Time `json:"totp_enabled_at,omitempty"`
+	TOTPLastStep      int64      `gorm:"not null;default:0" json:"-"`
+	DeactivatedAt     *time.Time `json:"deactivated_at,omitempty"`
+	AnonymizedAt      *time.Time `json:"anonymized_at,omitempty"`
+	CreatedAt         time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
 
 	Bookings []Booking `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;" json:"bookings,omitempty"`
+	Roles    []Role    `gorm:"many2many:user_roles;constraint:OnDelete:CASCADE;" json:"roles,omitempty"`
 }
@@ -0,0 +1,13 @@
+package request
+
+type AmenityRequest struct {
+	Code         string            `json:"code" validate:"required,max=50"`
+	Name         string            `json:"name" validate:"required,max=100"`
+	Category     string            `json:"category" validate:"required,oneof=GENERAL ROOM BATHROOM FOOD BED VIEW ACCESSIBILITY"`
+	Icon         string            `json:"icon" validate:"omitempty,max=100"`
+	Translations map[string]string `json:"translations" validate:"omitempty,dive,keys,min=2,max=10,endkeys,required,max=100"`
+}
+
+type SetAmenitiesRequest struct {
+	Codes []string `json:"codes" validate:"dive,required,max=50"`
+}
@@ -0,0 +1,10 @@
+package request
+
+import "time"
+
+type CreateAPIKeyRequest struct {
+	Name        string     `json:"name" validate:"required,max=100"`
+	Permissions []string   `json:"permissions" validate:"required,min=1,dive,required,max=100"`
+	HotelIDs    []string   `json:"hotel_ids" validate:"omitempty,dive,uuid4"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+}
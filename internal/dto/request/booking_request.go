@@ -7,3 +7,16 @@ type CreateBookingRequest struct {
 	CheckIn  time.Time `json:"check_in" validate:"required"`
 	CheckOut time.Time `json:"check_out" validate:"required,gtfield=CheckIn"`
 }
+
+type CreateGuestBookingRequest struct {
+	Name     string    `json:"name" validate:"required,min=3"`
+	Email    string    `json:"email" validate:"required,email"`
+	Phone    string    `json:"phone" validate:"required,min=6,max=30"`
+	RoomID   string    `json:"room_id" validate:"required,uuid4"`
+	CheckIn  time.Time `json:"check_in" validate:"required"`
+	CheckOut time.Time `json:"check_out" validate:"required,gtfield=CheckIn"`
+}
+
+type PayBookingRequest struct {
+	PaymentMethod string `json:"payment_method" validate:"required,oneof=VIRTUAL_ACCOUNT CREDIT_CARD E_WALLET BANK_TRANSFER"`
+}
@@ -1,15 +1,27 @@
 package request
 
 type CreateHotelRequest struct {
-	Name        string `json:"name" validate:"required,min=3"`
-	Location    string `json:"location" validate:"required"`
-	Description string `json:"description"`
+	Name        string   `json:"name" validate:"required,min=3"`
+	Location    string   `json:"location" validate:"required"`
+	Address     string   `json:"address" validate:"omitempty,max=255"`
+	City        string   `json:"city" validate:"omitempty,max=100"`
+	Country     string   `json:"country" validate:"omitempty,max=100"`
+	Latitude    *float64 `json:"latitude" validate:"required_with=Longitude,omitempty,latitude"`
+	Longitude   *float64 `json:"longitude" validate:"required_with=Latitude,omitempty,longitude"`
+	Description string   `json:"description"`
 }
 
+// UpdateHotelRequest is also the target of hotel merge patches, where null
+// clears an optional field.
 type UpdateHotelRequest struct {
-	Name        string `json:"name" validate:"required,min=3"`
-	Location    string `json:"location" validate:"required"`
-	Description string `json:"description"`
+	Name        string   `json:"name" validate:"required,min=3"`
+	Location    string   `json:"location" validate:"required"`
+	Address     string   `json:"address" validate:"omitempty,max=255"`
+	City        string   `json:"city" validate:"omitempty,max=100"`
+	Country     string   `json:"country" validate:"omitempty,max=100"`
+	Latitude    *float64 `json:"latitude" validate:"required_with=Longitude,omitempty,latitude"`
+	Longitude   *float64 `json:"longitude" validate:"required_with=Latitude,omitempty,longitude"`
+	Description string   `json:"description"`
 }
 
 type CreateRoomRequest struct {
@@ -17,10 +29,35 @@ type CreateRoomRequest struct {
 	RoomType      string  `json:"room_type" validate:"required"`
 	PricePerNight float64 `json:"price_per_night" validate:"required,gt=0"`
 	Availability  int     `json:"availability" validate:"required,gte=0"`
+	Capacity      int     `json:"capacity" validate:"omitempty,gte=1,lte=20"`
 }
 
+// UpdateRoomRequest is also the target of room merge patches. Availability
+// is a pointer so that zero, a sold-out room, still counts as given.
 type UpdateRoomRequest struct {
 	RoomType      string  `json:"room_type" validate:"required"`
 	PricePerNight float64 `json:"price_per_night" validate:"required,gt=0"`
-	Availability  int     `json:"availability" validate:"required,gte=0"`
+	Availability  *int    `json:"availability" validate:"required,gte=0"`
+	Capacity      int     `json:"capacity" validate:"omitempty,gte=1,lte=20"`
+}
+
+type SearchHotelsRequest struct {
+	Q           string  `query:"q" validate:"omitempty,max=100"`
+	Destination string  `query:"destination" validate:"omitempty,max=100"`
+	CheckIn     string  `query:"check_in" validate:"required_with=CheckOut,omitempty,datetime=2006-01-02"`
+	CheckOut    string  `query:"check_out" validate:"required_with=CheckIn,omitempty,datetime=2006-01-02"`
+	Guests      int     `query:"guests" validate:"omitempty,gte=1,lte=20"`
+	MinPrice    float64 `query:"min_price" validate:"omitempty,gte=0"`
+	MaxPrice    float64 `query:"max_price" validate:"omitempty,gte=0"`
+
+	// Radius mode: hotels within radius_km of (lat, lng).
+	Latitude  *float64 `query:"lat" validate:"required_with=Longitude,omitempty,latitude"`
+	Longitude *float64 `query:"lng" validate:"required_with=Latitude,omitempty,longitude"`
+	RadiusKm  float64  `query:"radius_km" validate:"omitempty,gt=0,lte=500"`
+
+	// Bounding-box mode: hotels inside the box. min_lng > max_lng wraps the antimeridian.
+	MinLat *float64 `query:"min_lat" validate:"required_with=MaxLat MinLng MaxLng,omitempty,latitude"`
+	MinLng *float64 `query:"min_lng" validate:"required_with=MinLat MaxLat MaxLng,omitempty,longitude"`
+	MaxLat *float64 `query:"max_lat" validate:"required_with=MinLat MinLng MaxLng,omitempty,latitude,gtefield=MinLat"`
+	MaxLng *float64 `query:"max_lng" validate:"required_with=MinLat MinLng MaxLat,omitempty,longitude"`
 }
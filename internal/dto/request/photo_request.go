@@ -0,0 +1,5 @@
+package request
+
+type ReorderPhotosRequest struct {
+	PhotoIDs []string `json:"photo_ids" validate:"required,min=1,dive,uuid"`
+}
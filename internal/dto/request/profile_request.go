@@ -0,0 +1,22 @@
+package request
+
+type UpdateProfileRequest struct {
+	Name              string `json:"name" validate:"required,min=3,max=100"`
+	Phone             string `json:"phone" validate:"omitempty,min=6,max=30"`
+	PreferredCurrency string `json:"preferred_currency" validate:"omitempty,iso4217"`
+	PreferredLanguage string `json:"preferred_language" validate:"omitempty,bcp47_language_tag,max=35"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	Password        string `json:"password" validate:"required,min=6"`
+}
+
+type ChangeEmailRequest struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+}
+
+type DeleteAccountRequest struct {
+	Password string `json:"password" validate:"required"`
+}
@@ -0,0 +1,18 @@
+package request
+
+type CreateReviewRequest struct {
+	Rating      int    `json:"rating" validate:"required,min=1,max=5"`
+	Cleanliness int    `json:"cleanliness" validate:"required,min=1,max=5"`
+	Location    int    `json:"location" validate:"required,min=1,max=5"`
+	Service     int    `json:"service" validate:"required,min=1,max=5"`
+	Comment     string `json:"comment" validate:"omitempty,max=2000"`
+}
+
+type ReplyReviewRequest struct {
+	Reply string `json:"reply" validate:"required,max=2000"`
+}
+
+type ModerateReviewRequest struct {
+	Status string `json:"status" validate:"required,oneof=VISIBLE HIDDEN FLAGGED"`
+	Note   string `json:"note" validate:"omitempty,max=500"`
+}
@@ -0,0 +1,11 @@
+package request
+
+type RoleRequest struct {
+	Name        string   `json:"name" validate:"required,max=50"`
+	Description string   `json:"description" validate:"omitempty,max=255"`
+	Permissions []string `json:"permissions" validate:"required,min=1,dive,required,max=100"`
+}
+
+type SetUserRolesRequest struct {
+	RoleIDs []string `json:"role_ids" validate:"dive,uuid"`
+}
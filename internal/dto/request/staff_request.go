@@ -0,0 +1,5 @@
+package request
+
+type AssignStaffRequest struct {
+	Role string `json:"role" validate:"required,oneof=HOTEL_MANAGER STAFF"`
+}
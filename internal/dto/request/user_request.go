@@ -0,0 +1,13 @@
+package request
+
+type CreateUserRequest struct {
+	Name     string `json:"name" validate:"required,min=3"`
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required,min=6"`
+	Role     string `json:"role" validate:"required,oneof=CUSTOMER STAFF HOTEL_MANAGER ADMIN"`
+	HotelID  string `json:"hotel_id" validate:"required_if=Role STAFF,required_if=Role HOTEL_MANAGER,omitempty,uuid4"`
+}
+
+type ChangeUserRoleRequest struct {
+	Role string `json:"role" validate:"required,oneof=CUSTOMER ADMIN"`
+}
@@ -0,0 +1,42 @@
+package response
+
+import (
+	"hotel-booking-api/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+type AmenityResponse struct {
+	ID           uuid.UUID         `json:"id"`
+	Code         string            `json:"code"`
+	Name         string            `json:"name"`
+	Category     string            `json:"category"`
+	Icon         string            `json:"icon,omitempty"`
+	Translations map[string]string `json:"translations,omitempty"`
+}
+
+// ToAmenityResponse renders an amenity, using the lang translation for Name
+// when one exists.
+func ToAmenityResponse(amenity *domain.Amenity, lang string) AmenityResponse {
+	return AmenityResponse{
+		ID:           amenity.ID,
+		Code:         amenity.Code,
+		Name:         amenity.LocalizedName(lang),
+		Category:     amenity.Category,
+		Icon:         amenity.Icon,
+		Translations: amenity.Translations,
+	}
+}
+
+func ToAmenityResponses(amenities []domain.Amenity, lang string) []AmenityResponse {
+	if len(amenities) == 0 {
+		return nil
+	}
+
+	resp := make([]AmenityResponse, len(amenities))
+	for i, amenity := range amenities {
+		resp[i] = ToAmenityResponse(&amenity, lang)
+	}
+
+	return resp
+}
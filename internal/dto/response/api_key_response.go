@@ -0,0 +1,43 @@
+package response
+
+import (
+	"hotel-booking-api/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type APIKeyResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	Name        string     `json:"name"`
+	Prefix      string     `json:"prefix"`
+	Permissions []string   `json:"permissions"`
+	HotelIDs    []string   `json:"hotel_ids"`
+	CreatedByID uuid.UUID  `json:"created_by_id"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	LastUsedAt  *time.Time `json:"last_used_at"`
+	RevokedAt   *time.Time `json:"revoked_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// CreatedAPIKeyResponse carries the full key, which is only ever returned
+// when the key is created.
+type CreatedAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+func ToAPIKeyResponse(key *domain.APIKey) APIKeyResponse {
+	return APIKeyResponse{
+		ID:          key.ID,
+		Name:        key.Name,
+		Prefix:      key.Prefix,
+		Permissions: key.PermissionCodes(),
+		HotelIDs:    key.HotelIDs(),
+		CreatedByID: key.CreatedByID,
+		ExpiresAt:   key.ExpiresAt,
+		LastUsedAt:  key.LastUsedAt,
+		RevokedAt:   key.RevokedAt,
+		CreatedAt:   key.CreatedAt,
+	}
+}
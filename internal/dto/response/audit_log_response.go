@@ -0,0 +1,52 @@
+package response
+
+import (
+	"hotel-booking-api/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuditLogResponse struct {
+	ID         uuid.UUID                     `json:"id"`
+	ActorID    *uuid.UUID                    `json:"actor_id"`
+	ActorRole  string                        `json:"actor_role,omitempty"`
+	APIKeyID   *uuid.UUID                    `json:"api_key_id,omitempty"`
+	Action     string                        `json:"action"`
+	EntityType string                        `json:"entity_type"`
+	EntityID   string                        `json:"entity_id"`
+	Changes    map[string]domain.AuditChange `json:"changes"`
+	RequestID  string                        `json:"request_id,omitempty"`
+	IP         string                        `json:"ip,omitempty"`
+	CreatedAt  time.Time                     `json:"created_at"`
+}
+
+func ToAuditLogResponse(entry *domain.AuditLog) AuditLogResponse {
+	changes := entry.Changes
+	if changes == nil {
+		changes = map[string]domain.AuditChange{}
+	}
+
+	return AuditLogResponse{
+		ID:         entry.ID,
+		ActorID:    entry.ActorID,
+		ActorRole:  entry.ActorRole,
+		APIKeyID:   entry.APIKeyID,
+		Action:     entry.Action,
+		EntityType: entry.EntityType,
+		EntityID:   entry.EntityID,
+		Changes:    changes,
+		RequestID:  entry.RequestID,
+		IP:         entry.IP,
+		CreatedAt:  entry.CreatedAt,
+	}
+}
+
+func ToAuditLogResponses(entries []domain.AuditLog) []AuditLogResponse {
+	responses := make([]AuditLogResponse, len(entries))
+	for i := range entries {
+		responses[i] = ToAuditLogResponse(&entries[i])
+	}
+
+	return responses
+}
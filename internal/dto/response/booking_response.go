@@ -9,16 +9,33 @@ import (
 
 type BookingResponse struct {
 	ID         uuid.UUID        `json:"id"`
-	UserID     uuid.UUID        `json:"user_id"`
+	Reference  string           `json:"reference"`
+	UserID     *uuid.UUID       `json:"user_id,omitempty"`
+	Guest      *GuestResponse   `json:"guest,omitempty"`
 	Room       RoomResponse     `json:"room"`
 	CheckIn    time.Time        `json:"check_in"`
 	CheckOut   time.Time        `json:"check_out"`
 	TotalPrice float64          `json:"total_price"`
 	Status     string           `json:"status"`
 	Payment    *PaymentResponse `json:"payment,omitempty"`
+	Version    int              `json:"version"`
 	CreatedAt  time.Time        `json:"created_at"`
 }
 
+type GuestResponse struct {
+	ID    uuid.UUID `json:"id"`
+	Name  string    `json:"name"`
+	Email string    `json:"email"`
+	Phone string    `json:"phone"`
+}
+
+type GuestBookingResponse struct {
+	Booking         BookingResponse `json:"booking"`
+	ManageToken     string          `json:"manage_token"`
+	ManageURL       string          `json:"manage_url"`
+	ManageExpiresAt time.Time       `json:"manage_expires_at"`
+}
+
 type PaymentResponse struct {
 	ID            uuid.UUID `json:"id"`
 	Amount        float64   `json:"amount"`
@@ -31,15 +48,30 @@ type PaymentResponse struct {
 func ToBookingResponse(booking *domain.Booking) BookingResponse {
 	resp := BookingResponse{
 		ID:         booking.ID,
+		Reference:  booking.Reference,
 		UserID:     booking.UserID,
 		Room:       ToRoomResponse(&booking.Room),
 		CheckIn:    booking.CheckIn,
 		CheckOut:   booking.CheckOut,
 		TotalPrice: booking.TotalPrice,
 		Status:     booking.Status,
+		Version:    booking.Version,
 		CreatedAt:  booking.CreatedAt,
 	}
 
+	if booking.Guest != nil {
+		resp.Guest = &GuestResponse{
+			ID:    booking.Guest.ID,
+			Name:  booking.GuestName,
+			Email: booking.Guest.Email,
+			Phone: booking.GuestPhone,
+		}
+		// Bookings made before contact details were kept per booking
+		if resp.Guest.Name == "" {
+			resp.Guest.Name, resp.Guest.Phone = booking.Guest.Name, booking.Guest.Phone
+		}
+	}
+
 	if booking.Payment != nil {
 		payment := ToPaymentResponse(booking.Payment)
 		resp.Payment = &payment
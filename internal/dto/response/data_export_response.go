@@ -0,0 +1,43 @@
+package response
+
+import (
+	"hotel-booking-api/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DataExportResponse struct {
+	ID            uuid.UUID  `json:"id"`
+	UserID        uuid.UUID  `json:"user_id"`
+	RequestedByID uuid.UUID  `json:"requested_by_id"`
+	Status        string     `json:"status"`
+	Size          int64      `json:"size"`
+	Error         string     `json:"error,omitempty"`
+	CompletedAt   *time.Time `json:"completed_at"`
+	ExpiresAt     *time.Time `json:"expires_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+func ToDataExportResponse(export *domain.DataExport) DataExportResponse {
+	return DataExportResponse{
+		ID:            export.ID,
+		UserID:        export.UserID,
+		RequestedByID: export.RequestedByID,
+		Status:        export.Status,
+		Size:          export.Size,
+		Error:         export.Error,
+		CompletedAt:   export.CompletedAt,
+		ExpiresAt:     export.ExpiresAt,
+		CreatedAt:     export.CreatedAt,
+	}
+}
+
+func ToDataExportResponses(exports []domain.DataExport) []DataExportResponse {
+	responses := make([]DataExportResponse, len(exports))
+	for i := range exports {
+		responses[i] = ToDataExportResponse(&exports[i])
+	}
+
+	return responses
+}
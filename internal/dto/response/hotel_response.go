@@ -5,25 +5,69 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type HotelResponse struct {
-	ID          uuid.UUID      `json:"id"`
-	Name        string         `json:"name"`
-	Location    string         `json:"location"`
-	Description string         `json:"description"`
-	Rooms       []RoomResponse `json:"rooms,omitempty"`
-	CreatedAt   time.Time      `json:"created_at"`
+	ID            uuid.UUID         `json:"id"`
+	Name          string            `json:"name"`
+	Location      string            `json:"location"`
+	Address       string            `json:"address,omitempty"`
+	City          string            `json:"city,omitempty"`
+	Country       string            `json:"country,omitempty"`
+	Latitude      *float64          `json:"latitude,omitempty"`
+	Longitude     *float64          `json:"longitude,omitempty"`
+	Description   string            `json:"description"`
+	AverageRating float64           `json:"average_rating"`
+	ReviewCount   int               `json:"review_count"`
+	Amenities     []AmenityResponse `json:"amenities,omitempty"`
+	CoverPhoto    *PhotoResponse    `json:"cover_photo,omitempty"`
+	Photos        []PhotoResponse   `json:"photos,omitempty"`
+	Rooms         []RoomResponse    `json:"rooms,omitempty"`
+	Version       int               `json:"version"`
+	CreatedAt     time.Time         `json:"created_at"`
+	DeletedAt     *time.Time        `json:"deleted_at,omitempty"`
 }
 
 type RoomResponse struct {
-	ID            uuid.UUID     `json:"id"`
-	HotelID       uuid.UUID     `json:"hotel_id"`
-	Hotel         *HotelSummary `json:"hotel,omitempty"`
-	RoomType      string        `json:"room_type"`
-	PricePerNight float64       `json:"price_per_night"`
-	Availability  int           `json:"availability"`
-	CreatedAt     time.Time     `json:"created_at"`
+	ID            uuid.UUID         `json:"id"`
+	HotelID       uuid.UUID         `json:"hotel_id"`
+	Hotel         *HotelSummary     `json:"hotel,omitempty"`
+	RoomType      string            `json:"room_type"`
+	PricePerNight float64           `json:"price_per_night"`
+	Availability  int               `json:"availability"`
+	Capacity      int               `json:"capacity"`
+	Amenities     []AmenityResponse `json:"amenities,omitempty"`
+	Photos        []PhotoResponse   `json:"photos,omitempty"`
+	Version       int               `json:"version"`
+	CreatedAt     time.Time         `json:"created_at"`
+	DeletedAt     *time.Time        `json:"deleted_at,omitempty"`
+}
+
+type HotelSearchResponse struct {
+	HotelResponse
+	LowestPrice    float64          `json:"lowest_price"`
+	AvailableRooms int              `json:"available_rooms"`
+	DistanceKm     *float64         `json:"distance_km,omitempty"`
+	Relevance      *float64         `json:"relevance,omitempty"`
+	Highlight      *SearchHighlight `json:"highlight,omitempty"`
+}
+
+// SearchHighlight holds fragments with matched terms wrapped in <mark> tags.
+type SearchHighlight struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+type SearchSuggestionResponse struct {
+	Type    string     `json:"type"`
+	HotelID *uuid.UUID `json:"hotel_id,omitempty"`
+	Text    string     `json:"text"`
+}
+
+// PatchMeta reports the JSON names of the fields a merge patch changed.
+type PatchMeta struct {
+	Changed []string `json:"changed"`
 }
 
 type HotelSummary struct {
@@ -34,22 +78,38 @@ type HotelSummary struct {
 
 func ToHotelResponse(hotel *domain.Hotel) HotelResponse {
 	resp := HotelResponse{
-		ID:          hotel.ID,
-		Name:        hotel.Name,
-		Location:    hotel.Location,
-		Description: hotel.Description,
-		CreatedAt:   hotel.CreatedAt,
+		ID:            hotel.ID,
+		Name:          hotel.Name,
+		Location:      hotel.Location,
+		Address:       hotel.Address,
+		City:          hotel.City,
+		Country:       hotel.Country,
+		Latitude:      hotel.Latitude,
+		Longitude:     hotel.Longitude,
+		Description:   hotel.Description,
+		AverageRating: hotel.AverageRating,
+		ReviewCount:   hotel.ReviewCount,
+		Amenities:     ToAmenityResponses(hotel.Amenities, ""),
+		CoverPhoto:    coverPhoto(hotel.Photos),
+		Photos:        ToPhotoResponses(hotel.Photos),
+		Version:       hotel.Version,
+		CreatedAt:     hotel.CreatedAt,
+		DeletedAt:     deletedAt(hotel.DeletedAt),
 	}
 
-	if len(hotel.Room) > 0 {
-		rooms := make([]RoomResponse, len(hotel.Room))
-		for i, room := range hotel.Room {
+	if len(hotel.Rooms) > 0 {
+		rooms := make([]RoomResponse, len(hotel.Rooms))
+		for i, room := range hotel.Rooms {
 			rooms[i] = RoomResponse{
 				ID:            room.ID,
 				HotelID:       room.HotelID,
 				RoomType:      room.RoomType,
 				PricePerNight: room.PricePerNight,
 				Availability:  room.Availability,
+				Capacity:      room.Capacity,
+				Amenities:     ToAmenityResponses(room.Amenities, ""),
+				Photos:        ToPhotoResponses(room.Photos),
+				Version:       room.Version,
 				CreatedAt:     room.CreatedAt,
 			}
 		}
@@ -59,6 +119,33 @@ func ToHotelResponse(hotel *domain.Hotel) HotelResponse {
 	return resp
 }
 
+func ToHotelSearchResponse(result *domain.HotelSearchResult) HotelSearchResponse {
+	resp := HotelSearchResponse{
+		HotelResponse:  ToHotelResponse(&result.Hotel),
+		LowestPrice:    result.LowestPrice,
+		AvailableRooms: result.AvailableRooms,
+		DistanceKm:     result.DistanceKm,
+		Relevance:      result.Relevance,
+	}
+
+	if result.NameHighlight != "" {
+		resp.Highlight = &SearchHighlight{
+			Name:        result.NameHighlight,
+			Description: result.DescriptionSnippet,
+		}
+	}
+
+	return resp
+}
+
+func ToSearchSuggestionResponse(suggestion *domain.SearchSuggestion) SearchSuggestionResponse {
+	return SearchSuggestionResponse{
+		Type:    suggestion.Type,
+		HotelID: suggestion.HotelID,
+		Text:    suggestion.Text,
+	}
+}
+
 func ToRoomResponse(room *domain.Room) RoomResponse {
 	resp := RoomResponse{
 		ID:            room.ID,
@@ -66,7 +153,12 @@ func ToRoomResponse(room *domain.Room) RoomResponse {
 		RoomType:      room.RoomType,
 		PricePerNight: room.PricePerNight,
 		Availability:  room.Availability,
+		Capacity:      room.Capacity,
+		Amenities:     ToAmenityResponses(room.Amenities, ""),
+		Photos:        ToPhotoResponses(room.Photos),
+		Version:       room.Version,
 		CreatedAt:     room.CreatedAt,
+		DeletedAt:     deletedAt(room.DeletedAt),
 	}
 
 	if room.Hotel.ID != uuid.Nil {
@@ -79,3 +171,11 @@ func ToRoomResponse(room *domain.Room) RoomResponse {
 
 	return resp
 }
+
+func deletedAt(at gorm.DeletedAt) *time.Time {
+	if !at.Valid {
+		return nil
+	}
+
+	return &at.Time
+}
@@ -0,0 +1,54 @@
+package response
+
+import (
+	"hotel-booking-api/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+type PhotoResponse struct {
+	ID           uuid.UUID `json:"id"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+	Width        int       `json:"width"`
+	Height       int       `json:"height"`
+	Position     int       `json:"position"`
+	IsCover      bool      `json:"is_cover"`
+}
+
+func ToPhotoResponse(photo *domain.Photo) PhotoResponse {
+	return PhotoResponse{
+		ID:           photo.ID,
+		URL:          photo.URL,
+		ThumbnailURL: photo.ThumbnailURL,
+		Width:        photo.Width,
+		Height:       photo.Height,
+		Position:     photo.Position,
+		IsCover:      photo.IsCover,
+	}
+}
+
+func ToPhotoResponses(photos []domain.Photo) []PhotoResponse {
+	if len(photos) == 0 {
+		return nil
+	}
+
+	resp := make([]PhotoResponse, len(photos))
+	for i := range photos {
+		resp[i] = ToPhotoResponse(&photos[i])
+	}
+
+	return resp
+}
+
+// coverPhoto returns the cover among photos, if one has been loaded.
+func coverPhoto(photos []domain.Photo) *PhotoResponse {
+	for i := range photos {
+		if photos[i].IsCover {
+			resp := ToPhotoResponse(&photos[i])
+			return &resp
+		}
+	}
+
+	return nil
+}
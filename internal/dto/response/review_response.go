@@ -0,0 +1,57 @@
+package response
+
+import (
+	"hotel-booking-api/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ReviewResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	HotelID     uuid.UUID  `json:"hotel_id"`
+	Reviewer    string     `json:"reviewer"`
+	Rating      int        `json:"rating"`
+	Cleanliness int        `json:"cleanliness"`
+	Location    int        `json:"location"`
+	Service     int        `json:"service"`
+	Comment     string     `json:"comment"`
+	Reply       string     `json:"reply,omitempty"`
+	RepliedAt   *time.Time `json:"replied_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// AdminReviewResponse adds moderation details hidden from the public list.
+type AdminReviewResponse struct {
+	ReviewResponse
+	BookingID      uuid.UUID `json:"booking_id"`
+	UserID         uuid.UUID `json:"user_id"`
+	Status         string    `json:"status"`
+	ModerationNote string    `json:"moderation_note,omitempty"`
+}
+
+func ToReviewResponse(review *domain.Review) ReviewResponse {
+	return ReviewResponse{
+		ID:          review.ID,
+		HotelID:     review.HotelID,
+		Reviewer:    review.User.Name,
+		Rating:      review.Rating,
+		Cleanliness: review.Cleanliness,
+		Location:    review.Location,
+		Service:     review.Service,
+		Comment:     review.Comment,
+		Reply:       review.Reply,
+		RepliedAt:   review.RepliedAt,
+		CreatedAt:   review.CreatedAt,
+	}
+}
+
+func ToAdminReviewResponse(review *domain.Review) AdminReviewResponse {
+	return AdminReviewResponse{
+		ReviewResponse: ToReviewResponse(review),
+		BookingID:      review.BookingID,
+		UserID:         review.UserID,
+		Status:         review.Status,
+		ModerationNote: review.ModerationNote,
+	}
+}
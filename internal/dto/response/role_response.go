@@ -0,0 +1,67 @@
+package response
+
+import (
+	"hotel-booking-api/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+type PermissionResponse struct {
+	Code        string `json:"code"`
+	Description string `json:"description"`
+}
+
+type RoleResponse struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	IsSystem    bool      `json:"is_system"`
+	Permissions []string  `json:"permissions"`
+}
+
+type UserAccessResponse struct {
+	UserID      uuid.UUID      `json:"user_id"`
+	Role        string         `json:"role"`
+	Roles       []RoleResponse `json:"roles"`
+	Permissions []string       `json:"permissions"`
+}
+
+func ToPermissionResponse(permission *domain.Permission) PermissionResponse {
+	return PermissionResponse{
+		Code:        permission.Code,
+		Description: permission.Description,
+	}
+}
+
+func ToRoleResponse(role *domain.Role) RoleResponse {
+	permissions := make([]string, len(role.Permissions))
+	for i, permission := range role.Permissions {
+		permissions[i] = permission.Code
+	}
+
+	return RoleResponse{
+		ID:          role.ID,
+		Name:        role.Name,
+		Description: role.Description,
+		IsSystem:    role.IsSystem,
+		Permissions: permissions,
+	}
+}
+
+func ToUserAccessResponse(user *domain.User, customRoles []domain.Role, permissions []string) UserAccessResponse {
+	roles := make([]RoleResponse, len(customRoles))
+	for i := range customRoles {
+		roles[i] = ToRoleResponse(&customRoles[i])
+	}
+
+	if permissions == nil {
+		permissions = []string{}
+	}
+
+	return UserAccessResponse{
+		UserID:      user.ID,
+		Role:        user.Role,
+		Roles:       roles,
+		Permissions: permissions,
+	}
+}
@@ -0,0 +1,28 @@
+package response
+
+import (
+	"hotel-booking-api/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type StaffResponse struct {
+	HotelID    uuid.UUID `json:"hotel_id"`
+	UserID     uuid.UUID `json:"user_id"`
+	Name       string    `json:"name"`
+	Email      string    `json:"email"`
+	Role       string    `json:"role"`
+	AssignedAt time.Time `json:"assigned_at"`
+}
+
+func ToStaffResponse(staff *domain.HotelStaff) StaffResponse {
+	return StaffResponse{
+		HotelID:    staff.HotelID,
+		UserID:     staff.UserID,
+		Name:       staff.User.Name,
+		Email:      staff.User.Email,
+		Role:       staff.Role,
+		AssignedAt: staff.CreatedAt,
+	}
+}
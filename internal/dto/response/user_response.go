@@ -0,0 +1,45 @@
+package response
+
+import (
+	"hotel-booking-api/internal/domain"
+	"time"
+)
+
+// AdminUserResponse is the back-office view of an account.
+type AdminUserResponse struct {
+	UserResponse
+	DeactivatedAt *time.Time `json:"deactivated_at"`
+	AnonymizedAt  *time.Time `json:"anonymized_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// ProfileResponse is a user's view of their own account. PendingEmail is the
+// address they asked to move to and have not confirmed yet.
+type ProfileResponse struct {
+	UserResponse
+	Phone             string    `json:"phone"`
+	PreferredCurrency string    `json:"preferred_currency"`
+	PreferredLanguage string    `json:"preferred_language"`
+	PendingEmail      string    `json:"pending_email,omitempty"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+func ToProfileResponse(user *domain.User) ProfileResponse {
+	return ProfileResponse{
+		UserResponse:      ToUserResponse(user),
+		Phone:             user.Phone,
+		PreferredCurrency: user.PreferredCurrency,
+		PreferredLanguage: user.PreferredLanguage,
+		PendingEmail:      user.PendingEmail,
+		UpdatedAt:         user.UpdatedAt,
+	}
+}
+
+func ToAdminUserResponse(user *domain.User) AdminUserResponse {
+	return AdminUserResponse{
+		UserResponse:  ToUserResponse(user),
+		DeactivatedAt: user.DeactivatedAt,
+		AnonymizedAt:  user.AnonymizedAt,
+		UpdatedAt:     user.UpdatedAt,
+	}
+}
@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"hotel-booking-api/internal/service"
+
+	"github.com/labstack/echo/v4"
+)
+
+// actorFromContext returns the caller identified by AuthMiddleware, with the
+// permissions resolved by RequirePermission. On public routes it identifies
+// only the request.
+func actorFromContext(c echo.Context) service.Actor {
+	userID, _ := c.Get("userID").(string)
+	role, _ := c.Get("role").(string)
+	permissions, _ := c.Get("permissions").([]string)
+	apiKeyID, _ := c.Get("apiKeyID").(string)
+	hotelIDs, _ := c.Get("apiKeyHotels").([]string)
+	requestID, _ := c.Get("requestID").(string)
+
+	return service.Actor{
+		UserID:      userID,
+		Role:        role,
+		Permissions: permissions,
+		APIKeyID:    apiKeyID,
+		HotelIDs:    hotelIDs,
+		RequestID:   requestID,
+		IP:          c.RealIP(),
+	}
+}
@@ -0,0 +1,245 @@
+package handler
+
+import (
+	"hotel-booking-api/internal/domain"
+	"hotel-booking-api/internal/dto/request"
+	dto "hotel-booking-api/internal/dto/response"
+	"hotel-booking-api/internal/service"
+	"hotel-booking-api/pkg/jsonres"
+	"hotel-booking-api/pkg/validator"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type AmenityHandler struct {
+	amenityService service.AmenityService
+}
+
+func NewAmenityHandler(amenityService service.AmenityService) *AmenityHandler {
+	return &AmenityHandler{
+		amenityService: amenityService,
+	}
+}
+
+// ListAmenities godoc
+// @Summary List amenities
+// @Description Get the amenity catalogue, optionally by category and localised to a language
+// @Tags amenities
+// @Accept json
+// @Produce json
+// @Param category query string false "Amenity category" Enums(GENERAL, ROOM, BATHROOM, FOOD, BED, VIEW, ACCESSIBILITY)
+// @Param lang query string false "Language code for names, e.g. id"
+// @Success 200 {object} jsonres.SuccessResponse{data=[]response.AmenityResponse}
+// @Failure 500 {object} jsonres.ErrorResponse
+// @Router /amenities [get]
+func (h *AmenityHandler) ListAmenities(c echo.Context) error {
+	amenities, err := h.amenityService.ListAmenities(c.QueryParam("category"))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, jsonres.Error(
+			"FETCH_FAILED", "Failed to fetch amenities", err.Error(),
+		))
+	}
+
+	amenityResponses := dto.ToAmenityResponses(amenities, c.QueryParam("lang"))
+	if amenityResponses == nil {
+		amenityResponses = []dto.AmenityResponse{}
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success(
+		"Amenities retrieved successfully", amenityResponses,
+	))
+}
+
+// CreateAmenity godoc
+// @Summary Create an amenity
+// @Description Add an amenity to the catalogue (Admin only)
+// @Tags amenities
+// @Accept json
+// @Produce json
+// @Param request body request.AmenityRequest true "Amenity details"
+// @Success 201 {object} jsonres.SuccessResponse{data=response.AmenityResponse}
+// @Failure 400 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /amenities [post]
+func (h *AmenityHandler) CreateAmenity(c echo.Context) error {
+	var req request.AmenityRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"BAD_REQUEST", "Invalid request body", err.Error(),
+		))
+	}
+
+	if errs := validator.Validate(&req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"VALIDATION_ERROR", "Validation failed", errs,
+		))
+	}
+
+	amenity := &domain.Amenity{
+		Code:         req.Code,
+		Name:         req.Name,
+		Category:     req.Category,
+		Icon:         req.Icon,
+		Translations: req.Translations,
+	}
+
+	if err := h.amenityService.CreateAmenity(amenity); err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"CREATE_FAILED", err.Error(), nil,
+		))
+	}
+
+	return c.JSON(http.StatusCreated, jsonres.Success(
+		"Amenity created successfully", dto.ToAmenityResponse(amenity, ""),
+	))
+}
+
+// UpdateAmenity godoc
+// @Summary Update an amenity
+// @Description Update an amenity in the catalogue (Admin only)
+// @Tags amenities
+// @Accept json
+// @Produce json
+// @Param id path string true "Amenity ID"
+// @Param request body request.AmenityRequest true "Amenity details"
+// @Success 200 {object} jsonres.SuccessResponse{data=response.AmenityResponse}
+// @Failure 400 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /amenities/{id} [put]
+func (h *AmenityHandler) UpdateAmenity(c echo.Context) error {
+	var req request.AmenityRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"BAD_REQUEST", "Invalid request body", err.Error(),
+		))
+	}
+
+	if errs := validator.Validate(&req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"VALIDATION_ERROR", "Validation failed", errs,
+		))
+	}
+
+	amenity, err := h.amenityService.GetAmenity(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, jsonres.Error(
+			"NOT_FOUND", "Amenity not found", nil,
+		))
+	}
+
+	amenity.Code = req.Code
+	amenity.Name = req.Name
+	amenity.Category = req.Category
+	amenity.Icon = req.Icon
+	amenity.Translations = req.Translations
+
+	if err := h.amenityService.UpdateAmenity(amenity); err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"UPDATE_FAILED", err.Error(), nil,
+		))
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success(
+		"Amenity updated successfully", dto.ToAmenityResponse(amenity, ""),
+	))
+}
+
+// DeleteAmenity godoc
+// @Summary Delete an amenity
+// @Description Remove an amenity from the catalogue and from every hotel and room (Admin only)
+// @Tags amenities
+// @Accept json
+// @Produce json
+// @Param id path string true "Amenity ID"
+// @Success 200 {object} jsonres.SuccessResponse
+// @Failure 404 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /amenities/{id} [delete]
+func (h *AmenityHandler) DeleteAmenity(c echo.Context) error {
+	if err := h.amenityService.DeleteAmenity(c.Param("id")); err != nil {
+		return c.JSON(http.StatusNotFound, jsonres.Error(
+			"DELETE_FAILED", err.Error(), nil,
+		))
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success(
+		"Amenity deleted successfully", nil,
+	))
+}
+
+// SetHotelAmenities godoc
+// @Summary Set hotel amenities
+// @Description Replace the amenities offered by a hotel (Admin only)
+// @Tags amenities
+// @Accept json
+// @Produce json
+// @Param id path string true "Hotel ID"
+// @Param request body request.SetAmenitiesRequest true "Amenity codes"
+// @Success 200 {object} jsonres.SuccessResponse{data=[]response.AmenityResponse}
+// @Failure 400 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /hotels/{id}/amenities [put]
+func (h *AmenityHandler) SetHotelAmenities(c echo.Context) error {
+	var req request.SetAmenitiesRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"BAD_REQUEST", "Invalid request body", err.Error(),
+		))
+	}
+
+	if errs := validator.Validate(&req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"VALIDATION_ERROR", "Validation failed", errs,
+		))
+	}
+
+	amenities, err := h.amenityService.SetHotelAmenities(c.Param("id"), req.Codes)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"UPDATE_FAILED", err.Error(), nil,
+		))
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success(
+		"Hotel amenities updated successfully", dto.ToAmenityResponses(amenities, ""),
+	))
+}
+
+// SetRoomAmenities godoc
+// @Summary Set room amenities
+// @Description Replace the amenities offered by a room type (Admin only)
+// @Tags amenities
+// @Accept json
+// @Produce json
+// @Param id path string true "Room ID"
+// @Param request body request.SetAmenitiesRequest true "Amenity codes"
+// @Success 200 {object} jsonres.SuccessResponse{data=[]response.AmenityResponse}
+// @Failure 400 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /rooms/{id}/amenities [put]
+func (h *AmenityHandler) SetRoomAmenities(c echo.Context) error {
+	var req request.SetAmenitiesRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"BAD_REQUEST", "Invalid request body", err.Error(),
+		))
+	}
+
+	if errs := validator.Validate(&req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"VALIDATION_ERROR", "Validation failed", errs,
+		))
+	}
+
+	amenities, err := h.amenityService.SetRoomAmenities(c.Param("id"), req.Codes)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"UPDATE_FAILED", err.Error(), nil,
+		))
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success(
+		"Room amenities updated successfully", dto.ToAmenityResponses(amenities, ""),
+	))
+}
@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"errors"
+	"hotel-booking-api/internal/dto/request"
+	dto "hotel-booking-api/internal/dto/response"
+	"hotel-booking-api/internal/repository"
+	"hotel-booking-api/internal/service"
+	"hotel-booking-api/pkg/jsonres"
+	"hotel-booking-api/pkg/validator"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type APIKeyHandler struct {
+	apiKeyService service.APIKeyService
+}
+
+func NewAPIKeyHandler(apiKeyService service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+	}
+}
+
+// ListAPIKeys godoc
+// @Summary List API keys
+// @Description Get API keys with their permissions, hotels and last use; secrets are never returned
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param cursor query string false "Cursor from a previous page's meta.next_cursor"
+// @Param limit query int false "Page size (max 100)" default(20)
+// @Param sort query string false "Sort field" Enums(created_at, name)
+// @Param order query string false "Sort order" Enums(asc, desc)
+// @Success 200 {object} jsonres.SuccessResponse{data=[]response.APIKeyResponse,meta=pagination.Meta}
+// @Failure 400 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/api-keys [get]
+func (h *APIKeyHandler) ListAPIKeys(c echo.Context) error {
+	query, err := listQuery(c, repository.APIKeyListOptions)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"VALIDATION_ERROR", "Validation failed", []string{err.Error()},
+		))
+	}
+
+	keys, total, err := h.apiKeyService.ListAPIKeys(query)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, jsonres.Error(
+			"FETCH_FAILED", "Failed to fetch API keys", err.Error(),
+		))
+	}
+
+	keyResponses := make([]dto.APIKeyResponse, len(keys))
+	for i := range keys {
+		keyResponses[i] = dto.ToAPIKeyResponse(&keys[i])
+	}
+
+	return c.JSON(http.StatusOK, jsonres.SuccessWithMeta(
+		"API keys retrieved successfully", keyResponses, query.Meta(total, len(keys)),
+	))
+}
+
+// CreateAPIKey godoc
+// @Summary Create an API key
+// @Description Issue an API key with a subset of the caller's permissions, optionally limited to hotels and given an expiry. The key is only shown in this response; send it in the X-API-Key header.
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Param request body request.CreateAPIKeyRequest true "Key details"
+// @Success 201 {object} jsonres.SuccessResponse{data=response.CreatedAPIKeyResponse}
+// @Failure 400 {object} jsonres.ErrorResponse
+// @Failure 403 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/api-keys [post]
+func (h *APIKeyHandler) CreateAPIKey(c echo.Context) error {
+	var req request.CreateAPIKeyRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"BAD_REQUEST", "Invalid request body", err.Error(),
+		))
+	}
+
+	if errs := validator.Validate(&req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"VALIDATION_ERROR", "Validation failed", errs,
+		))
+	}
+
+	created, err := h.apiKeyService.CreateAPIKey(actorFromContext(c), service.NewAPIKeyInput{
+		Name:        req.Name,
+		Permissions: req.Permissions,
+		HotelIDs:    req.HotelIDs,
+		ExpiresAt:   req.ExpiresAt,
+	})
+	if errors.Is(err, service.ErrPermissionDenied) || errors.Is(err, service.ErrHotelForbidden) {
+		return c.JSON(http.StatusForbidden, jsonres.Error(
+			"FORBIDDEN", err.Error(), nil,
+		))
+	}
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"CREATE_FAILED", err.Error(), nil,
+		))
+	}
+
+	return c.JSON(http.StatusCreated, jsonres.Success(
+		"API key created successfully", dto.CreatedAPIKeyResponse{
+			APIKeyResponse: dto.ToAPIKeyResponse(created.Key),
+			Key:            created.Secret,
+		},
+	))
+}
+
+// RevokeAPIKey godoc
+// @Summary Revoke an API key
+// @Description Revoke an API key immediately
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Param id path string true "API key ID"
+// @Success 200 {object} jsonres.SuccessResponse
+// @Failure 404 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/api-keys/{id} [delete]
+func (h *APIKeyHandler) RevokeAPIKey(c echo.Context) error {
+	if err := h.apiKeyService.RevokeAPIKey(actorFromContext(c), c.Param("id")); err != nil {
+		return c.JSON(http.StatusNotFound, jsonres.Error(
+			"NOT_FOUND", err.Error(), nil,
+		))
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success(
+		"API key revoked successfully", nil,
+	))
+}
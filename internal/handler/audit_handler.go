@@ -0,0 +1,91 @@
+package handler
+
+import (
+	dto "hotel-booking-api/internal/dto/response"
+	"hotel-booking-api/internal/repository"
+	"hotel-booking-api/internal/service"
+	"hotel-booking-api/pkg/jsonres"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type AuditHandler struct {
+	auditService service.AuditService
+}
+
+func NewAuditHandler(auditService service.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// ListAuditLogs godoc
+// @Summary Search the audit log
+// @Description Get administrative and financial changes, newest first: who made them, from which request and IP, and the fields they changed
+// @Tags audit
+// @Accept json
+// @Produce json
+// @Param actor_id query string false "User who made the change"
+// @Param api_key_id query string false "API key that made the change"
+// @Param entity_type query string false "Changed entity type" Enums(hotel, room, booking, payment, user, role, api_key)
+// @Param entity_id query string false "Changed entity ID"
+// @Param action query string false "Action, e.g. hotel.delete"
+// @Param request_id query string false "Request ID from the X-Request-ID header"
+// @Param from query string false "Made on or after (YYYY-MM-DD or RFC 3339)"
+// @Param to query string false "Made on or before (YYYY-MM-DD or RFC 3339)"
+// @Param page query int false "Page number" default(1)
+// @Param cursor query string false "Cursor from a previous page's meta.next_cursor"
+// @Param limit query int false "Page size (max 100)" default(20)
+// @Param order query string false "Sort order" Enums(asc, desc)
+// @Success 200 {object} jsonres.SuccessResponse{data=[]response.AuditLogResponse,meta=pagination.Meta}
+// @Failure 400 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/audit-logs [get]
+func (h *AuditHandler) ListAuditLogs(c echo.Context) error {
+	filter := repository.AuditFilter{
+		ActorID:    c.QueryParam("actor_id"),
+		EntityType: c.QueryParam("entity_type"),
+		EntityID:   c.QueryParam("entity_id"),
+	}
+
+	var errs []string
+	query, err := listQuery(c, repository.AuditListOptions)
+	if err != nil {
+		errs = append(errs, err.Error())
+	}
+	filter.Query = query
+
+	for _, name := range []string{"actor_id", "api_key_id"} {
+		if raw := c.QueryParam(name); raw != "" {
+			if _, err := uuid.Parse(raw); err != nil {
+				errs = append(errs, name+" must be a UUID")
+			}
+		}
+	}
+
+	if filter.From, err = queryTime(c, "from"); err != nil {
+		errs = append(errs, err.Error())
+	}
+	if filter.To, err = queryTime(c, "to"); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	if len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"VALIDATION_ERROR", "Validation failed", errs,
+		))
+	}
+
+	entries, total, err := h.auditService.ListEntries(filter)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, jsonres.Error(
+			"FETCH_FAILED", "Failed to fetch audit log", err.Error(),
+		))
+	}
+
+	return c.JSON(http.StatusOK, jsonres.SuccessWithMeta(
+		"Audit log retrieved successfully", dto.ToAuditLogResponses(entries), filter.Query.Meta(total, len(entries)),
+	))
+}
@@ -1,30 +1,37 @@
 package handler
 
 import (
+	"errors"
 	"hotel-booking-api/internal/domain"
 	"hotel-booking-api/internal/dto/request"
 	dto "hotel-booking-api/internal/dto/response"
 	"hotel-booking-api/internal/service"
 	"hotel-booking-api/pkg/jsonres"
+	"hotel-booking-api/pkg/logger"
+	"hotel-booking-api/pkg/util"
 	"hotel-booking-api/pkg/validator"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/labstack/echo/v4"
 )
 
 type AuthHandler struct {
-	authService service.AuthService
+	authService    service.AuthService
+	accountService service.AccountService
 }
 
-func NewAuthHandler(authService service.AuthService) *AuthHandler {
+func NewAuthHandler(authService service.AuthService, accountService service.AccountService) *AuthHandler {
 	return &AuthHandler{
-		authService: authService,
+		authService:    authService,
+		accountService: accountService,
 	}
 }
 
 // Register godoc
 // @Summary Register a new user
-// @Description Register a new user account
+// @Description Register a new customer account and email a verification link
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -47,17 +54,18 @@ func (h *AuthHandler) Register(c echo.Context) error {
 		))
 	}
 
-	if req.Role == "" {
-		req.Role = domain.RoleCustomer
-	}
-
-	user, err := h.authService.Register(req.Name, req.Email, req.Password, req.Role)
+	user, err := h.authService.Register(req.Name, req.Email, req.Password)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, jsonres.Error(
 			"REGISTER_FAILED", err.Error(), nil,
 		))
 	}
 
+	// The account exists either way; the user can ask for another email.
+	if err := h.accountService.SendVerificationEmail(user); err != nil {
+		logger.Warn("Failed to send verification email", "user_id", user.ID, "error", err)
+	}
+
 	return c.JSON(http.StatusCreated, jsonres.Success(
 		"Registration successfull", dto.ToUserResponse(user),
 	))
@@ -70,8 +78,10 @@ func (h *AuthHandler) Register(c echo.Context) error {
 // @Accept json
 // @Produce json
 // @Param request body request.LoginRequest true "Login credentials"
-// @Success 200 {object} jsonres.SuccessResponse{data=response.LoginResponse}
+// @Success 200 {object} jsonres.SuccessResponse{data=response.LoginResponse} "Tokens, or a response.TwoFactorChallengeResponse when the account has two-factor authentication"
 // @Failure 401 {object} jsonres.ErrorResponse
+// @Failure 403 {object} jsonres.ErrorResponse
+// @Failure 429 {object} jsonres.ErrorResponse
 // @Router /auth/login [post]
 func (h *AuthHandler) Login(c echo.Context) error {
 	var req request.LoginRequest
@@ -88,15 +98,543 @@ func (h *AuthHandler) Login(c echo.Context) error {
 		))
 	}
 
-	token, user, err := h.authService.Login(req.Email, req.Password)
+	result, err := h.authService.Login(req.Email, req.Password, c.RealIP())
 	if err != nil {
+		return loginError(c, err)
+	}
+
+	return loginSuccess(c, result)
+}
+
+// Refresh godoc
+// @Summary Refresh access token
+// @Description Exchange a refresh token for a new access and refresh token. Each refresh token can be used once; reusing one revokes the session
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body request.RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} jsonres.SuccessResponse{data=response.LoginResponse}
+// @Failure 401 {object} jsonres.ErrorResponse
+// @Router /auth/refresh [post]
+func (h *AuthHandler) Refresh(c echo.Context) error {
+	var req request.RefreshTokenRequest
+
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"BAD_REQUEST", "Invalid request body", err.Error(),
+		))
+	}
+
+	if errs := validator.Validate(&req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"VALIDATION_ERROR", "Validation failed", errs,
+		))
+	}
+
+	tokens, user, err := h.authService.Refresh(req.RefreshToken)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidRefreshToken) || errors.Is(err, service.ErrRefreshTokenReused) {
+			return c.JSON(http.StatusUnauthorized, jsonres.Error(
+				"INVALID_REFRESH_TOKEN", err.Error(), nil,
+			))
+		}
+		return c.JSON(http.StatusInternalServerError, jsonres.Error(
+			"REFRESH_FAILED", "Failed to refresh token", nil,
+		))
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success("Token refreshed", toLoginResponse(tokens, user)))
+}
+
+// Logout godoc
+// @Summary Logout
+// @Description Revoke the current access token and the session of the given refresh token, or every session of the user
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body request.LogoutRequest false "Session to end"
+// @Success 200 {object} jsonres.SuccessResponse
+// @Failure 401 {object} jsonres.ErrorResponse
+// @Router /auth/logout [post]
+func (h *AuthHandler) Logout(c echo.Context) error {
+	var req request.LogoutRequest
+
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"BAD_REQUEST", "Invalid request body", err.Error(),
+		))
+	}
+
+	claims, _ := c.Get("claims").(*util.JWTClaims)
+	if err := h.authService.Logout(claims, req.RefreshToken, req.AllSessions); err != nil {
+		return c.JSON(http.StatusInternalServerError, jsonres.Error(
+			"LOGOUT_FAILED", "Failed to logout", nil,
+		))
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success("Logged out", nil))
+}
+
+// VerifyEmail godoc
+// @Summary Verify email address
+// @Description Confirm the email address of an account with the token from the verification email
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body request.VerifyEmailRequest true "Verification token"
+// @Success 200 {object} jsonres.SuccessResponse
+// @Failure 400 {object} jsonres.ErrorResponse
+// @Router /auth/verify-email [post]
+func (h *AuthHandler) VerifyEmail(c echo.Context) error {
+	var req request.VerifyEmailRequest
+
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"BAD_REQUEST", "Invalid request body", err.Error(),
+		))
+	}
+
+	if errs := validator.Validate(&req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"VALIDATION_ERROR", "Validation failed", errs,
+		))
+	}
+
+	if err := h.accountService.VerifyEmail(req.Token); err != nil {
+		if errors.Is(err, service.ErrInvalidUserToken) {
+			return c.JSON(http.StatusBadRequest, jsonres.Error(
+				"INVALID_TOKEN", err.Error(), nil,
+			))
+		}
+		return c.JSON(http.StatusInternalServerError, jsonres.Error(
+			"VERIFY_FAILED", "Failed to verify email", nil,
+		))
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success("Email verified", nil))
+}
+
+// ResendVerificationEmail godoc
+// @Summary Resend verification email
+// @Description Email a new verification link to the authenticated user. Earlier links stop working
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} jsonres.SuccessResponse
+// @Failure 409 {object} jsonres.ErrorResponse
+// @Router /auth/verify-email/resend [post]
+func (h *AuthHandler) ResendVerificationEmail(c echo.Context) error {
+	userID := c.Get("userID").(string)
+
+	if err := h.accountService.ResendVerificationEmail(userID); err != nil {
+		if errors.Is(err, service.ErrEmailAlreadyVerified) {
+			return c.JSON(http.StatusConflict, jsonres.Error(
+				"ALREADY_VERIFIED", err.Error(), nil,
+			))
+		}
+		return c.JSON(http.StatusInternalServerError, jsonres.Error(
+			"SEND_FAILED", "Failed to send verification email", nil,
+		))
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success("Verification email sent", nil))
+}
+
+// ForgotPassword godoc
+// @Summary Request password reset
+// @Description Email a password reset link if the address belongs to an account. The response is the same whether or not it does
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body request.ForgotPasswordRequest true "Account email"
+// @Success 200 {object} jsonres.SuccessResponse
+// @Failure 400 {object} jsonres.ErrorResponse
+// @Router /auth/forgot-password [post]
+func (h *AuthHandler) ForgotPassword(c echo.Context) error {
+	var req request.ForgotPasswordRequest
+
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"BAD_REQUEST", "Invalid request body", err.Error(),
+		))
+	}
+
+	if errs := validator.Validate(&req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"VALIDATION_ERROR", "Validation failed", errs,
+		))
+	}
+
+	if err := h.accountService.ForgotPassword(req.Email); err != nil {
+		logger.Error("Failed to send password reset email", "error", err)
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success(
+		"If the email is registered, a password reset link has been sent", nil,
+	))
+}
+
+// ResetPassword godoc
+// @Summary Reset password
+// @Description Set a new password with the token from the reset email. All sessions of the account are signed out
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body request.ResetPasswordRequest true "Reset token and new password"
+// @Success 200 {object} jsonres.SuccessResponse
+// @Failure 400 {object} jsonres.ErrorResponse
+// @Router /auth/reset-password [post]
+func (h *AuthHandler) ResetPassword(c echo.Context) error {
+	var req request.ResetPasswordRequest
+
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"BAD_REQUEST", "Invalid request body", err.Error(),
+		))
+	}
+
+	if errs := validator.Validate(&req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"VALIDATION_ERROR", "Validation failed", errs,
+		))
+	}
+
+	if err := h.accountService.ResetPassword(req.Token, req.Password); err != nil {
+		if errors.Is(err, service.ErrInvalidUserToken) {
+			return c.JSON(http.StatusBadRequest, jsonres.Error(
+				"INVALID_TOKEN", err.Error(), nil,
+			))
+		}
+		return c.JSON(http.StatusInternalServerError, jsonres.Error(
+			"RESET_FAILED", "Failed to reset password", nil,
+		))
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success("Password has been reset", nil))
+}
+
+// ConfirmEmailChange godoc
+// @Summary Confirm an email change
+// @Description Switch the account to its new email address with the token mailed there
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body request.VerifyEmailRequest true "Confirmation token"
+// @Success 200 {object} jsonres.SuccessResponse
+// @Failure 400 {object} jsonres.ErrorResponse
+// @Failure 409 {object} jsonres.ErrorResponse
+// @Router /auth/confirm-email [post]
+func (h *AuthHandler) ConfirmEmailChange(c echo.Context) error {
+	var req request.VerifyEmailRequest
+
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"BAD_REQUEST", "Invalid request body", err.Error(),
+		))
+	}
+
+	if errs := validator.Validate(&req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"VALIDATION_ERROR", "Validation failed", errs,
+		))
+	}
+
+	if err := h.accountService.ConfirmEmailChange(req.Token); err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidUserToken):
+			return c.JSON(http.StatusBadRequest, jsonres.Error(
+				"INVALID_TOKEN", err.Error(), nil,
+			))
+		case errors.Is(err, service.ErrEmailTaken):
+			return c.JSON(http.StatusConflict, jsonres.Error(
+				"CONFLICT", err.Error(), nil,
+			))
+		}
+		return c.JSON(http.StatusInternalServerError, jsonres.Error(
+			"CONFIRM_FAILED", "Failed to change email", nil,
+		))
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success("Email address changed", nil))
+}
+
+// UnlockAccount godoc
+// @Summary Unlock user login
+// @Description Clear failed login attempts and any lockout of a user's account
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} jsonres.SuccessResponse
+// @Failure 404 {object} jsonres.ErrorResponse
+// @Router /admin/users/{id}/unlock [post]
+func (h *AuthHandler) UnlockAccount(c echo.Context) error {
+	if err := h.authService.UnlockAccount(actorFromContext(c), c.Param("id")); err != nil {
+		return c.JSON(http.StatusNotFound, jsonres.Error(
+			"NOT_FOUND", err.Error(), nil,
+		))
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success("Account unlocked", nil))
+}
+
+// VerifyTwoFactor godoc
+// @Summary Complete two-factor login
+// @Description Exchange the challenge token from login and a TOTP or recovery code for tokens
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body request.VerifyTwoFactorRequest true "Challenge and code"
+// @Success 200 {object} jsonres.SuccessResponse{data=response.LoginResponse}
+// @Failure 401 {object} jsonres.ErrorResponse
+// @Failure 429 {object} jsonres.ErrorResponse
+// @Router /auth/2fa/verify [post]
+func (h *AuthHandler) VerifyTwoFactor(c echo.Context) error {
+	var req request.VerifyTwoFactorRequest
+
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"BAD_REQUEST", "Invalid request body", err.Error(),
+		))
+	}
+
+	if errs := validator.Validate(&req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"VALIDATION_ERROR", "Validation failed", errs,
+		))
+	}
+
+	result, err := h.authService.VerifyTwoFactor(req.ChallengeToken, req.Code, c.RealIP())
+	if err != nil {
+		return loginError(c, err)
+	}
+
+	return loginSuccess(c, result)
+}
+
+// SetupTwoFactor godoc
+// @Summary Start two-factor enrolment
+// @Description Generate a TOTP secret and provisioning URI for an authenticator app. Confirm it with /auth/2fa/enable
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} jsonres.SuccessResponse{data=response.TwoFactorSetupResponse}
+// @Failure 409 {object} jsonres.ErrorResponse
+// @Router /auth/2fa/setup [post]
+func (h *AuthHandler) SetupTwoFactor(c echo.Context) error {
+	userID := c.Get("userID").(string)
+
+	setup, err := h.authService.SetupTwoFactor(userID)
+	if err != nil {
+		return twoFactorError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success("Scan the code with your authenticator app", dto.TwoFactorSetupResponse{
+		Secret:          setup.Secret,
+		ProvisioningURI: setup.ProvisioningURI,
+	}))
+}
+
+// EnableTwoFactor godoc
+// @Summary Enable two-factor authentication
+// @Description Confirm enrolment with a code from the authenticator app. The recovery codes are only shown once
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body request.TwoFactorCodeRequest true "TOTP code"
+// @Success 200 {object} jsonres.SuccessResponse{data=response.RecoveryCodesResponse}
+// @Failure 400 {object} jsonres.ErrorResponse
+// @Failure 409 {object} jsonres.ErrorResponse
+// @Router /auth/2fa/enable [post]
+func (h *AuthHandler) EnableTwoFactor(c echo.Context) error {
+	var req request.TwoFactorCodeRequest
+
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"BAD_REQUEST", "Invalid request body", err.Error(),
+		))
+	}
+
+	if errs := validator.Validate(&req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"VALIDATION_ERROR", "Validation failed", errs,
+		))
+	}
+
+	userID := c.Get("userID").(string)
+
+	codes, err := h.authService.EnableTwoFactor(userID, req.Code)
+	if err != nil {
+		return twoFactorError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success(
+		"Two-factor authentication enabled", dto.RecoveryCodesResponse{RecoveryCodes: codes},
+	))
+}
+
+// DisableTwoFactor godoc
+// @Summary Disable two-factor authentication
+// @Description Turn off two-factor authentication with the account password and a TOTP or recovery code
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body request.DisableTwoFactorRequest true "Password and code"
+// @Success 200 {object} jsonres.SuccessResponse
+// @Failure 400 {object} jsonres.ErrorResponse
+// @Failure 403 {object} jsonres.ErrorResponse
+// @Router /auth/2fa/disable [post]
+func (h *AuthHandler) DisableTwoFactor(c echo.Context) error {
+	var req request.DisableTwoFactorRequest
+
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"BAD_REQUEST", "Invalid request body", err.Error(),
+		))
+	}
+
+	if errs := validator.Validate(&req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"VALIDATION_ERROR", "Validation failed", errs,
+		))
+	}
+
+	userID := c.Get("userID").(string)
+
+	if err := h.authService.DisableTwoFactor(userID, req.Password, req.Code); err != nil {
+		return twoFactorError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success("Two-factor authentication disabled", nil))
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary Regenerate recovery codes
+// @Description Replace all recovery codes after checking a TOTP or recovery code
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body request.TwoFactorCodeRequest true "TOTP or recovery code"
+// @Success 200 {object} jsonres.SuccessResponse{data=response.RecoveryCodesResponse}
+// @Failure 400 {object} jsonres.ErrorResponse
+// @Router /auth/2fa/recovery-codes [post]
+func (h *AuthHandler) RegenerateRecoveryCodes(c echo.Context) error {
+	var req request.TwoFactorCodeRequest
+
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"BAD_REQUEST", "Invalid request body", err.Error(),
+		))
+	}
+
+	if errs := validator.Validate(&req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"VALIDATION_ERROR", "Validation failed", errs,
+		))
+	}
+
+	userID := c.Get("userID").(string)
+
+	codes, err := h.authService.RegenerateRecoveryCodes(userID, req.Code)
+	if err != nil {
+		return twoFactorError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success(
+		"Recovery codes regenerated", dto.RecoveryCodesResponse{RecoveryCodes: codes},
+	))
+}
+
+// ResetTwoFactor godoc
+// @Summary Reset user two-factor authentication
+// @Description Remove two-factor authentication from a user who lost their authenticator and recovery codes, and sign them out everywhere
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} jsonres.SuccessResponse
+// @Failure 404 {object} jsonres.ErrorResponse
+// @Router /admin/users/{id}/2fa [delete]
+func (h *AuthHandler) ResetTwoFactor(c echo.Context) error {
+	if err := h.authService.ResetTwoFactor(c.Param("id")); err != nil {
+		return c.JSON(http.StatusNotFound, jsonres.Error(
+			"NOT_FOUND", err.Error(), nil,
+		))
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success("Two-factor authentication reset", nil))
+}
+
+func loginSuccess(c echo.Context, result *service.LoginResult) error {
+	if result.Challenge != nil {
+		return c.JSON(http.StatusOK, jsonres.Success("Two-factor authentication required", dto.TwoFactorChallengeResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    result.Challenge.Token,
+			ExpiresAt:         result.Challenge.ExpiresAt,
+		}))
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success("Login Successful", toLoginResponse(result.Tokens, result.User)))
+}
+
+func loginError(c echo.Context, err error) error {
+	var throttled *service.LoginThrottledError
+	switch {
+	case errors.As(err, &throttled):
+		c.Response().Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+		return c.JSON(http.StatusTooManyRequests, jsonres.Error(
+			"TOO_MANY_ATTEMPTS", err.Error(), nil,
+		))
+	case errors.Is(err, service.ErrInvalidCredentials),
+		errors.Is(err, service.ErrInvalidChallenge),
+		errors.Is(err, service.ErrInvalidTwoFactorCode):
 		return c.JSON(http.StatusUnauthorized, jsonres.Error(
 			"LOGIN_FAILED", err.Error(), nil,
 		))
+	case errors.Is(err, service.ErrAccountDeactivated):
+		return c.JSON(http.StatusForbidden, jsonres.Error(
+			"ACCOUNT_DEACTIVATED", err.Error(), nil,
+		))
+	default:
+		return c.JSON(http.StatusInternalServerError, jsonres.Error(
+			"LOGIN_FAILED", "Failed to login", nil,
+		))
 	}
+}
 
-	return c.JSON(http.StatusOK, jsonres.Success("Login Successful", dto.LoginResponse{
-		Token: token,
-		User:  dto.ToUserResponse(user),
-	}))
+func twoFactorError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, service.ErrTwoFactorEnabled), errors.Is(err, service.ErrTwoFactorNotEnabled):
+		return c.JSON(http.StatusConflict, jsonres.Error(
+			"CONFLICT", err.Error(), nil,
+		))
+	case errors.Is(err, service.ErrTwoFactorRequired):
+		return c.JSON(http.StatusForbidden, jsonres.Error(
+			"FORBIDDEN", err.Error(), nil,
+		))
+	case errors.Is(err, service.ErrTwoFactorNotSetUp),
+		errors.Is(err, service.ErrInvalidTwoFactorCode),
+		errors.Is(err, service.ErrIncorrectPassword):
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"TWO_FACTOR_FAILED", err.Error(), nil,
+		))
+	default:
+		return c.JSON(http.StatusInternalServerError, jsonres.Error(
+			"TWO_FACTOR_FAILED", "Failed to update two-factor authentication", nil,
+		))
+	}
+}
+
+func toLoginResponse(tokens *service.AuthTokens, user *domain.User) dto.LoginResponse {
+	return dto.LoginResponse{
+		Token:            tokens.AccessToken,
+		ExpiresAt:        tokens.AccessExpiresAt,
+		RefreshToken:     tokens.RefreshToken,
+		RefreshExpiresAt: tokens.RefreshExpiresAt,
+		User:             dto.ToUserResponse(user),
+	}
 }
@@ -1,12 +1,15 @@
 package handler
 
 import (
+	"errors"
 	"hotel-booking-api/internal/dto/request"
 	dto "hotel-booking-api/internal/dto/response"
+	"hotel-booking-api/internal/repository"
 	"hotel-booking-api/internal/service"
 	"hotel-booking-api/pkg/jsonres"
 	"hotel-booking-api/pkg/validator"
 	"net/http"
+	"time"
 
 	"github.com/labstack/echo/v4"
 )
@@ -67,15 +70,31 @@ func (h *BookingHandler) CreateBooking(c echo.Context) error {
 // @Accept json
 // @Produce json
 // @Param id path string true "Booking ID"
+// @Param If-Match header string true "ETag of the booking as last read"
 // @Success 200 {object} jsonres.SuccessResponse
 // @Failure 400 {object} jsonres.ErrorResponse
+// @Failure 403 {object} jsonres.ErrorResponse
+// @Failure 404 {object} jsonres.ErrorResponse
+// @Failure 412 {object} jsonres.ErrorResponse
+// @Failure 428 {object} jsonres.ErrorResponse
 // @Security BearerAuth
 // @Router /bookings/{id}/cancel [patch]
 func (h *BookingHandler) CancelBooking(c echo.Context) error {
-	userID := c.Get("userID").(string)
 	bookingID := c.Param("id")
 
-	if err := h.bookingService.CancelBooking(userID, bookingID); err != nil {
+	err := h.bookingService.CancelBooking(actorFromContext(c), bookingID, ifMatch(c))
+	switch {
+	case errors.Is(err, service.ErrBookingNotFound):
+		return c.JSON(http.StatusNotFound, jsonres.Error(
+			"NOT_FOUND", "Booking not found", nil,
+		))
+	case errors.Is(err, service.ErrBookingForbidden):
+		return c.JSON(http.StatusForbidden, jsonres.Error(
+			"FORBIDDEN", err.Error(), nil,
+		))
+	case isPreconditionError(err):
+		return preconditionError(c, err)
+	case err != nil:
 		return c.JSON(http.StatusBadRequest, jsonres.Error(
 			"CANCEL_FAILED", err.Error(), nil,
 		))
@@ -86,20 +105,76 @@ func (h *BookingHandler) CancelBooking(c echo.Context) error {
 	))
 }
 
+// CompleteBooking godoc
+// @Summary Complete a booking
+// @Description Mark a confirmed booking as completed after check-out, making it eligible for review (admins and the hotel's managers and staff)
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Param id path string true "Booking ID"
+// @Param If-Match header string true "ETag of the booking as last read"
+// @Success 200 {object} jsonres.SuccessResponse{data=response.BookingResponse}
+// @Failure 400 {object} jsonres.ErrorResponse
+// @Failure 403 {object} jsonres.ErrorResponse
+// @Failure 404 {object} jsonres.ErrorResponse
+// @Failure 412 {object} jsonres.ErrorResponse
+// @Failure 428 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /bookings/{id}/complete [patch]
+func (h *BookingHandler) CompleteBooking(c echo.Context) error {
+	booking, err := h.bookingService.CompleteBooking(actorFromContext(c), c.Param("id"), ifMatch(c))
+	if errors.Is(err, service.ErrBookingNotFound) {
+		return c.JSON(http.StatusNotFound, jsonres.Error(
+			"NOT_FOUND", "Booking not found", nil,
+		))
+	}
+	if errors.Is(err, service.ErrBookingForbidden) {
+		return c.JSON(http.StatusForbidden, jsonres.Error(
+			"FORBIDDEN", err.Error(), nil,
+		))
+	}
+	if isPreconditionError(err) {
+		return preconditionError(c, err)
+	}
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"UPDATE_FAILED", err.Error(), nil,
+		))
+	}
+
+	setETag(c, booking.Version)
+	return c.JSON(http.StatusOK, jsonres.Success(
+		"Booking completed successfully", dto.ToBookingResponse(booking),
+	))
+}
+
 // GetUserBookings godoc
 // @Summary Get user bookings
-// @Description Get all bookings for the authenticated user
+// @Description Get a page of bookings for the authenticated user
 // @Tags bookings
 // @Accept json
 // @Produce json
-// @Success 200 {object} jsonres.SuccessResponse{data=[]response.BookingResponse}
+// @Param status query string false "Booking status"
+// @Param page query int false "Page number" default(1)
+// @Param cursor query string false "Cursor from a previous page's meta.next_cursor"
+// @Param limit query int false "Page size (max 100)" default(20)
+// @Param sort query string false "Sort field" Enums(created_at, check_in, check_out, total_price, status)
+// @Param order query string false "Sort order" Enums(asc, desc)
+// @Success 200 {object} jsonres.SuccessResponse{data=[]response.BookingResponse,meta=pagination.Meta}
 // @Failure 400 {object} jsonres.ErrorResponse
 // @Security BearerAuth
 // @Router /bookings [get]
 func (h *BookingHandler) GetUserBookings(c echo.Context) error {
 	userID := c.Get("userID").(string)
 
-	bookings, err := h.bookingService.GetUserBookings(userID)
+	query, err := listQuery(c, repository.BookingListOptions)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"VALIDATION_ERROR", "Validation failed", []string{err.Error()},
+		))
+	}
+
+	bookings, total, err := h.bookingService.GetUserBookings(userID, query)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, jsonres.Error(
 			"FETCH_FAILED", "Failed to fetch bookings", err.Error(),
@@ -111,7 +186,165 @@ func (h *BookingHandler) GetUserBookings(c echo.Context) error {
 		bookingResponses[i] = dto.ToBookingResponse(&booking)
 	}
 
+	return c.JSON(http.StatusOK, jsonres.SuccessWithMeta(
+		"Bookings retrieved successfully", bookingResponses, query.Meta(total, len(bookings)),
+	))
+}
+
+// GetBooking godoc
+// @Summary Get booking by ID
+// @Description Get a booking owned by the authenticated user (admins may view any booking, managers and staff those at their hotels)
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Param id path string true "Booking ID"
+// @Param If-None-Match header string false "ETag of a cached copy"
+// @Success 200 {object} jsonres.SuccessResponse{data=response.BookingResponse}
+// @Success 304 "Not modified"
+// @Failure 403 {object} jsonres.ErrorResponse
+// @Failure 404 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /bookings/{id} [get]
+func (h *BookingHandler) GetBooking(c echo.Context) error {
+	booking, err := h.bookingService.GetBookingDetail(actorFromContext(c), c.Param("id"))
+	if errors.Is(err, service.ErrBookingForbidden) {
+		return c.JSON(http.StatusForbidden, jsonres.Error(
+			"FORBIDDEN", err.Error(), nil,
+		))
+	}
+	if err != nil {
+		return c.JSON(http.StatusNotFound, jsonres.Error(
+			"NOT_FOUND", "Booking not found", nil,
+		))
+	}
+
+	if notModified(c, booking.Version) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
 	return c.JSON(http.StatusOK, jsonres.Success(
-		"Bookings retrieved successfully", bookingResponses,
+		"Booking retrieved successfully", dto.ToBookingResponse(booking),
+	))
+}
+
+// SearchBookings godoc
+// @Summary Search bookings
+// @Description Search all bookings with filters, pagination and sorting (Admin only)
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Param hotel_id query string false "Hotel ID"
+// @Param room_id query string false "Room ID"
+// @Param status query string false "Booking status"
+// @Param guest_email query string false "Guest or user email"
+// @Param reference query string false "Booking reference code"
+// @Param check_in_from query string false "Check-in on or after (YYYY-MM-DD)"
+// @Param check_in_to query string false "Check-in on or before (YYYY-MM-DD)"
+// @Param check_out_from query string false "Check-out on or after (YYYY-MM-DD)"
+// @Param check_out_to query string false "Check-out on or before (YYYY-MM-DD)"
+// @Param created_from query string false "Created on or after (YYYY-MM-DD)"
+// @Param created_to query string false "Created on or before (YYYY-MM-DD)"
+// @Param page query int false "Page number" default(1)
+// @Param cursor query string false "Cursor from a previous page's meta.next_cursor"
+// @Param limit query int false "Page size (max 100)" default(20)
+// @Param sort query string false "Sort field" Enums(created_at, check_in, check_out, total_price, status)
+// @Param order query string false "Sort order" Enums(asc, desc)
+// @Success 200 {object} jsonres.SuccessResponse{data=[]response.BookingResponse,meta=pagination.Meta}
+// @Failure 400 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/bookings [get]
+func (h *BookingHandler) SearchBookings(c echo.Context) error {
+	return h.searchBookings(c, c.QueryParam("hotel_id"))
+}
+
+// ListHotelBookings godoc
+// @Summary List a hotel's bookings
+// @Description Search the bookings of one hotel (admins and the hotel's managers and staff)
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Param id path string true "Hotel ID"
+// @Param room_id query string false "Room ID"
+// @Param status query string false "Booking status"
+// @Param guest_email query string false "Guest or user email"
+// @Param reference query string false "Booking reference code"
+// @Param check_in_from query string false "Check-in on or after (YYYY-MM-DD)"
+// @Param check_in_to query string false "Check-in on or before (YYYY-MM-DD)"
+// @Param check_out_from query string false "Check-out on or after (YYYY-MM-DD)"
+// @Param check_out_to query string false "Check-out on or before (YYYY-MM-DD)"
+// @Param created_from query string false "Created on or after (YYYY-MM-DD)"
+// @Param created_to query string false "Created on or before (YYYY-MM-DD)"
+// @Param page query int false "Page number" default(1)
+// @Param cursor query string false "Cursor from a previous page's meta.next_cursor"
+// @Param limit query int false "Page size (max 100)" default(20)
+// @Param sort query string false "Sort field" Enums(created_at, check_in, check_out, total_price, status)
+// @Param order query string false "Sort order" Enums(asc, desc)
+// @Success 200 {object} jsonres.SuccessResponse{data=[]response.BookingResponse,meta=pagination.Meta}
+// @Failure 400 {object} jsonres.ErrorResponse
+// @Failure 403 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /hotels/{id}/bookings [get]
+func (h *BookingHandler) ListHotelBookings(c echo.Context) error {
+	return h.searchBookings(c, c.Param("id"))
+}
+
+func (h *BookingHandler) searchBookings(c echo.Context, hotelID string) error {
+	filter := repository.BookingFilter{
+		HotelID:    hotelID,
+		RoomID:     c.QueryParam("room_id"),
+		GuestEmail: c.QueryParam("guest_email"),
+		Reference:  c.QueryParam("reference"),
+	}
+
+	var errs []string
+	query, err := listQuery(c, repository.BookingListOptions)
+	if err != nil {
+		errs = append(errs, err.Error())
+	}
+	filter.Query = query
+
+	for _, param := range []struct {
+		name string
+		dst  **time.Time
+	}{
+		{"check_in_from", &filter.CheckInFrom},
+		{"check_in_to", &filter.CheckInTo},
+		{"check_out_from", &filter.CheckOutFrom},
+		{"check_out_to", &filter.CheckOutTo},
+		{"created_from", &filter.CreatedFrom},
+		{"created_to", &filter.CreatedTo},
+	} {
+		t, err := queryTime(c, param.name)
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+		*param.dst = t
+	}
+
+	if len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"VALIDATION_ERROR", "Validation failed", errs,
+		))
+	}
+
+	bookings, total, err := h.bookingService.SearchBookings(actorFromContext(c), filter)
+	if errors.Is(err, service.ErrHotelForbidden) {
+		return c.JSON(http.StatusForbidden, jsonres.Error(
+			"FORBIDDEN", err.Error(), nil,
+		))
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, jsonres.Error(
+			"FETCH_FAILED", "Failed to search bookings", err.Error(),
+		))
+	}
+
+	bookingResponses := make([]dto.BookingResponse, len(bookings))
+	for i, booking := range bookings {
+		bookingResponses[i] = dto.ToBookingResponse(&booking)
+	}
+
+	return c.JSON(http.StatusOK, jsonres.SuccessWithMeta(
+		"Bookings retrieved successfully", bookingResponses, filter.Query.Meta(total, len(bookings)),
 	))
 }
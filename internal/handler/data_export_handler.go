@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"errors"
+	dto "hotel-booking-api/internal/dto/response"
+	"hotel-booking-api/internal/service"
+	"hotel-booking-api/pkg/jsonres"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type DataExportHandler struct {
+	exportService service.DataExportService
+}
+
+func NewDataExportHandler(exportService service.DataExportService) *DataExportHandler {
+	return &DataExportHandler{
+		exportService: exportService,
+	}
+}
+
+// RequestMyExport godoc
+// @Summary Request a copy of my data
+// @Description Queue a ZIP archive of the authenticated user's profile, bookings, payments, reviews and sign-in history. An email is sent when it is ready; it can be downloaded for 7 days
+// @Tags profile
+// @Produce json
+// @Success 202 {object} jsonres.SuccessResponse{data=response.DataExportResponse}
+// @Failure 409 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /users/me/exports [post]
+func (h *DataExportHandler) RequestMyExport(c echo.Context) error {
+	userID := c.Get("userID").(string)
+
+	return h.requestExport(c, userID, userID)
+}
+
+// ListMyExports godoc
+// @Summary List my data exports
+// @Description Get the authenticated user's data exports, newest first
+// @Tags profile
+// @Produce json
+// @Success 200 {object} jsonres.SuccessResponse{data=[]response.DataExportResponse}
+// @Security BearerAuth
+// @Router /users/me/exports [get]
+func (h *DataExportHandler) ListMyExports(c echo.Context) error {
+	return h.listExports(c, c.Get("userID").(string))
+}
+
+// GetMyExport godoc
+// @Summary Get a data export
+// @Description Get the status of one of the authenticated user's data exports
+// @Tags profile
+// @Produce json
+// @Param id path string true "Export ID"
+// @Success 200 {object} jsonres.SuccessResponse{data=response.DataExportResponse}
+// @Failure 404 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /users/me/exports/{id} [get]
+func (h *DataExportHandler) GetMyExport(c echo.Context) error {
+	export, err := h.exportService.GetExport(c.Get("userID").(string), c.Param("id"))
+	if err != nil {
+		return exportError(c, "FETCH_FAILED", err)
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success(
+		"Data export retrieved successfully", dto.ToDataExportResponse(export),
+	))
+}
+
+// DownloadMyExport godoc
+// @Summary Download a data export
+// @Description Download the ZIP archive of a finished data export
+// @Tags profile
+// @Produce application/zip
+// @Param id path string true "Export ID"
+// @Success 200 {file} binary
+// @Failure 404 {object} jsonres.ErrorResponse
+// @Failure 409 {object} jsonres.ErrorResponse
+// @Failure 410 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /users/me/exports/{id}/download [get]
+func (h *DataExportHandler) DownloadMyExport(c echo.Context) error {
+	return h.downloadExport(c, c.Get("userID").(string), c.Param("id"))
+}
+
+// RequestUserExport godoc
+// @Summary Request a user's data export
+// @Description Queue a data export on a user's behalf, e.g. for a support request. The requesting admin is emailed when it is ready
+// @Tags users
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 202 {object} jsonres.SuccessResponse{data=response.DataExportResponse}
+// @Failure 404 {object} jsonres.ErrorResponse
+// @Failure 409 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/users/{id}/exports [post]
+func (h *DataExportHandler) RequestUserExport(c echo.Context) error {
+	return h.requestExport(c, c.Get("userID").(string), c.Param("id"))
+}
+
+// ListUserExports godoc
+// @Summary List a user's data exports
+// @Description Get a user's data exports, newest first
+// @Tags users
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} jsonres.SuccessResponse{data=[]response.DataExportResponse}
+// @Security BearerAuth
+// @Router /admin/users/{id}/exports [get]
+func (h *DataExportHandler) ListUserExports(c echo.Context) error {
+	return h.listExports(c, c.Param("id"))
+}
+
+// DownloadUserExport godoc
+// @Summary Download a user's data export
+// @Description Download the ZIP archive of a user's finished data export
+// @Tags users
+// @Produce application/zip
+// @Param id path string true "User ID"
+// @Param exportId path string true "Export ID"
+// @Success 200 {file} binary
+// @Failure 404 {object} jsonres.ErrorResponse
+// @Failure 409 {object} jsonres.ErrorResponse
+// @Failure 410 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/users/{id}/exports/{exportId}/download [get]
+func (h *DataExportHandler) DownloadUserExport(c echo.Context) error {
+	return h.downloadExport(c, c.Param("id"), c.Param("exportId"))
+}
+
+func (h *DataExportHandler) requestExport(c echo.Context, requestedBy, userID string) error {
+	export, err := h.exportService.RequestExport(requestedBy, userID)
+	if err != nil {
+		return exportError(c, "EXPORT_FAILED", err)
+	}
+
+	return c.JSON(http.StatusAccepted, jsonres.Success(
+		"Data export requested", dto.ToDataExportResponse(export),
+	))
+}
+
+func (h *DataExportHandler) listExports(c echo.Context, userID string) error {
+	exports, err := h.exportService.ListExports(userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, jsonres.Error(
+			"FETCH_FAILED", "Failed to fetch data exports", err.Error(),
+		))
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success(
+		"Data exports retrieved successfully", dto.ToDataExportResponses(exports),
+	))
+}
+
+func (h *DataExportHandler) downloadExport(c echo.Context, userID, id string) error {
+	export, file, err := h.exportService.OpenExport(userID, id)
+	if err != nil {
+		return exportError(c, "DOWNLOAD_FAILED", err)
+	}
+	defer file.Close()
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="data-export-`+export.ID.String()+`.zip"`)
+	c.Response().Header().Set("Cache-Control", "no-store")
+
+	return c.Stream(http.StatusOK, "application/zip", file)
+}
+
+func exportError(c echo.Context, code string, err error) error {
+	switch {
+	case errors.Is(err, service.ErrUserNotFound), errors.Is(err, service.ErrExportNotFound):
+		return c.JSON(http.StatusNotFound, jsonres.Error(
+			"NOT_FOUND", err.Error(), nil,
+		))
+	case errors.Is(err, service.ErrExportInProgress), errors.Is(err, service.ErrExportNotReady):
+		return c.JSON(http.StatusConflict, jsonres.Error(
+			"CONFLICT", err.Error(), nil,
+		))
+	case errors.Is(err, service.ErrExportExpired):
+		return c.JSON(http.StatusGone, jsonres.Error(
+			"EXPIRED", err.Error(), nil,
+		))
+	default:
+		return c.JSON(http.StatusInternalServerError, jsonres.Error(
+			code, "Failed to process data export", nil,
+		))
+	}
+}
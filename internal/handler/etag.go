@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"errors"
+	"hotel-booking-api/internal/repository"
+	"hotel-booking-api/internal/service"
+	"hotel-booking-api/pkg/jsonres"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Hotels, rooms and bookings carry their version column as a strong ETag.
+// Reads answer a matching If-None-Match with 304; updates and booking status
+// changes must send the ETag they read in If-Match, so two editors cannot
+// overwrite each other.
+
+var (
+	errPreconditionRequired = errors.New("an If-Match header with the resource's ETag is required")
+	errPreconditionFailed   = errors.New("the resource has changed since it was read; fetch it again and retry")
+)
+
+func etag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+func setETag(c echo.Context, version int) {
+	c.Response().Header().Set("ETag", etag(version))
+}
+
+// notModified sets the ETag of a read and reports whether If-None-Match
+// already names it, in which case the handler answers 304.
+func notModified(c echo.Context, version int) bool {
+	setETag(c, version)
+
+	header := c.Request().Header.Get("If-None-Match")
+	return header != "" && matchETag(header, etag(version), true)
+}
+
+// checkIfMatch requires the request's If-Match header to name version.
+func checkIfMatch(c echo.Context, version int) error {
+	header := c.Request().Header.Get("If-Match")
+	if header == "" {
+		return errPreconditionRequired
+	}
+	if !matchETag(header, etag(version), false) {
+		return errPreconditionFailed
+	}
+
+	return nil
+}
+
+// ifMatch is checkIfMatch for services that read the record themselves.
+func ifMatch(c echo.Context) service.Precondition {
+	return func(version int) error {
+		return checkIfMatch(c, version)
+	}
+}
+
+// isPreconditionError reports whether err is answered by preconditionError.
+func isPreconditionError(err error) bool {
+	return errors.Is(err, errPreconditionRequired) || errors.Is(err, errPreconditionFailed) ||
+		errors.Is(err, repository.ErrVersionConflict)
+}
+
+// matchETag reports whether tag is in header, a comma-separated list of
+// entity tags or "*". Weak tags (W/"...") only match under weak comparison,
+// which If-None-Match uses and If-Match does not.
+func matchETag(header, tag string, weak bool) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+		if strings.HasPrefix(candidate, "W/") {
+			if !weak {
+				continue
+			}
+			candidate = candidate[2:]
+		}
+		if candidate == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// preconditionError answers a failed If-Match check or a version conflict
+// found while saving.
+func preconditionError(c echo.Context, err error) error {
+	if errors.Is(err, errPreconditionRequired) {
+		return c.JSON(http.StatusPreconditionRequired, jsonres.Error(
+			"PRECONDITION_REQUIRED", err.Error(), nil,
+		))
+	}
+	if errors.Is(err, repository.ErrVersionConflict) {
+		err = errPreconditionFailed
+	}
+
+	return c.JSON(http.StatusPreconditionFailed, jsonres.Error(
+		"PRECONDITION_FAILED", err.Error(), nil,
+	))
+}
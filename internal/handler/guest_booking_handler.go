@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"hotel-booking-api/internal/dto/request"
+	dto "hotel-booking-api/internal/dto/response"
+	"hotel-booking-api/internal/service"
+	"hotel-booking-api/pkg/jsonres"
+	"hotel-booking-api/pkg/validator"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type GuestBookingHandler struct {
+	bookingService service.BookingService
+	paymentService service.PaymentService
+}
+
+func NewGuestBookingHandler(bookingService service.BookingService, paymentService service.PaymentService) *GuestBookingHandler {
+	return &GuestBookingHandler{
+		bookingService: bookingService,
+		paymentService: paymentService,
+	}
+}
+
+// CreateGuestBooking godoc
+// @Summary Create a guest booking
+// @Description Book a room without an account and receive a manage-booking link
+// @Tags guest-bookings
+// @Accept json
+// @Produce json
+// @Param request body request.CreateGuestBookingRequest true "Guest and booking details"
+// @Success 201 {object} jsonres.SuccessResponse{data=response.GuestBookingResponse}
+// @Failure 400 {object} jsonres.ErrorResponse
+// @Router /guest/bookings [post]
+func (h *GuestBookingHandler) CreateGuestBooking(c echo.Context) error {
+	var req request.CreateGuestBookingRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"BAD_REQUEST", "Invalid request body", err.Error(),
+		))
+	}
+
+	if errs := validator.Validate(&req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"VALIDATION_ERROR", "Validation failed", errs,
+		))
+	}
+
+	booking, link, err := h.bookingService.CreateGuestBooking(req.Name, req.Email, req.Phone, req.RoomID, req.CheckIn, req.CheckOut)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"BOOKING_FAILED", err.Error(), nil,
+		))
+	}
+
+	return c.JSON(http.StatusCreated, jsonres.Success(
+		"Booking created successfully", dto.GuestBookingResponse{
+			Booking:         dto.ToBookingResponse(booking),
+			ManageToken:     link.Token,
+			ManageURL:       c.Echo().Reverse("guest-booking-detail", link.Token),
+			ManageExpiresAt: link.ExpiresAt,
+		},
+	))
+}
+
+// GetGuestBooking godoc
+// @Summary Get a guest booking
+// @Description View a booking using its manage-booking link token
+// @Tags guest-bookings
+// @Accept json
+// @Produce json
+// @Param token path string true "Manage-booking token"
+// @Param If-None-Match header string false "ETag of a cached copy"
+// @Success 200 {object} jsonres.SuccessResponse{data=response.BookingResponse}
+// @Success 304 "Not modified"
+// @Failure 401 {object} jsonres.ErrorResponse
+// @Router /guest/bookings/{token} [get]
+func (h *GuestBookingHandler) GetGuestBooking(c echo.Context) error {
+	booking, err := h.bookingService.GetBookingByManageToken(c.Param("token"))
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, jsonres.Error(
+			"INVALID_LINK", err.Error(), nil,
+		))
+	}
+
+	if notModified(c, booking.Version) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success(
+		"Booking retrieved successfully", dto.ToBookingResponse(booking),
+	))
+}
+
+// CancelGuestBooking godoc
+// @Summary Cancel a guest booking
+// @Description Cancel a booking using its manage-booking link token
+// @Tags guest-bookings
+// @Accept json
+// @Produce json
+// @Param token path string true "Manage-booking token"
+// @Param If-Match header string true "ETag of the booking as last read"
+// @Success 200 {object} jsonres.SuccessResponse
+// @Failure 400 {object} jsonres.ErrorResponse
+// @Failure 412 {object} jsonres.ErrorResponse
+// @Failure 428 {object} jsonres.ErrorResponse
+// @Router /guest/bookings/{token}/cancel [patch]
+func (h *GuestBookingHandler) CancelGuestBooking(c echo.Context) error {
+	err := h.bookingService.CancelBookingByManageToken(actorFromContext(c), c.Param("token"), ifMatch(c))
+	if isPreconditionError(err) {
+		return preconditionError(c, err)
+	}
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"CANCEL_FAILED", err.Error(), nil,
+		))
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success(
+		"Booking cancelled successfully", nil,
+	))
+}
+
+// PayGuestBooking godoc
+// @Summary Pay for a guest booking
+// @Description Start payment for a booking using its manage-booking link token
+// @Tags guest-bookings
+// @Accept json
+// @Produce json
+// @Param token path string true "Manage-booking token"
+// @Param request body request.PayBookingRequest true "Payment details"
+// @Success 200 {object} jsonres.SuccessResponse{data=response.PaymentResponse}
+// @Failure 400 {object} jsonres.ErrorResponse
+// @Router /guest/bookings/{token}/pay [post]
+func (h *GuestBookingHandler) PayGuestBooking(c echo.Context) error {
+	var req request.PayBookingRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"BAD_REQUEST", "Invalid request body", err.Error(),
+		))
+	}
+
+	if errs := validator.Validate(&req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"VALIDATION_ERROR", "Validation failed", errs,
+		))
+	}
+
+	booking, err := h.bookingService.GetBookingByManageToken(c.Param("token"))
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, jsonres.Error(
+			"INVALID_LINK", err.Error(), nil,
+		))
+	}
+
+	payment, err := h.paymentService.InitiatePayment(booking.ID.String(), req.PaymentMethod)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"PAYMENT_FAILED", err.Error(), nil,
+		))
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success(
+		"Payment initiated successfully", dto.ToPaymentResponse(payment),
+	))
+}
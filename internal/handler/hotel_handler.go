@@ -1,13 +1,17 @@
 package handler
 
 import (
+	"errors"
 	"hotel-booking-api/internal/domain"
 	"hotel-booking-api/internal/dto/request"
 	dto "hotel-booking-api/internal/dto/response"
+	"hotel-booking-api/internal/repository"
 	"hotel-booking-api/internal/service"
 	"hotel-booking-api/pkg/jsonres"
 	"hotel-booking-api/pkg/validator"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 )
@@ -51,15 +55,21 @@ func (h *HotelHandler) CreateHotel(c echo.Context) error {
 	hotel := &domain.Hotel{
 		Name:        req.Name,
 		Location:    req.Location,
+		Address:     req.Address,
+		City:        req.City,
+		Country:     req.Country,
+		Latitude:    req.Latitude,
+		Longitude:   req.Longitude,
 		Description: req.Description,
 	}
 
-	if err := h.hotelService.CreateHotel(hotel); err != nil {
+	if err := h.hotelService.CreateHotel(actorFromContext(c), hotel); err != nil {
 		return c.JSON(http.StatusInternalServerError, jsonres.Error(
 			"CREATE_FAILED", "Failed to create hotel", err.Error(),
 		))
 	}
 
+	setETag(c, hotel.Version)
 	return c.JSON(http.StatusCreated, jsonres.Success(
 		"Hotel created successfully", dto.ToHotelResponse(hotel),
 	))
@@ -67,14 +77,17 @@ func (h *HotelHandler) CreateHotel(c echo.Context) error {
 
 // UpdateHotel godoc
 // @Summary Update a hotel
-// @Description Update hotel details (Admin only)
+// @Description Update hotel details (admins and the hotel's managers)
 // @Tags hotels
 // @Accept json
 // @Produce json
 // @Param id path string true "Hotel ID"
+// @Param If-Match header string true "ETag of the hotel as last read"
 // @Param request body request.UpdateHotelRequest true "Hotel details"
 // @Success 200 {object} jsonres.SuccessResponse{data=response.HotelResponse}
 // @Failure 400 {object} jsonres.ErrorResponse
+// @Failure 412 {object} jsonres.ErrorResponse
+// @Failure 428 {object} jsonres.ErrorResponse
 // @Security BearerAuth
 // @Router /hotels/{id} [put]
 func (h *HotelHandler) UpdateHotel(c echo.Context) error {
@@ -100,32 +113,121 @@ func (h *HotelHandler) UpdateHotel(c echo.Context) error {
 		))
 	}
 
-	hotel.Name = req.Name
-	hotel.Location = req.Location
-	hotel.Description = req.Description
+	if err := checkIfMatch(c, hotel.Version); err != nil {
+		return preconditionError(c, err)
+	}
+
+	setHotelFields(hotel, req)
 
-	if err := h.hotelService.UpdateHotel(hotel); err != nil {
+	if err := h.hotelService.UpdateHotel(actorFromContext(c), hotel); errors.Is(err, service.ErrHotelForbidden) {
+		return c.JSON(http.StatusForbidden, jsonres.Error(
+			"FORBIDDEN", err.Error(), nil,
+		))
+	} else if errors.Is(err, repository.ErrVersionConflict) {
+		return preconditionError(c, err)
+	} else if err != nil {
 		return c.JSON(http.StatusInternalServerError, jsonres.Error(
 			"UPDATE_FAILED", "Failed to update hotel", err.Error(),
 		))
 	}
 
+	setETag(c, hotel.Version)
 	return c.JSON(http.StatusOK, jsonres.Success(
 		"Hotel updated successfully", dto.ToHotelResponse(hotel),
 	))
 }
 
+// PatchHotel godoc
+// @Summary Partially update a hotel
+// @Description Apply a JSON Merge Patch (RFC 7396) to a hotel's details (admins and the hotel's managers). Only the fields in the patch change; null clears an optional field. meta.changed lists the fields whose value changed
+// @Tags hotels
+// @Accept application/merge-patch+json
+// @Produce json
+// @Param id path string true "Hotel ID"
+// @Param If-Match header string true "ETag of the hotel as last read"
+// @Param request body request.UpdateHotelRequest true "Fields to change"
+// @Success 200 {object} jsonres.SuccessResponse{data=response.HotelResponse,meta=response.PatchMeta}
+// @Failure 400 {object} jsonres.ErrorResponse
+// @Failure 404 {object} jsonres.ErrorResponse
+// @Failure 412 {object} jsonres.ErrorResponse
+// @Failure 415 {object} jsonres.ErrorResponse
+// @Failure 428 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /hotels/{id} [patch]
+func (h *HotelHandler) PatchHotel(c echo.Context) error {
+	hotel, err := h.hotelService.GetHotelDetail(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, jsonres.Error(
+			"NOT_FOUND", "Hotel not found", nil,
+		))
+	}
+
+	if err := checkIfMatch(c, hotel.Version); err != nil {
+		return preconditionError(c, err)
+	}
+
+	req := hotelFields(hotel)
+	changed, err := mergePatch(c, &req)
+	if err != nil {
+		return patchError(c, err)
+	}
+
+	if errs := validator.Validate(&req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"VALIDATION_ERROR", "Validation failed", errs,
+		))
+	}
+
+	if len(changed) > 0 {
+		setHotelFields(hotel, req)
+
+		if err := h.hotelService.UpdateHotel(actorFromContext(c), hotel); errors.Is(err, service.ErrHotelForbidden) {
+			return c.JSON(http.StatusForbidden, jsonres.Error(
+				"FORBIDDEN", err.Error(), nil,
+			))
+		} else if errors.Is(err, repository.ErrVersionConflict) {
+			return preconditionError(c, err)
+		} else if err != nil {
+			return c.JSON(http.StatusInternalServerError, jsonres.Error(
+				"UPDATE_FAILED", "Failed to update hotel", err.Error(),
+			))
+		}
+	}
+
+	setETag(c, hotel.Version)
+	return c.JSON(http.StatusOK, jsonres.SuccessWithMeta(
+		"Hotel updated successfully", dto.ToHotelResponse(hotel), dto.PatchMeta{Changed: changed},
+	))
+}
+
 // ListHotels godoc
 // @Summary List all hotels
-// @Description Get a list of all hotels
+// @Description Get a page of hotels
 // @Tags hotels
 // @Accept json
 // @Produce json
-// @Success 200 {object} jsonres.SuccessResponse{data=[]response.HotelResponse}
+// @Param name query string false "Filter by name"
+// @Param location query string false "Filter by location"
+// @Param min_rating query number false "Minimum average rating"
+// @Param amenities query string false "Comma-separated amenity codes the hotel must all offer"
+// @Param page query int false "Page number" default(1)
+// @Param cursor query string false "Cursor from a previous page's meta.next_cursor"
+// @Param limit query int false "Page size (max 100)" default(20)
+// @Param sort query string false "Sort field" Enums(name, location, rating, created_at)
+// @Param order query string false "Sort order" Enums(asc, desc)
+// @Success 200 {object} jsonres.SuccessResponse{data=[]response.HotelResponse,meta=pagination.Meta}
+// @Failure 400 {object} jsonres.ErrorResponse
 // @Failure 500 {object} jsonres.ErrorResponse
 // @Router /hotels [get]
 func (h *HotelHandler) ListHotels(c echo.Context) error {
-	hotels, err := h.hotelService.ListHotel()
+	query, err := listQuery(c, repository.HotelListOptions)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"VALIDATION_ERROR", "Validation failed", []string{err.Error()},
+		))
+	}
+
+	hotels, total, err := h.hotelService.ListHotel(query, queryList(c, "amenities"))
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, jsonres.Error(
 			"FETCH_FAILED", "Failed to fetch hotels", err.Error(),
@@ -137,8 +239,8 @@ func (h *HotelHandler) ListHotels(c echo.Context) error {
 		hotelResponse[i] = dto.ToHotelResponse(&hotel)
 	}
 
-	return c.JSON(http.StatusOK, jsonres.Success(
-		"Hotels retrieved successfully", hotelResponse,
+	return c.JSON(http.StatusOK, jsonres.SuccessWithMeta(
+		"Hotels retrieved successfully", hotelResponse, query.Meta(total, len(hotels)),
 	))
 }
 
@@ -149,7 +251,9 @@ func (h *HotelHandler) ListHotels(c echo.Context) error {
 // @Accept json
 // @Produce json
 // @Param id path string true "Hotel ID"
+// @Param If-None-Match header string false "ETag of a cached copy"
 // @Success 200 {object} jsonres.SuccessResponse{data=response.HotelResponse}
+// @Success 304 "Not modified"
 // @Failure 404 {object} jsonres.ErrorResponse
 // @Router /hotels/{id} [get]
 func (h *HotelHandler) GetHotel(c echo.Context) error {
@@ -162,6 +266,10 @@ func (h *HotelHandler) GetHotel(c echo.Context) error {
 		))
 	}
 
+	if notModified(c, hotel.Version) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
 	return c.JSON(http.StatusOK, jsonres.Success(
 		"Hotel retrieved successfully", dto.ToHotelResponse(hotel),
 	))
@@ -169,25 +277,294 @@ func (h *HotelHandler) GetHotel(c echo.Context) error {
 
 // DeleteHotel godoc
 // @Summary Delete a hotel
-// @Description Delete a hotel by ID (Admin only)
+// @Description Soft-delete a hotel and its rooms (Admin only). They disappear from listings, search and booking, while existing bookings keep them. Refused while upcoming bookings exist
 // @Tags hotels
 // @Accept json
 // @Produce json
 // @Param id path string true "Hotel ID"
 // @Success 200 {object} jsonres.SuccessResponse
+// @Failure 404 {object} jsonres.ErrorResponse
+// @Failure 409 {object} jsonres.ErrorResponse
 // @Failure 500 {object} jsonres.ErrorResponse
 // @Security BearerAuth
 // @Router /hotels/{id} [delete]
 func (h *HotelHandler) DeleteHotel(c echo.Context) error {
 	hotelID := c.Param("id")
 
-	if err := h.hotelService.DeleteHotel(hotelID); err != nil {
+	if err := h.hotelService.DeleteHotel(actorFromContext(c), hotelID); err != nil {
+		return deletionError(c, "DELETE_FAILED", "Failed to delete hotel", err)
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success(
+		"Hotel deleted successfully", nil,
+	))
+}
+
+// ListDeletedHotels godoc
+// @Summary List deleted hotels
+// @Description Get a page of soft-deleted hotels, most recently deleted first
+// @Tags hotels
+// @Produce json
+// @Param name query string false "Filter by name"
+// @Param page query int false "Page number" default(1)
+// @Param cursor query string false "Cursor from a previous page's meta.next_cursor"
+// @Param limit query int false "Page size (max 100)" default(20)
+// @Param sort query string false "Sort field" Enums(name, deleted_at)
+// @Param order query string false "Sort order" Enums(asc, desc)
+// @Success 200 {object} jsonres.SuccessResponse{data=[]response.HotelResponse,meta=pagination.Meta}
+// @Failure 400 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/hotels/deleted [get]
+func (h *HotelHandler) ListDeletedHotels(c echo.Context) error {
+	query, err := listQuery(c, repository.DeletedHotelListOptions)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"VALIDATION_ERROR", "Validation failed", []string{err.Error()},
+		))
+	}
+
+	hotels, total, err := h.hotelService.ListDeletedHotels(query)
+	if err != nil {
 		return c.JSON(http.StatusInternalServerError, jsonres.Error(
-			"DELETE_FAILED", "Failed to delete hotel", err.Error(),
+			"FETCH_FAILED", "Failed to fetch deleted hotels", err.Error(),
 		))
 	}
 
+	hotelResponse := make([]dto.HotelResponse, len(hotels))
+	for i, hotel := range hotels {
+		hotelResponse[i] = dto.ToHotelResponse(&hotel)
+	}
+
+	return c.JSON(http.StatusOK, jsonres.SuccessWithMeta(
+		"Deleted hotels retrieved successfully", hotelResponse, query.Meta(total, len(hotels)),
+	))
+}
+
+// RestoreHotel godoc
+// @Summary Restore a deleted hotel
+// @Description Undelete a hotel together with the rooms that were deleted with it
+// @Tags hotels
+// @Produce json
+// @Param id path string true "Hotel ID"
+// @Success 200 {object} jsonres.SuccessResponse{data=response.HotelResponse}
+// @Failure 404 {object} jsonres.ErrorResponse
+// @Failure 409 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/hotels/{id}/restore [post]
+func (h *HotelHandler) RestoreHotel(c echo.Context) error {
+	hotel, err := h.hotelService.RestoreHotel(actorFromContext(c), c.Param("id"))
+	if err != nil {
+		return deletionError(c, "RESTORE_FAILED", "Failed to restore hotel", err)
+	}
+
 	return c.JSON(http.StatusOK, jsonres.Success(
-		"Hotel deleted successfully", nil,
+		"Hotel restored successfully", dto.ToHotelResponse(hotel),
 	))
 }
+
+// PurgeHotel godoc
+// @Summary Permanently delete a hotel
+// @Description Permanently delete a soft-deleted hotel with its rooms, photos and staff assignments. Hotels with any booking history cannot be purged
+// @Tags hotels
+// @Produce json
+// @Param id path string true "Hotel ID"
+// @Success 200 {object} jsonres.SuccessResponse
+// @Failure 404 {object} jsonres.ErrorResponse
+// @Failure 409 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/hotels/{id} [delete]
+func (h *HotelHandler) PurgeHotel(c echo.Context) error {
+	if err := h.hotelService.PurgeHotel(actorFromContext(c), c.Param("id")); err != nil {
+		return deletionError(c, "PURGE_FAILED", "Failed to purge hotel", err)
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success(
+		"Hotel purged successfully", nil,
+	))
+}
+
+// SearchHotels godoc
+// @Summary Search hotels
+// @Description Find hotels with a room available for the whole stay, showing the lowest nightly price
+// @Tags hotels
+// @Accept json
+// @Produce json
+// @Param q query string false "Free-text search over name, description and location, ranked by relevance"
+// @Param destination query string false "Hotel name, city, country or location"
+// @Param check_in query string false "Check-in date (YYYY-MM-DD)"
+// @Param check_out query string false "Check-out date (YYYY-MM-DD)"
+// @Param guests query int false "Number of guests"
+// @Param min_price query number false "Minimum nightly price"
+// @Param max_price query number false "Maximum nightly price"
+// @Param amenities query string false "Comma-separated amenity codes the hotel must all offer"
+// @Param room_amenities query string false "Comma-separated amenity codes the room must all offer"
+// @Param lat query number false "Latitude of the search centre"
+// @Param lng query number false "Longitude of the search centre"
+// @Param radius_km query number false "Search radius in kilometres" default(10)
+// @Param min_lat query number false "Bounding box south edge"
+// @Param min_lng query number false "Bounding box west edge"
+// @Param max_lat query number false "Bounding box north edge"
+// @Param max_lng query number false "Bounding box east edge"
+// @Param page query int false "Page number" default(1)
+// @Param cursor query string false "Cursor from a previous page's meta.next_cursor"
+// @Param limit query int false "Page size (max 100)" default(20)
+// @Param sort query string false "Sort field" Enums(price, name, distance, relevance, rating)
+// @Param order query string false "Sort order" Enums(asc, desc)
+// @Success 200 {object} jsonres.SuccessResponse{data=[]response.HotelSearchResponse,meta=pagination.Meta}
+// @Failure 400 {object} jsonres.ErrorResponse
+// @Router /hotels/search [get]
+func (h *HotelHandler) SearchHotels(c echo.Context) error {
+	var req request.SearchHotelsRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"BAD_REQUEST", "Invalid query parameters", err.Error(),
+		))
+	}
+
+	if errs := validator.Validate(&req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"VALIDATION_ERROR", "Validation failed", errs,
+		))
+	}
+
+	opts := repository.HotelSearchOptions
+	if strings.TrimSpace(req.Q) != "" && c.QueryParam("sort") == "" {
+		opts = repository.HotelTextSearchOptions
+	}
+	query, err := listQuery(c, opts)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"VALIDATION_ERROR", "Validation failed", []string{err.Error()},
+		))
+	}
+
+	filter := repository.HotelSearchFilter{
+		Text:          req.Q,
+		Destination:   req.Destination,
+		Guests:        req.Guests,
+		MinPrice:      req.MinPrice,
+		MaxPrice:      req.MaxPrice,
+		Amenities:     queryList(c, "amenities"),
+		RoomAmenities: queryList(c, "room_amenities"),
+		Query:         query,
+	}
+	if req.Latitude != nil {
+		filter.Near = &repository.GeoPoint{Latitude: *req.Latitude, Longitude: *req.Longitude}
+		filter.RadiusKm = req.RadiusKm
+	}
+	if req.MinLat != nil {
+		filter.Bounds = &repository.GeoBounds{
+			MinLatitude:  *req.MinLat,
+			MinLongitude: *req.MinLng,
+			MaxLatitude:  *req.MaxLat,
+			MaxLongitude: *req.MaxLng,
+		}
+	}
+	if req.CheckIn != "" {
+		checkIn, _ := time.Parse(time.DateOnly, req.CheckIn)
+		checkOut, _ := time.Parse(time.DateOnly, req.CheckOut)
+		filter.CheckIn, filter.CheckOut = &checkIn, &checkOut
+	}
+
+	results, total, err := h.hotelService.SearchHotels(filter)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"SEARCH_FAILED", err.Error(), nil,
+		))
+	}
+
+	hotelResponses := make([]dto.HotelSearchResponse, len(results))
+	for i, result := range results {
+		hotelResponses[i] = dto.ToHotelSearchResponse(&result)
+	}
+
+	return c.JSON(http.StatusOK, jsonres.SuccessWithMeta(
+		"Hotels retrieved successfully", hotelResponses, query.Meta(total, len(results)),
+	))
+}
+
+// AutocompleteHotels godoc
+// @Summary Autocomplete destinations and hotels
+// @Description Suggest destinations and hotel names for a partial, possibly misspelled, query
+// @Tags hotels
+// @Accept json
+// @Produce json
+// @Param q query string true "Partial text (at least 2 characters)"
+// @Param limit query int false "Maximum suggestions (max 20)" default(10)
+// @Success 200 {object} jsonres.SuccessResponse{data=[]response.SearchSuggestionResponse}
+// @Failure 400 {object} jsonres.ErrorResponse
+// @Router /hotels/autocomplete [get]
+func (h *HotelHandler) AutocompleteHotels(c echo.Context) error {
+	limit, err := queryInt(c, "limit", 10)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"VALIDATION_ERROR", "Validation failed", []string{err.Error()},
+		))
+	}
+
+	suggestions, err := h.hotelService.Autocomplete(c.QueryParam("q"), limit)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, jsonres.Error(
+			"FETCH_FAILED", "Failed to fetch suggestions", err.Error(),
+		))
+	}
+
+	suggestionResponses := make([]dto.SearchSuggestionResponse, len(suggestions))
+	for i, suggestion := range suggestions {
+		suggestionResponses[i] = dto.ToSearchSuggestionResponse(&suggestion)
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success(
+		"Suggestions retrieved successfully", suggestionResponses,
+	))
+}
+
+// deletionError maps the errors of deleting, restoring and purging hotels and
+// rooms to responses.
+func deletionError(c echo.Context, code, message string, err error) error {
+	switch {
+	case errors.Is(err, service.ErrHotelNotFound), errors.Is(err, service.ErrRoomNotFound):
+		return c.JSON(http.StatusNotFound, jsonres.Error(
+			"NOT_FOUND", err.Error(), nil,
+		))
+	case errors.Is(err, service.ErrHotelForbidden):
+		return c.JSON(http.StatusForbidden, jsonres.Error(
+			"FORBIDDEN", err.Error(), nil,
+		))
+	case errors.Is(err, service.ErrHasUpcomingBookings), errors.Is(err, service.ErrHasBookingHistory),
+		errors.Is(err, service.ErrNotDeleted), errors.Is(err, service.ErrHotelDeleted):
+		return c.JSON(http.StatusConflict, jsonres.Error(
+			"CONFLICT", err.Error(), nil,
+		))
+	default:
+		return c.JSON(http.StatusInternalServerError, jsonres.Error(
+			code, message, err.Error(),
+		))
+	}
+}
+
+// hotelFields and setHotelFields convert between a hotel and the fields a
+// client may update.
+func hotelFields(hotel *domain.Hotel) request.UpdateHotelRequest {
+	return request.UpdateHotelRequest{
+		Name:        hotel.Name,
+		Location:    hotel.Location,
+		Address:     hotel.Address,
+		City:        hotel.City,
+		Country:     hotel.Country,
+		Latitude:    hotel.Latitude,
+		Longitude:   hotel.Longitude,
+		Description: hotel.Description,
+	}
+}
+
+func setHotelFields(hotel *domain.Hotel, req request.UpdateHotelRequest) {
+	hotel.Name = req.Name
+	hotel.Location = req.Location
+	hotel.Address = req.Address
+	hotel.City = req.City
+	hotel.Country = req.Country
+	hotel.Latitude = req.Latitude
+	hotel.Longitude = req.Longitude
+	hotel.Description = req.Description
+}
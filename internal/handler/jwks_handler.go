@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"hotel-booking-api/pkg/jwtkeys"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type JWKSHandler struct {
+	keys *jwtkeys.KeySet
+}
+
+func NewJWKSHandler(keys *jwtkeys.KeySet) *JWKSHandler {
+	return &JWKSHandler{
+		keys: keys,
+	}
+}
+
+// GetJWKS godoc
+// @Summary JSON Web Key Set
+// @Description Public keys that verify access tokens, for services that accept them. Served unwrapped, as RFC 7517 requires.
+// @Tags auth
+// @Produce json
+// @Success 200 {object} jwtkeys.JWKS
+// @Router /.well-known/jwks.json [get]
+func (h *JWKSHandler) GetJWKS(c echo.Context) error {
+	// Verifiers may cache the set; new keys are published before they sign.
+	c.Response().Header().Set("Cache-Control", "public, max-age=300")
+
+	return c.JSON(http.StatusOK, h.keys.JWKS())
+}
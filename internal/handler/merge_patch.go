@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hotel-booking-api/pkg/jsonres"
+	"hotel-booking-api/pkg/mergepatch"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"slices"
+
+	"github.com/labstack/echo/v4"
+)
+
+const mimeMergePatch = "application/merge-patch+json"
+
+var errUnsupportedPatchType = errors.New("send the patch as " + mimeMergePatch)
+
+// mergePatch applies the request body, a JSON Merge Patch (RFC 7396), to
+// fields, a pointer to a struct of a resource's editable JSON fields. A null
+// member clears its field. It returns the JSON names of the fields whose
+// value changed, sorted.
+func mergePatch(c echo.Context, fields any) ([]string, error) {
+	mediaType, _, _ := mime.ParseMediaType(c.Request().Header.Get(echo.HeaderContentType))
+	if mediaType != mimeMergePatch && mediaType != echo.MIMEApplicationJSON {
+		return nil, errUnsupportedPatchType
+	}
+
+	patch, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(bytes.TrimSpace(patch), []byte("{")) {
+		return nil, fmt.Errorf("%w: the patch must be a JSON object", mergepatch.ErrInvalidPatch)
+	}
+
+	original, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	merged, err := mergepatch.Apply(original, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode into a zero value so fields the patch removed end up empty.
+	patched := reflect.New(reflect.TypeOf(fields).Elem())
+	decoder := json.NewDecoder(bytes.NewReader(merged))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(patched.Interface()); err != nil {
+		return nil, fmt.Errorf("%w: %v", mergepatch.ErrInvalidPatch, err)
+	}
+	reflect.ValueOf(fields).Elem().Set(patched.Elem())
+
+	return changedFields(original, fields)
+}
+
+// changedFields lists the JSON fields of after that differ from before.
+func changedFields(before []byte, after any) ([]string, error) {
+	encoded, err := json.Marshal(after)
+	if err != nil {
+		return nil, err
+	}
+
+	var from, to map[string]any
+	if err := json.Unmarshal(before, &from); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(encoded, &to); err != nil {
+		return nil, err
+	}
+
+	changed := []string{}
+	for name, value := range to {
+		if !reflect.DeepEqual(value, from[name]) {
+			changed = append(changed, name)
+		}
+	}
+	slices.Sort(changed)
+
+	return changed, nil
+}
+
+// patchError answers a patch that could not be applied.
+func patchError(c echo.Context, err error) error {
+	if errors.Is(err, errUnsupportedPatchType) {
+		return c.JSON(http.StatusUnsupportedMediaType, jsonres.Error(
+			"UNSUPPORTED_MEDIA_TYPE", err.Error(), nil,
+		))
+	}
+
+	return c.JSON(http.StatusBadRequest, jsonres.Error(
+		"BAD_REQUEST", "Invalid merge patch", err.Error(),
+	))
+}
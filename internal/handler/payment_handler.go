@@ -32,7 +32,7 @@ func (h *PaymentHandler) HandleWebhook(c echo.Context) error {
 		))
 	}
 
-	if err := h.paymentService.HandlePaymentCallback(req.BookingID, req.TransactionID, req.Status); err != nil {
+	if err := h.paymentService.HandlePaymentCallback(actorFromContext(c), req.BookingID, req.TransactionID, req.Status); err != nil {
 		return c.JSON(http.StatusInternalServerError, jsonres.Error(
 			"WEBHOOK_FAILED", err.Error(), nil,
 		))
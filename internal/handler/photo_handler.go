@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"errors"
+	"hotel-booking-api/internal/domain"
+	"hotel-booking-api/internal/dto/request"
+	dto "hotel-booking-api/internal/dto/response"
+	"hotel-booking-api/internal/service"
+	"hotel-booking-api/pkg/jsonres"
+	"hotel-booking-api/pkg/validator"
+	"io"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type PhotoHandler struct {
+	photoService service.PhotoService
+}
+
+func NewPhotoHandler(photoService service.PhotoService) *PhotoHandler {
+	return &PhotoHandler{
+		photoService: photoService,
+	}
+}
+
+// UploadHotelPhoto godoc
+// @Summary Upload a hotel photo
+// @Description Upload a JPEG or PNG image for a hotel; the first photo becomes the cover (Admin only)
+// @Tags photos
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Hotel ID"
+// @Param file formData file true "Image file"
+// @Success 201 {object} jsonres.SuccessResponse{data=response.PhotoResponse}
+// @Failure 400 {object} jsonres.ErrorResponse
+// @Failure 413 {object} jsonres.ErrorResponse
+// @Failure 415 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /hotels/{id}/photos [post]
+func (h *PhotoHandler) UploadHotelPhoto(c echo.Context) error {
+	return h.upload(c, h.photoService.UploadHotelPhoto)
+}
+
+// UploadRoomPhoto godoc
+// @Summary Upload a room photo
+// @Description Upload a JPEG or PNG image for a room type; the first photo becomes the cover (Admin only)
+// @Tags photos
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Room ID"
+// @Param file formData file true "Image file"
+// @Success 201 {object} jsonres.SuccessResponse{data=response.PhotoResponse}
+// @Failure 400 {object} jsonres.ErrorResponse
+// @Failure 413 {object} jsonres.ErrorResponse
+// @Failure 415 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /rooms/{id}/photos [post]
+func (h *PhotoHandler) UploadRoomPhoto(c echo.Context) error {
+	return h.upload(c, h.photoService.UploadRoomPhoto)
+}
+
+func (h *PhotoHandler) upload(c echo.Context, upload func(string, io.Reader) (*domain.Photo, error)) error {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"BAD_REQUEST", "An image file is required in the 'file' field", err.Error(),
+		))
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"BAD_REQUEST", "Failed to read uploaded file", err.Error(),
+		))
+	}
+	defer file.Close()
+
+	photo, err := upload(c.Param("id"), file)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrImageTooLarge):
+			return c.JSON(http.StatusRequestEntityTooLarge, jsonres.Error(
+				"FILE_TOO_LARGE", err.Error(), nil,
+			))
+		case errors.Is(err, service.ErrUnsupportedImage):
+			return c.JSON(http.StatusUnsupportedMediaType, jsonres.Error(
+				"UNSUPPORTED_MEDIA_TYPE", err.Error(), nil,
+			))
+		}
+
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"UPLOAD_FAILED", err.Error(), nil,
+		))
+	}
+
+	return c.JSON(http.StatusCreated, jsonres.Success(
+		"Photo uploaded successfully", dto.ToPhotoResponse(photo),
+	))
+}
+
+// ReorderHotelPhotos godoc
+// @Summary Reorder hotel photos
+// @Description Set the display order of a hotel's photos (Admin only)
+// @Tags photos
+// @Accept json
+// @Produce json
+// @Param id path string true "Hotel ID"
+// @Param request body request.ReorderPhotosRequest true "Every photo ID in the new order"
+// @Success 200 {object} jsonres.SuccessResponse{data=[]response.PhotoResponse}
+// @Failure 400 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /hotels/{id}/photos/order [put]
+func (h *PhotoHandler) ReorderHotelPhotos(c echo.Context) error {
+	return h.reorder(c, h.photoService.ReorderHotelPhotos)
+}
+
+// ReorderRoomPhotos godoc
+// @Summary Reorder room photos
+// @Description Set the display order of a room type's photos (Admin only)
+// @Tags photos
+// @Accept json
+// @Produce json
+// @Param id path string true "Room ID"
+// @Param request body request.ReorderPhotosRequest true "Every photo ID in the new order"
+// @Success 200 {object} jsonres.SuccessResponse{data=[]response.PhotoResponse}
+// @Failure 400 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /rooms/{id}/photos/order [put]
+func (h *PhotoHandler) ReorderRoomPhotos(c echo.Context) error {
+	return h.reorder(c, h.photoService.ReorderRoomPhotos)
+}
+
+func (h *PhotoHandler) reorder(c echo.Context, reorder func(string, []string) ([]domain.Photo, error)) error {
+	var req request.ReorderPhotosRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"BAD_REQUEST", "Invalid request body", err.Error(),
+		))
+	}
+
+	if errs := validator.Validate(&req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"VALIDATION_ERROR", "Validation failed", errs,
+		))
+	}
+
+	photos, err := reorder(c.Param("id"), req.PhotoIDs)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"UPDATE_FAILED", err.Error(), nil,
+		))
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success(
+		"Photos reordered successfully", dto.ToPhotoResponses(photos),
+	))
+}
+
+// SetCoverPhoto godoc
+// @Summary Set cover photo
+// @Description Make a photo the cover of its hotel or room type (Admin only)
+// @Tags photos
+// @Accept json
+// @Produce json
+// @Param id path string true "Photo ID"
+// @Success 200 {object} jsonres.SuccessResponse{data=response.PhotoResponse}
+// @Failure 404 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /photos/{id}/cover [patch]
+func (h *PhotoHandler) SetCoverPhoto(c echo.Context) error {
+	photo, err := h.photoService.SetCoverPhoto(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, jsonres.Error(
+			"UPDATE_FAILED", err.Error(), nil,
+		))
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success(
+		"Cover photo updated successfully", dto.ToPhotoResponse(photo),
+	))
+}
+
+// DeletePhoto godoc
+// @Summary Delete a photo
+// @Description Delete a photo and its stored files (Admin only)
+// @Tags photos
+// @Accept json
+// @Produce json
+// @Param id path string true "Photo ID"
+// @Success 200 {object} jsonres.SuccessResponse
+// @Failure 404 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /photos/{id} [delete]
+func (h *PhotoHandler) DeletePhoto(c echo.Context) error {
+	if err := h.photoService.DeletePhoto(c.Param("id")); err != nil {
+		return c.JSON(http.StatusNotFound, jsonres.Error(
+			"DELETE_FAILED", err.Error(), nil,
+		))
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success(
+		"Photo deleted successfully", nil,
+	))
+}
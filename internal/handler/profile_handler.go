@@ -0,0 +1,213 @@
+package handler
+
+import (
+	"errors"
+	"hotel-booking-api/internal/dto/request"
+	dto "hotel-booking-api/internal/dto/response"
+	"hotel-booking-api/internal/service"
+	"hotel-booking-api/pkg/jsonres"
+	"hotel-booking-api/pkg/validator"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ProfileHandler struct {
+	profileService service.ProfileService
+	accountService service.AccountService
+}
+
+func NewProfileHandler(profileService service.ProfileService, accountService service.AccountService) *ProfileHandler {
+	return &ProfileHandler{
+		profileService: profileService,
+		accountService: accountService,
+	}
+}
+
+// GetProfile godoc
+// @Summary Get my profile
+// @Description Get the authenticated user's account and preferences
+// @Tags profile
+// @Produce json
+// @Success 200 {object} jsonres.SuccessResponse{data=response.ProfileResponse}
+// @Failure 404 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /users/me [get]
+func (h *ProfileHandler) GetProfile(c echo.Context) error {
+	user, err := h.profileService.GetProfile(c.Get("userID").(string))
+	if err != nil {
+		return profileError(c, "FETCH_FAILED", err)
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success(
+		"Profile retrieved successfully", dto.ToProfileResponse(user),
+	))
+}
+
+// UpdateProfile godoc
+// @Summary Update my profile
+// @Description Replace the authenticated user's name, phone and preferred currency and language. Omitted optional fields are cleared
+// @Tags profile
+// @Accept json
+// @Produce json
+// @Param request body request.UpdateProfileRequest true "Profile details"
+// @Success 200 {object} jsonres.SuccessResponse{data=response.ProfileResponse}
+// @Failure 400 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /users/me [put]
+func (h *ProfileHandler) UpdateProfile(c echo.Context) error {
+	var req request.UpdateProfileRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"BAD_REQUEST", "Invalid request body", err.Error(),
+		))
+	}
+
+	if errs := validator.Validate(&req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"VALIDATION_ERROR", "Validation failed", errs,
+		))
+	}
+
+	user, err := h.profileService.UpdateProfile(c.Get("userID").(string), service.ProfileUpdate{
+		Name:              req.Name,
+		Phone:             req.Phone,
+		PreferredCurrency: req.PreferredCurrency,
+		PreferredLanguage: req.PreferredLanguage,
+	})
+	if err != nil {
+		return profileError(c, "UPDATE_FAILED", err)
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success(
+		"Profile updated successfully", dto.ToProfileResponse(user),
+	))
+}
+
+// ChangePassword godoc
+// @Summary Change my password
+// @Description Set a new password after confirming the current one. All sessions, including this one, are signed out
+// @Tags profile
+// @Accept json
+// @Produce json
+// @Param request body request.ChangePasswordRequest true "Current and new password"
+// @Success 200 {object} jsonres.SuccessResponse
+// @Failure 400 {object} jsonres.ErrorResponse
+// @Failure 403 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /users/me/password [put]
+func (h *ProfileHandler) ChangePassword(c echo.Context) error {
+	var req request.ChangePasswordRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"BAD_REQUEST", "Invalid request body", err.Error(),
+		))
+	}
+
+	if errs := validator.Validate(&req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"VALIDATION_ERROR", "Validation failed", errs,
+		))
+	}
+
+	if err := h.accountService.ChangePassword(c.Get("userID").(string), req.CurrentPassword, req.Password); err != nil {
+		return profileError(c, "UPDATE_FAILED", err)
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success(
+		"Password changed; please sign in again", nil,
+	))
+}
+
+// ChangeEmail godoc
+// @Summary Change my email address
+// @Description Email a confirmation link to the new address. The account keeps its current address until the link is used
+// @Tags profile
+// @Accept json
+// @Produce json
+// @Param request body request.ChangeEmailRequest true "New email and current password"
+// @Success 202 {object} jsonres.SuccessResponse
+// @Failure 400 {object} jsonres.ErrorResponse
+// @Failure 403 {object} jsonres.ErrorResponse
+// @Failure 409 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /users/me/email [put]
+func (h *ProfileHandler) ChangeEmail(c echo.Context) error {
+	var req request.ChangeEmailRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"BAD_REQUEST", "Invalid request body", err.Error(),
+		))
+	}
+
+	if errs := validator.Validate(&req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"VALIDATION_ERROR", "Validation failed", errs,
+		))
+	}
+
+	if err := h.accountService.RequestEmailChange(c.Get("userID").(string), req.Password, req.Email); err != nil {
+		return profileError(c, "UPDATE_FAILED", err)
+	}
+
+	return c.JSON(http.StatusAccepted, jsonres.Success(
+		"A confirmation link has been sent to the new address", nil,
+	))
+}
+
+// DeleteAccount godoc
+// @Summary Delete my account
+// @Description Close the account after confirming the password. Personal data is anonymised; past bookings are kept without it. Upcoming bookings must be cancelled first
+// @Tags profile
+// @Accept json
+// @Produce json
+// @Param request body request.DeleteAccountRequest true "Current password"
+// @Success 200 {object} jsonres.SuccessResponse
+// @Failure 400 {object} jsonres.ErrorResponse
+// @Failure 403 {object} jsonres.ErrorResponse
+// @Failure 409 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /users/me [delete]
+func (h *ProfileHandler) DeleteAccount(c echo.Context) error {
+	var req request.DeleteAccountRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"BAD_REQUEST", "Invalid request body", err.Error(),
+		))
+	}
+
+	if errs := validator.Validate(&req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"VALIDATION_ERROR", "Validation failed", errs,
+		))
+	}
+
+	if err := h.profileService.DeleteAccount(c.Get("userID").(string), req.Password); err != nil {
+		return profileError(c, "DELETE_FAILED", err)
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success(
+		"Account deleted successfully", nil,
+	))
+}
+
+func profileError(c echo.Context, code string, err error) error {
+	switch {
+	case errors.Is(err, service.ErrUserNotFound):
+		return c.JSON(http.StatusNotFound, jsonres.Error(
+			"NOT_FOUND", err.Error(), nil,
+		))
+	case errors.Is(err, service.ErrWrongPassword):
+		return c.JSON(http.StatusForbidden, jsonres.Error(
+			"INVALID_PASSWORD", err.Error(), nil,
+		))
+	case errors.Is(err, service.ErrEmailTaken), errors.Is(err, service.ErrLastAdmin), errors.Is(err, service.ErrUpcomingBookings):
+		return c.JSON(http.StatusConflict, jsonres.Error(
+			"CONFLICT", err.Error(), nil,
+		))
+	default:
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			code, err.Error(), nil,
+		))
+	}
+}
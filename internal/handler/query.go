@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"fmt"
+	"hotel-booking-api/pkg/pagination"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// queryTime parses an optional date (2006-01-02) or RFC 3339 timestamp from
+// the query string. A missing parameter yields nil.
+func queryTime(c echo.Context, name string) (*time.Time, error) {
+	raw := c.QueryParam(name)
+	if raw == "" {
+		return nil, nil
+	}
+
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if t, err := time.Parse(layout, raw); err == nil {
+			return &t, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%s must be a date (YYYY-MM-DD) or RFC 3339 timestamp", name)
+}
+
+// queryInt parses an optional integer from the query string, falling back to
+// def when the parameter is missing.
+func queryInt(c echo.Context, name string, def int) (int, error) {
+	raw := c.QueryParam(name)
+	if raw == "" {
+		return def, nil
+	}
+
+	n, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be an integer", name)
+	}
+
+	return n, nil
+}
+
+// queryList splits a comma-separated query parameter, dropping blanks.
+func queryList(c echo.Context, name string) []string {
+	var values []string
+	for _, value := range strings.Split(c.QueryParam(name), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+// listQuery reads the shared page/cursor, limit, sort and order parameters
+// and normalizes them against opts, the endpoint's allow-list. Every other
+// query parameter is a candidate filter; only the ones opts allows are kept.
+// The returned query is what the repository pages with, so the handler must
+// build the response meta from it too.
+func listQuery(c echo.Context, opts pagination.Options) (pagination.Query, error) {
+	page, err := queryInt(c, "page", 1)
+	if err != nil {
+		return pagination.Query{}, err
+	}
+
+	limit, err := queryInt(c, "limit", pagination.DefaultLimit)
+	if err != nil {
+		return pagination.Query{}, err
+	}
+
+	filters := make(map[string]string)
+	for name, values := range c.QueryParams() {
+		if len(values) > 0 {
+			filters[name] = values[0]
+		}
+	}
+
+	query := pagination.Query{
+		Page:    page,
+		Limit:   limit,
+		Cursor:  c.QueryParam("cursor"),
+		Sort:    c.QueryParam("sort"),
+		Order:   c.QueryParam("order"),
+		Filters: filters,
+	}
+	if err := query.Normalize(opts); err != nil {
+		return pagination.Query{}, err
+	}
+
+	return query, nil
+}
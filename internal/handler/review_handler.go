@@ -0,0 +1,268 @@
+package handler
+
+import (
+	"errors"
+	"hotel-booking-api/internal/dto/request"
+	dto "hotel-booking-api/internal/dto/response"
+	"hotel-booking-api/internal/repository"
+	"hotel-booking-api/internal/service"
+	"hotel-booking-api/pkg/jsonres"
+	"hotel-booking-api/pkg/validator"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ReviewHandler struct {
+	reviewService service.ReviewService
+}
+
+func NewReviewHandler(reviewService service.ReviewService) *ReviewHandler {
+	return &ReviewHandler{
+		reviewService: reviewService,
+	}
+}
+
+// CreateReview godoc
+// @Summary Review a stay
+// @Description Rate and review a completed booking of the authenticated user; each booking can be reviewed once
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path string true "Booking ID"
+// @Param request body request.CreateReviewRequest true "Review details"
+// @Success 201 {object} jsonres.SuccessResponse{data=response.ReviewResponse}
+// @Failure 400 {object} jsonres.ErrorResponse
+// @Failure 403 {object} jsonres.ErrorResponse
+// @Failure 404 {object} jsonres.ErrorResponse
+// @Failure 409 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /bookings/{id}/review [post]
+func (h *ReviewHandler) CreateReview(c echo.Context) error {
+	userID := c.Get("userID").(string)
+
+	var req request.CreateReviewRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"BAD_REQUEST", "Invalid request body", err.Error(),
+		))
+	}
+
+	if errs := validator.Validate(&req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"VALIDATION_ERROR", "Validation failed", errs,
+		))
+	}
+
+	review, err := h.reviewService.CreateReview(userID, c.Param("id"), service.ReviewInput{
+		Rating:      req.Rating,
+		Cleanliness: req.Cleanliness,
+		Location:    req.Location,
+		Service:     req.Service,
+		Comment:     req.Comment,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrBookingNotFound):
+			return c.JSON(http.StatusNotFound, jsonres.Error(
+				"NOT_FOUND", "Booking not found", nil,
+			))
+		case errors.Is(err, service.ErrBookingForbidden):
+			return c.JSON(http.StatusForbidden, jsonres.Error(
+				"FORBIDDEN", err.Error(), nil,
+			))
+		case errors.Is(err, service.ErrReviewExists):
+			return c.JSON(http.StatusConflict, jsonres.Error(
+				"REVIEW_EXISTS", err.Error(), nil,
+			))
+		}
+
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"CREATE_FAILED", err.Error(), nil,
+		))
+	}
+
+	return c.JSON(http.StatusCreated, jsonres.Success(
+		"Review submitted successfully", dto.ToReviewResponse(review),
+	))
+}
+
+// ListHotelReviews godoc
+// @Summary List hotel reviews
+// @Description Get a page of published reviews for a hotel
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path string true "Hotel ID"
+// @Param min_rating query int false "Minimum rating"
+// @Param max_rating query int false "Maximum rating"
+// @Param page query int false "Page number" default(1)
+// @Param cursor query string false "Cursor from a previous page's meta.next_cursor"
+// @Param limit query int false "Page size (max 100)" default(20)
+// @Param sort query string false "Sort field" Enums(created_at, rating)
+// @Param order query string false "Sort order" Enums(asc, desc)
+// @Success 200 {object} jsonres.SuccessResponse{data=[]response.ReviewResponse,meta=pagination.Meta}
+// @Failure 400 {object} jsonres.ErrorResponse
+// @Router /hotels/{id}/reviews [get]
+func (h *ReviewHandler) ListHotelReviews(c echo.Context) error {
+	query, err := listQuery(c, repository.HotelReviewListOptions)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"VALIDATION_ERROR", "Validation failed", []string{err.Error()},
+		))
+	}
+
+	reviews, total, err := h.reviewService.ListHotelReviews(c.Param("id"), query)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, jsonres.Error(
+			"FETCH_FAILED", "Failed to fetch reviews", err.Error(),
+		))
+	}
+
+	reviewResponses := make([]dto.ReviewResponse, len(reviews))
+	for i := range reviews {
+		reviewResponses[i] = dto.ToReviewResponse(&reviews[i])
+	}
+
+	return c.JSON(http.StatusOK, jsonres.SuccessWithMeta(
+		"Reviews retrieved successfully", reviewResponses, query.Meta(total, len(reviews)),
+	))
+}
+
+// ListReviews godoc
+// @Summary List reviews for moderation
+// @Description Get a page of reviews across all hotels, including hidden and flagged ones (Admin only)
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param status query string false "Review status" Enums(VISIBLE, HIDDEN, FLAGGED)
+// @Param hotel_id query string false "Hotel ID"
+// @Param min_rating query int false "Minimum rating"
+// @Param max_rating query int false "Maximum rating"
+// @Param page query int false "Page number" default(1)
+// @Param cursor query string false "Cursor from a previous page's meta.next_cursor"
+// @Param limit query int false "Page size (max 100)" default(20)
+// @Param sort query string false "Sort field" Enums(created_at, rating)
+// @Param order query string false "Sort order" Enums(asc, desc)
+// @Success 200 {object} jsonres.SuccessResponse{data=[]response.AdminReviewResponse,meta=pagination.Meta}
+// @Failure 400 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/reviews [get]
+func (h *ReviewHandler) ListReviews(c echo.Context) error {
+	query, err := listQuery(c, repository.ReviewListOptions)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"VALIDATION_ERROR", "Validation failed", []string{err.Error()},
+		))
+	}
+
+	reviews, total, err := h.reviewService.ListReviews(query)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, jsonres.Error(
+			"FETCH_FAILED", "Failed to fetch reviews", err.Error(),
+		))
+	}
+
+	reviewResponses := make([]dto.AdminReviewResponse, len(reviews))
+	for i := range reviews {
+		reviewResponses[i] = dto.ToAdminReviewResponse(&reviews[i])
+	}
+
+	return c.JSON(http.StatusOK, jsonres.SuccessWithMeta(
+		"Reviews retrieved successfully", reviewResponses, query.Meta(total, len(reviews)),
+	))
+}
+
+// ReplyToReview godoc
+// @Summary Reply to a review
+// @Description Publish the hotel's reply to a guest review, replacing any earlier reply (admins and the hotel's managers)
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path string true "Review ID"
+// @Param request body request.ReplyReviewRequest true "Reply"
+// @Success 200 {object} jsonres.SuccessResponse{data=response.AdminReviewResponse}
+// @Failure 400 {object} jsonres.ErrorResponse
+// @Failure 403 {object} jsonres.ErrorResponse
+// @Failure 404 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /reviews/{id}/reply [put]
+func (h *ReviewHandler) ReplyToReview(c echo.Context) error {
+	var req request.ReplyReviewRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"BAD_REQUEST", "Invalid request body", err.Error(),
+		))
+	}
+
+	if errs := validator.Validate(&req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"VALIDATION_ERROR", "Validation failed", errs,
+		))
+	}
+
+	review, err := h.reviewService.ReplyToReview(actorFromContext(c), c.Param("id"), req.Reply)
+	if errors.Is(err, service.ErrReviewNotFound) {
+		return c.JSON(http.StatusNotFound, jsonres.Error(
+			"NOT_FOUND", "Review not found", nil,
+		))
+	}
+	if errors.Is(err, service.ErrHotelForbidden) {
+		return c.JSON(http.StatusForbidden, jsonres.Error(
+			"FORBIDDEN", err.Error(), nil,
+		))
+	}
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"UPDATE_FAILED", err.Error(), nil,
+		))
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success(
+		"Reply saved successfully", dto.ToAdminReviewResponse(review),
+	))
+}
+
+// ModerateReview godoc
+// @Summary Moderate a review
+// @Description Hide, flag or restore a review; only visible reviews count towards the hotel rating (Admin only)
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path string true "Review ID"
+// @Param request body request.ModerateReviewRequest true "Moderation decision"
+// @Success 200 {object} jsonres.SuccessResponse{data=response.AdminReviewResponse}
+// @Failure 400 {object} jsonres.ErrorResponse
+// @Failure 404 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/reviews/{id}/moderation [patch]
+func (h *ReviewHandler) ModerateReview(c echo.Context) error {
+	var req request.ModerateReviewRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"BAD_REQUEST", "Invalid request body", err.Error(),
+		))
+	}
+
+	if errs := validator.Validate(&req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"VALIDATION_ERROR", "Validation failed", errs,
+		))
+	}
+
+	review, err := h.reviewService.ModerateReview(c.Param("id"), req.Status, req.Note)
+	if errors.Is(err, service.ErrReviewNotFound) {
+		return c.JSON(http.StatusNotFound, jsonres.Error(
+			"NOT_FOUND", "Review not found", nil,
+		))
+	}
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"UPDATE_FAILED", err.Error(), nil,
+		))
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success(
+		"Review moderated successfully", dto.ToAdminReviewResponse(review),
+	))
+}
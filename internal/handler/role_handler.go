@@ -0,0 +1,272 @@
+package handler
+
+import (
+	"errors"
+	"hotel-booking-api/internal/dto/request"
+	dto "hotel-booking-api/internal/dto/response"
+	"hotel-booking-api/internal/service"
+	"hotel-booking-api/pkg/jsonres"
+	"hotel-booking-api/pkg/validator"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type RoleHandler struct {
+	roleService service.RoleService
+}
+
+func NewRoleHandler(roleService service.RoleService) *RoleHandler {
+	return &RoleHandler{
+		roleService: roleService,
+	}
+}
+
+// ListPermissions godoc
+// @Summary List permissions
+// @Description Get every permission that roles can be composed from
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Success 200 {object} jsonres.SuccessResponse{data=[]response.PermissionResponse}
+// @Failure 500 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/permissions [get]
+func (h *RoleHandler) ListPermissions(c echo.Context) error {
+	permissions, err := h.roleService.ListPermissions()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, jsonres.Error(
+			"FETCH_FAILED", "Failed to fetch permissions", err.Error(),
+		))
+	}
+
+	permissionResponses := make([]dto.PermissionResponse, len(permissions))
+	for i := range permissions {
+		permissionResponses[i] = dto.ToPermissionResponse(&permissions[i])
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success(
+		"Permissions retrieved successfully", permissionResponses,
+	))
+}
+
+// ListRoles godoc
+// @Summary List roles
+// @Description Get the system and custom roles with their permissions
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Success 200 {object} jsonres.SuccessResponse{data=[]response.RoleResponse}
+// @Failure 500 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/roles [get]
+func (h *RoleHandler) ListRoles(c echo.Context) error {
+	roles, err := h.roleService.ListRoles()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, jsonres.Error(
+			"FETCH_FAILED", "Failed to fetch roles", err.Error(),
+		))
+	}
+
+	roleResponses := make([]dto.RoleResponse, len(roles))
+	for i := range roles {
+		roleResponses[i] = dto.ToRoleResponse(&roles[i])
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success(
+		"Roles retrieved successfully", roleResponses,
+	))
+}
+
+// CreateRole godoc
+// @Summary Create a role
+// @Description Create a custom role from a set of permissions, all of which the caller must hold
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Param request body request.RoleRequest true "Role details"
+// @Success 201 {object} jsonres.SuccessResponse{data=response.RoleResponse}
+// @Failure 400 {object} jsonres.ErrorResponse
+// @Failure 403 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/roles [post]
+func (h *RoleHandler) CreateRole(c echo.Context) error {
+	var req request.RoleRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"BAD_REQUEST", "Invalid request body", err.Error(),
+		))
+	}
+
+	if errs := validator.Validate(&req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"VALIDATION_ERROR", "Validation failed", errs,
+		))
+	}
+
+	role, err := h.roleService.CreateRole(actorFromContext(c), req.Name, req.Description, req.Permissions)
+	if errors.Is(err, service.ErrPermissionDenied) {
+		return c.JSON(http.StatusForbidden, jsonres.Error(
+			"FORBIDDEN", err.Error(), nil,
+		))
+	}
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"CREATE_FAILED", err.Error(), nil,
+		))
+	}
+
+	return c.JSON(http.StatusCreated, jsonres.Success(
+		"Role created successfully", dto.ToRoleResponse(role),
+	))
+}
+
+// UpdateRole godoc
+// @Summary Update a role
+// @Description Rename a custom role or replace its permissions; system roles are read-only and the caller must hold the role's old and new permissions
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Param id path string true "Role ID"
+// @Param request body request.RoleRequest true "Role details"
+// @Success 200 {object} jsonres.SuccessResponse{data=response.RoleResponse}
+// @Failure 400 {object} jsonres.ErrorResponse
+// @Failure 403 {object} jsonres.ErrorResponse
+// @Failure 404 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/roles/{id} [put]
+func (h *RoleHandler) UpdateRole(c echo.Context) error {
+	var req request.RoleRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"BAD_REQUEST", "Invalid request body", err.Error(),
+		))
+	}
+
+	if errs := validator.Validate(&req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"VALIDATION_ERROR", "Validation failed", errs,
+		))
+	}
+
+	role, err := h.roleService.UpdateRole(actorFromContext(c), c.Param("id"), req.Name, req.Description, req.Permissions)
+	if errors.Is(err, service.ErrPermissionDenied) {
+		return c.JSON(http.StatusForbidden, jsonres.Error(
+			"FORBIDDEN", err.Error(), nil,
+		))
+	}
+	if errors.Is(err, service.ErrRoleNotFound) {
+		return c.JSON(http.StatusNotFound, jsonres.Error(
+			"NOT_FOUND", "Role not found", nil,
+		))
+	}
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"UPDATE_FAILED", err.Error(), nil,
+		))
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success(
+		"Role updated successfully", dto.ToRoleResponse(role),
+	))
+}
+
+// DeleteRole godoc
+// @Summary Delete a role
+// @Description Delete a custom role, removing it from every user
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Param id path string true "Role ID"
+// @Success 200 {object} jsonres.SuccessResponse
+// @Failure 400 {object} jsonres.ErrorResponse
+// @Failure 404 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/roles/{id} [delete]
+func (h *RoleHandler) DeleteRole(c echo.Context) error {
+	err := h.roleService.DeleteRole(actorFromContext(c), c.Param("id"))
+	if errors.Is(err, service.ErrRoleNotFound) {
+		return c.JSON(http.StatusNotFound, jsonres.Error(
+			"NOT_FOUND", "Role not found", nil,
+		))
+	}
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"DELETE_FAILED", err.Error(), nil,
+		))
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success(
+		"Role deleted successfully", nil,
+	))
+}
+
+// GetUserAccess godoc
+// @Summary Get a user's roles
+// @Description Get a user's custom roles and effective permissions
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} jsonres.SuccessResponse{data=response.UserAccessResponse}
+// @Failure 404 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/users/{id}/roles [get]
+func (h *RoleHandler) GetUserAccess(c echo.Context) error {
+	access, err := h.roleService.GetUserAccess(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, jsonres.Error(
+			"NOT_FOUND", err.Error(), nil,
+		))
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success(
+		"User roles retrieved successfully",
+		dto.ToUserAccessResponse(access.User, access.Roles, access.Permissions),
+	))
+}
+
+// SetUserRoles godoc
+// @Summary Assign roles to a user
+// @Description Replace a user's custom roles; their system role is unaffected and the caller must hold every permission of the roles assigned
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param request body request.SetUserRolesRequest true "Custom role IDs"
+// @Success 200 {object} jsonres.SuccessResponse{data=response.UserAccessResponse}
+// @Failure 400 {object} jsonres.ErrorResponse
+// @Failure 403 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/users/{id}/roles [put]
+func (h *RoleHandler) SetUserRoles(c echo.Context) error {
+	var req request.SetUserRolesRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"BAD_REQUEST", "Invalid request body", err.Error(),
+		))
+	}
+
+	if errs := validator.Validate(&req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"VALIDATION_ERROR", "Validation failed", errs,
+		))
+	}
+
+	access, err := h.roleService.SetUserRoles(actorFromContext(c), c.Param("id"), req.RoleIDs)
+	if errors.Is(err, service.ErrPermissionDenied) {
+		return c.JSON(http.StatusForbidden, jsonres.Error(
+			"FORBIDDEN", err.Error(), nil,
+		))
+	}
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"UPDATE_FAILED", err.Error(), nil,
+		))
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success(
+		"User roles updated successfully",
+		dto.ToUserAccessResponse(access.User, access.Roles, access.Permissions),
+	))
+}
@@ -1,14 +1,17 @@
 package handler
 
 import (
+	"errors"
 	"hotel-booking-api/internal/domain"
 	"hotel-booking-api/internal/dto/request"
 	dto "hotel-booking-api/internal/dto/response"
+	"hotel-booking-api/internal/repository"
 	"hotel-booking-api/internal/service"
 	"hotel-booking-api/pkg/jsonres"
 	"hotel-booking-api/pkg/util"
 	"hotel-booking-api/pkg/validator"
 	"net/http"
+	"slices"
 
 	"github.com/labstack/echo/v4"
 )
@@ -25,7 +28,7 @@ func NewRoomHandler(roomService service.RoomService) *RoomHandler {
 
 // CreateRoom godoc
 // @Summary Create a room
-// @Description Create a new room hotel
+// @Description Create a new room hotel (admins and the hotel's managers)
 // @Tags rooms
 // @Accept json
 // @Produce json
@@ -53,14 +56,20 @@ func (h *RoomHandler) CreateRoom(c echo.Context) error {
 		RoomType:      req.RoomType,
 		PricePerNight: req.PricePerNight,
 		Availability:  req.Availability,
+		Capacity:      req.Capacity,
 	}
 
-	if err := h.roomService.CreateRoom(room); err != nil {
+	if err := h.roomService.CreateRoom(actorFromContext(c), room); errors.Is(err, service.ErrHotelForbidden) || errors.Is(err, service.ErrPermissionDenied) {
+		return c.JSON(http.StatusForbidden, jsonres.Error(
+			"FORBIDDEN", err.Error(), nil,
+		))
+	} else if err != nil {
 		return c.JSON(http.StatusInternalServerError, jsonres.Error(
 			"CREATE_FAILED", err.Error(), nil,
 		))
 	}
 
+	setETag(c, room.Version)
 	return c.JSON(http.StatusCreated, jsonres.Success(
 		"Room created successfully", dto.ToRoomResponse(room),
 	))
@@ -68,14 +77,17 @@ func (h *RoomHandler) CreateRoom(c echo.Context) error {
 
 // UpdateRoom godoc
 // @Summary Update a room
-// @Description Update rooms details (Admin only)
+// @Description Update rooms details (admins and the hotel's managers)
 // @Tags rooms
 // @Accept json
 // @Produce json
 // @Param id path string true "Room ID"
+// @Param If-Match header string true "ETag of the room as last read"
 // @Param request body request.UpdateRoomRequest true "Room details"
 // @Success 200 {object} jsonres.SuccessResponse{data=response.RoomResponse}
 // @Failure 400 {object} jsonres.ErrorResponse
+// @Failure 412 {object} jsonres.ErrorResponse
+// @Failure 428 {object} jsonres.ErrorResponse
 // @Security BearerAuth
 // @Router /rooms/{id} [put]
 func (h *RoomHandler) UpdateRoom(c echo.Context) error {
@@ -101,35 +113,129 @@ func (h *RoomHandler) UpdateRoom(c echo.Context) error {
 		))
 	}
 
-	room.RoomType = req.RoomType
-	room.PricePerNight = req.PricePerNight
-	room.Availability = req.Availability
+	if err := checkIfMatch(c, room.Version); err != nil {
+		return preconditionError(c, err)
+	}
+
+	setRoomFields(room, req)
 
-	if err := h.roomService.UpdateRoom(room); err != nil {
+	if err := h.roomService.UpdateRoom(actorFromContext(c), room); errors.Is(err, service.ErrHotelForbidden) || errors.Is(err, service.ErrPermissionDenied) {
+		return c.JSON(http.StatusForbidden, jsonres.Error(
+			"FORBIDDEN", err.Error(), nil,
+		))
+	} else if errors.Is(err, repository.ErrVersionConflict) {
+		return preconditionError(c, err)
+	} else if err != nil {
 		return c.JSON(http.StatusInternalServerError, jsonres.Error(
 			"UPDATE_FAILED", "Failed to update room", err.Error(),
 		))
 	}
 
+	setETag(c, room.Version)
 	return c.JSON(http.StatusOK, jsonres.Success(
 		"Room updated successfully", dto.ToRoomResponse(room),
 	))
 }
 
+// PatchRoom godoc
+// @Summary Partially update a room
+// @Description Apply a JSON Merge Patch (RFC 7396) to a room (admins and the hotel's managers). Only the fields in the patch change. meta.changed lists the fields whose value changed
+// @Tags rooms
+// @Accept application/merge-patch+json
+// @Produce json
+// @Param id path string true "Room ID"
+// @Param If-Match header string true "ETag of the room as last read"
+// @Param request body request.UpdateRoomRequest true "Fields to change"
+// @Success 200 {object} jsonres.SuccessResponse{data=response.RoomResponse,meta=response.PatchMeta}
+// @Failure 400 {object} jsonres.ErrorResponse
+// @Failure 403 {object} jsonres.ErrorResponse
+// @Failure 404 {object} jsonres.ErrorResponse
+// @Failure 412 {object} jsonres.ErrorResponse
+// @Failure 415 {object} jsonres.ErrorResponse
+// @Failure 428 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /rooms/{id} [patch]
+func (h *RoomHandler) PatchRoom(c echo.Context) error {
+	room, err := h.roomService.GetRoomByID(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, jsonres.Error(
+			"NOT_FOUND", "Room not found", nil,
+		))
+	}
+
+	if err := checkIfMatch(c, room.Version); err != nil {
+		return preconditionError(c, err)
+	}
+
+	req := roomFields(room)
+	changed, err := mergePatch(c, &req)
+	if err != nil {
+		return patchError(c, err)
+	}
+	if req.Capacity == 0 {
+		// As in a full update, no capacity keeps the current one.
+		req.Capacity = room.Capacity
+		changed = slices.DeleteFunc(changed, func(name string) bool { return name == "capacity" })
+	}
+
+	if errs := validator.Validate(&req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"VALIDATION_ERROR", "Validation failed", errs,
+		))
+	}
+
+	if len(changed) > 0 {
+		setRoomFields(room, req)
+
+		if err := h.roomService.UpdateRoom(actorFromContext(c), room); errors.Is(err, service.ErrHotelForbidden) || errors.Is(err, service.ErrPermissionDenied) {
+			return c.JSON(http.StatusForbidden, jsonres.Error(
+				"FORBIDDEN", err.Error(), nil,
+			))
+		} else if errors.Is(err, repository.ErrVersionConflict) {
+			return preconditionError(c, err)
+		} else if err != nil {
+			return c.JSON(http.StatusInternalServerError, jsonres.Error(
+				"UPDATE_FAILED", "Failed to update room", err.Error(),
+			))
+		}
+	}
+
+	setETag(c, room.Version)
+	return c.JSON(http.StatusOK, jsonres.SuccessWithMeta(
+		"Room updated successfully", dto.ToRoomResponse(room), dto.PatchMeta{Changed: changed},
+	))
+}
+
 // ListRoomsByHotel godoc
 // @Summary Get rooms by HotelId
 // @Description Get detailed information about a specific rooms by hotelId
 // @Tags rooms
 // @Accept json
 // @Produce json
-// @Param id path string true "hotelId"
-// @Success 200 {object} jsonres.SuccessResponse{data=response.RoomResponse}
-// @Failure 404 {object} jsonres.ErrorResponse
-// @Router /rooms/{id} [get]
+// @Param hotelId path string true "hotelId"
+// @Param room_type query string false "Filter by room type"
+// @Param min_price query number false "Minimum price per night"
+// @Param max_price query number false "Maximum price per night"
+// @Param amenities query string false "Comma-separated amenity codes the room must all offer"
+// @Param page query int false "Page number" default(1)
+// @Param cursor query string false "Cursor from a previous page's meta.next_cursor"
+// @Param limit query int false "Page size (max 100)" default(20)
+// @Param sort query string false "Sort field" Enums(price_per_night, room_type, availability, created_at)
+// @Param order query string false "Sort order" Enums(asc, desc)
+// @Success 200 {object} jsonres.SuccessResponse{data=[]response.RoomResponse,meta=pagination.Meta}
+// @Failure 400 {object} jsonres.ErrorResponse
+// @Router /rooms/hotel/{hotelId} [get]
 func (h *RoomHandler) ListRoomsByHotel(c echo.Context) error {
 	hotelId := c.Param("hotelId")
 
-	rooms, err := h.roomService.GetRoomsByHotel(hotelId)
+	query, err := listQuery(c, repository.RoomListOptions)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"VALIDATION_ERROR", "Validation failed", []string{err.Error()},
+		))
+	}
+
+	rooms, total, err := h.roomService.GetRoomsByHotel(hotelId, queryList(c, "amenities"), query)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, jsonres.Error(
 			"FETCH_FAILED", "Failed to fetch room", err.Error(),
@@ -141,8 +247,8 @@ func (h *RoomHandler) ListRoomsByHotel(c echo.Context) error {
 		roomResponses[i] = dto.ToRoomResponse(&room)
 	}
 
-	return c.JSON(http.StatusOK, jsonres.Success(
-		"Room retrieved successfully", roomResponses,
+	return c.JSON(http.StatusOK, jsonres.SuccessWithMeta(
+		"Room retrieved successfully", roomResponses, query.Meta(total, len(rooms)),
 	))
 }
 
@@ -153,7 +259,9 @@ func (h *RoomHandler) ListRoomsByHotel(c echo.Context) error {
 // @Accept json
 // @Produce json
 // @Param id path string true "Room ID"
+// @Param If-None-Match header string false "ETag of a cached copy"
 // @Success 200 {object} jsonres.SuccessResponse{data=response.RoomResponse}
+// @Success 304 "Not modified"
 // @Failure 404 {object} jsonres.ErrorResponse
 // @Router /rooms/{id} [get]
 func (h *RoomHandler) GetRoom(c echo.Context) error {
@@ -166,7 +274,96 @@ func (h *RoomHandler) GetRoom(c echo.Context) error {
 		))
 	}
 
+	if notModified(c, room.Version) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
 	return c.JSON(http.StatusOK, jsonres.Success(
 		"Room retrieved successfully", dto.ToRoomResponse(room),
 	))
 }
+
+// DeleteRoom godoc
+// @Summary Delete a room
+// @Description Soft-delete a room (admins and the hotel's managers). It disappears from listings, search and booking, while existing bookings keep it. Refused while upcoming bookings exist
+// @Tags rooms
+// @Produce json
+// @Param id path string true "Room ID"
+// @Success 200 {object} jsonres.SuccessResponse
+// @Failure 403 {object} jsonres.ErrorResponse
+// @Failure 404 {object} jsonres.ErrorResponse
+// @Failure 409 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /rooms/{id} [delete]
+func (h *RoomHandler) DeleteRoom(c echo.Context) error {
+	if err := h.roomService.DeleteRoom(actorFromContext(c), c.Param("id")); err != nil {
+		return deletionError(c, "DELETE_FAILED", "Failed to delete room", err)
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success(
+		"Room deleted successfully", nil,
+	))
+}
+
+// RestoreRoom godoc
+// @Summary Restore a deleted room
+// @Description Undelete a room. Rooms deleted with their hotel are restored by restoring the hotel
+// @Tags rooms
+// @Produce json
+// @Param id path string true "Room ID"
+// @Success 200 {object} jsonres.SuccessResponse{data=response.RoomResponse}
+// @Failure 404 {object} jsonres.ErrorResponse
+// @Failure 409 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/rooms/{id}/restore [post]
+func (h *RoomHandler) RestoreRoom(c echo.Context) error {
+	room, err := h.roomService.RestoreRoom(actorFromContext(c), c.Param("id"))
+	if err != nil {
+		return deletionError(c, "RESTORE_FAILED", "Failed to restore room", err)
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success(
+		"Room restored successfully", dto.ToRoomResponse(room),
+	))
+}
+
+// PurgeRoom godoc
+// @Summary Permanently delete a room
+// @Description Permanently delete a soft-deleted room. Rooms with any booking history cannot be purged
+// @Tags rooms
+// @Produce json
+// @Param id path string true "Room ID"
+// @Success 200 {object} jsonres.SuccessResponse
+// @Failure 404 {object} jsonres.ErrorResponse
+// @Failure 409 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/rooms/{id} [delete]
+func (h *RoomHandler) PurgeRoom(c echo.Context) error {
+	if err := h.roomService.PurgeRoom(actorFromContext(c), c.Param("id")); err != nil {
+		return deletionError(c, "PURGE_FAILED", "Failed to purge room", err)
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success(
+		"Room purged successfully", nil,
+	))
+}
+
+// roomFields and setRoomFields convert between a room and the fields a client
+// may update. A zero capacity keeps the room's current one.
+func roomFields(room *domain.Room) request.UpdateRoomRequest {
+	return request.UpdateRoomRequest{
+		RoomType:      room.RoomType,
+		PricePerNight: room.PricePerNight,
+		Availability:  &room.Availability,
+		Capacity:      room.Capacity,
+	}
+}
+
+func setRoomFields(room *domain.Room, req request.UpdateRoomRequest) {
+	room.RoomType = req.RoomType
+	room.PricePerNight = req.PricePerNight
+	room.Availability = *req.Availability
+	if req.Capacity > 0 {
+		room.Capacity = req.Capacity
+	}
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"hotel-booking-api/internal/dto/request"
+	dto "hotel-booking-api/internal/dto/response"
+	"hotel-booking-api/internal/service"
+	"hotel-booking-api/pkg/jsonres"
+	"hotel-booking-api/pkg/validator"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type StaffHandler struct {
+	staffService service.StaffService
+}
+
+func NewStaffHandler(staffService service.StaffService) *StaffHandler {
+	return &StaffHandler{
+		staffService: staffService,
+	}
+}
+
+// ListHotelStaff godoc
+// @Summary List hotel staff
+// @Description Get the managers and staff assigned to a hotel (Admin only)
+// @Tags staff
+// @Accept json
+// @Produce json
+// @Param id path string true "Hotel ID"
+// @Success 200 {object} jsonres.SuccessResponse{data=[]response.StaffResponse}
+// @Failure 404 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/hotels/{id}/staff [get]
+func (h *StaffHandler) ListHotelStaff(c echo.Context) error {
+	staff, err := h.staffService.ListHotelStaff(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, jsonres.Error(
+			"NOT_FOUND", err.Error(), nil,
+		))
+	}
+
+	staffResponses := make([]dto.StaffResponse, len(staff))
+	for i := range staff {
+		staffResponses[i] = dto.ToStaffResponse(&staff[i])
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success(
+		"Hotel staff retrieved successfully", staffResponses,
+	))
+}
+
+// AssignStaff godoc
+// @Summary Assign hotel staff
+// @Description Make a user a manager or staff member of a hotel, or change their role there (Admin only)
+// @Tags staff
+// @Accept json
+// @Produce json
+// @Param id path string true "Hotel ID"
+// @Param userId path string true "User ID"
+// @Param request body request.AssignStaffRequest true "Role at the hotel"
+// @Success 200 {object} jsonres.SuccessResponse{data=response.StaffResponse}
+// @Failure 400 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/hotels/{id}/staff/{userId} [put]
+func (h *StaffHandler) AssignStaff(c echo.Context) error {
+	var req request.AssignStaffRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"BAD_REQUEST", "Invalid request body", err.Error(),
+		))
+	}
+
+	if errs := validator.Validate(&req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"VALIDATION_ERROR", "Validation failed", errs,
+		))
+	}
+
+	staff, err := h.staffService.AssignStaff(actorFromContext(c), c.Param("id"), c.Param("userId"), req.Role)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"ASSIGN_FAILED", err.Error(), nil,
+		))
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success(
+		"Staff assigned successfully", dto.ToStaffResponse(staff),
+	))
+}
+
+// RemoveStaff godoc
+// @Summary Remove hotel staff
+// @Description Remove a user's assignment to a hotel (Admin only)
+// @Tags staff
+// @Accept json
+// @Produce json
+// @Param id path string true "Hotel ID"
+// @Param userId path string true "User ID"
+// @Success 200 {object} jsonres.SuccessResponse
+// @Failure 404 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/hotels/{id}/staff/{userId} [delete]
+func (h *StaffHandler) RemoveStaff(c echo.Context) error {
+	if err := h.staffService.RemoveStaff(actorFromContext(c), c.Param("id"), c.Param("userId")); err != nil {
+		return c.JSON(http.StatusNotFound, jsonres.Error(
+			"DELETE_FAILED", err.Error(), nil,
+		))
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success(
+		"Staff removed successfully", nil,
+	))
+}
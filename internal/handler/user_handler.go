@@ -0,0 +1,250 @@
+package handler
+
+import (
+	"errors"
+	"hotel-booking-api/internal/dto/request"
+	dto "hotel-booking-api/internal/dto/response"
+	"hotel-booking-api/internal/repository"
+	"hotel-booking-api/internal/service"
+	"hotel-booking-api/pkg/jsonres"
+	"hotel-booking-api/pkg/validator"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type UserHandler struct {
+	userService service.UserService
+}
+
+func NewUserHandler(userService service.UserService) *UserHandler {
+	return &UserHandler{
+		userService: userService,
+	}
+}
+
+// ListUsers godoc
+// @Summary List users
+// @Description Search accounts by name or email, role and status
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param q query string false "Name or email contains"
+// @Param role query string false "Account role" Enums(CUSTOMER, STAFF, HOTEL_MANAGER, ADMIN)
+// @Param status query string false "Account status" Enums(active, deactivated)
+// @Param page query int false "Page number" default(1)
+// @Param cursor query string false "Cursor from a previous page's meta.next_cursor"
+// @Param limit query int false "Page size (max 100)" default(20)
+// @Param sort query string false "Sort field" Enums(created_at, name, email, role)
+// @Param order query string false "Sort order" Enums(asc, desc)
+// @Success 200 {object} jsonres.SuccessResponse{data=[]response.AdminUserResponse,meta=pagination.Meta}
+// @Failure 400 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/users [get]
+func (h *UserHandler) ListUsers(c echo.Context) error {
+	query, err := listQuery(c, repository.UserListOptions)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"VALIDATION_ERROR", "Validation failed", []string{err.Error()},
+		))
+	}
+
+	filter := repository.UserFilter{
+		Search: c.QueryParam("q"),
+		Status: c.QueryParam("status"),
+		Query:  query,
+	}
+	if filter.Status != "" && filter.Status != repository.UserStatusActive && filter.Status != repository.UserStatusDeactivated {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"VALIDATION_ERROR", "Validation failed", []string{"status must be active or deactivated"},
+		))
+	}
+
+	users, total, err := h.userService.ListUsers(filter)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, jsonres.Error(
+			"FETCH_FAILED", "Failed to fetch users", err.Error(),
+		))
+	}
+
+	userResponses := make([]dto.AdminUserResponse, len(users))
+	for i := range users {
+		userResponses[i] = dto.ToAdminUserResponse(&users[i])
+	}
+
+	return c.JSON(http.StatusOK, jsonres.SuccessWithMeta(
+		"Users retrieved successfully", userResponses, filter.Query.Meta(total, len(users)),
+	))
+}
+
+// GetUser godoc
+// @Summary Get a user
+// @Description Get an account by ID
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} jsonres.SuccessResponse{data=response.AdminUserResponse}
+// @Failure 404 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/users/{id} [get]
+func (h *UserHandler) GetUser(c echo.Context) error {
+	user, err := h.userService.GetUser(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, jsonres.Error(
+			"NOT_FOUND", err.Error(), nil,
+		))
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success(
+		"User retrieved successfully", dto.ToAdminUserResponse(user),
+	))
+}
+
+// CreateUser godoc
+// @Summary Create a user
+// @Description Provision a customer, staff, hotel manager or admin account. Staff and managers are assigned to hotel_id; only admins may create admins.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body request.CreateUserRequest true "Account details"
+// @Success 201 {object} jsonres.SuccessResponse{data=response.AdminUserResponse}
+// @Failure 400 {object} jsonres.ErrorResponse
+// @Failure 403 {object} jsonres.ErrorResponse
+// @Failure 409 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/users [post]
+func (h *UserHandler) CreateUser(c echo.Context) error {
+	var req request.CreateUserRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"BAD_REQUEST", "Invalid request body", err.Error(),
+		))
+	}
+
+	if errs := validator.Validate(&req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"VALIDATION_ERROR", "Validation failed", errs,
+		))
+	}
+
+	user, err := h.userService.CreateUser(actorFromContext(c), service.NewUserInput{
+		Name:     req.Name,
+		Email:    req.Email,
+		Password: req.Password,
+		Role:     req.Role,
+		HotelID:  req.HotelID,
+	})
+	if err != nil {
+		return userError(c, "CREATE_FAILED", err)
+	}
+
+	return c.JSON(http.StatusCreated, jsonres.Success(
+		"User created successfully", dto.ToAdminUserResponse(user),
+	))
+}
+
+// ChangeUserRole godoc
+// @Summary Change a user's role
+// @Description Grant or remove admin rights. A demoted user keeps any hotel role from their staff assignments. The user's sessions are revoked.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param request body request.ChangeUserRoleRequest true "New role"
+// @Success 200 {object} jsonres.SuccessResponse{data=response.AdminUserResponse}
+// @Failure 400 {object} jsonres.ErrorResponse
+// @Failure 403 {object} jsonres.ErrorResponse
+// @Failure 404 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/users/{id}/role [patch]
+func (h *UserHandler) ChangeUserRole(c echo.Context) error {
+	var req request.ChangeUserRoleRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"BAD_REQUEST", "Invalid request body", err.Error(),
+		))
+	}
+
+	if errs := validator.Validate(&req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			"VALIDATION_ERROR", "Validation failed", errs,
+		))
+	}
+
+	user, err := h.userService.ChangeRole(actorFromContext(c), c.Param("id"), req.Role)
+	if err != nil {
+		return userError(c, "UPDATE_FAILED", err)
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success(
+		"User role updated successfully", dto.ToAdminUserResponse(user),
+	))
+}
+
+// DeactivateUser godoc
+// @Summary Deactivate a user
+// @Description Block an account from signing in and revoke its sessions
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} jsonres.SuccessResponse{data=response.AdminUserResponse}
+// @Failure 400 {object} jsonres.ErrorResponse
+// @Failure 403 {object} jsonres.ErrorResponse
+// @Failure 404 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/users/{id}/deactivate [post]
+func (h *UserHandler) DeactivateUser(c echo.Context) error {
+	user, err := h.userService.DeactivateUser(actorFromContext(c), c.Param("id"))
+	if err != nil {
+		return userError(c, "UPDATE_FAILED", err)
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success(
+		"User deactivated successfully", dto.ToAdminUserResponse(user),
+	))
+}
+
+// ReactivateUser godoc
+// @Summary Reactivate a user
+// @Description Allow a deactivated account to sign in again
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} jsonres.SuccessResponse{data=response.AdminUserResponse}
+// @Failure 404 {object} jsonres.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/users/{id}/reactivate [post]
+func (h *UserHandler) ReactivateUser(c echo.Context) error {
+	user, err := h.userService.ReactivateUser(actorFromContext(c), c.Param("id"))
+	if err != nil {
+		return userError(c, "UPDATE_FAILED", err)
+	}
+
+	return c.JSON(http.StatusOK, jsonres.Success(
+		"User reactivated successfully", dto.ToAdminUserResponse(user),
+	))
+}
+
+func userError(c echo.Context, code string, err error) error {
+	switch {
+	case errors.Is(err, service.ErrUserNotFound):
+		return c.JSON(http.StatusNotFound, jsonres.Error(
+			"NOT_FOUND", err.Error(), nil,
+		))
+	case errors.Is(err, service.ErrPermissionDenied):
+		return c.JSON(http.StatusForbidden, jsonres.Error(
+			"FORBIDDEN", err.Error(), nil,
+		))
+	case errors.Is(err, service.ErrEmailTaken):
+		return c.JSON(http.StatusConflict, jsonres.Error(
+			"CONFLICT", err.Error(), nil,
+		))
+	default:
+		return c.JSON(http.StatusBadRequest, jsonres.Error(
+			code, err.Error(), nil,
+		))
+	}
+}
@@ -1,17 +1,51 @@
 package middleware
 
 import (
+	"hotel-booking-api/internal/domain"
 	"hotel-booking-api/pkg/jsonres"
 	"hotel-booking-api/pkg/util"
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/labstack/echo/v4"
 )
 
-func AuthMiddleware() echo.MiddlewareFunc {
+// TokenRevocationChecker reports whether an access token was revoked before
+// it expired.
+type TokenRevocationChecker interface {
+	IsTokenRevoked(jti string) (bool, error)
+}
+
+// APIKeyAuthenticator looks up the active API key matching a raw key.
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(key string) (*domain.APIKey, error)
+}
+
+// AuthMiddleware authenticates a Bearer access token or, when apiKeys is not
+// nil, an API key sent in the X-API-Key header. An API key sets "apiKeyID",
+// its "permissions" and the hotels it is limited to as "apiKeyHotels"; it
+// has no user, so "userID" and "role" are empty.
+func AuthMiddleware(revocations TokenRevocationChecker, apiKeys APIKeyAuthenticator) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(echo echo.Context) error {
+			if key := echo.Request().Header.Get("X-API-Key"); key != "" && apiKeys != nil {
+				apiKey, err := apiKeys.AuthenticateAPIKey(key)
+				if err != nil {
+					return echo.JSON(http.StatusUnauthorized, jsonres.Error(
+						"UNAUTHORIZED", "Invalid API key", nil,
+					))
+				}
+
+				echo.Set("userID", "")
+				echo.Set("role", "")
+				echo.Set("apiKeyID", apiKey.ID.String())
+				echo.Set("permissions", apiKey.PermissionCodes())
+				echo.Set("apiKeyHotels", apiKey.HotelIDs())
+
+				return next(echo)
+			}
+
 			authHeader := echo.Request().Header.Get("Authorization")
 			if authHeader == "" {
 				return echo.JSON(http.StatusUnauthorized, jsonres.Error(
@@ -33,8 +67,21 @@ func AuthMiddleware() echo.MiddlewareFunc {
 				))
 			}
 
+			revoked, err := revocations.IsTokenRevoked(claims.ID)
+			if err != nil {
+				return echo.JSON(http.StatusInternalServerError, jsonres.Error(
+					"INTERNAL_ERROR", "Failed to verify token", nil,
+				))
+			}
+			if revoked {
+				return echo.JSON(http.StatusUnauthorized, jsonres.Error(
+					"UNAUTHORIZED", "Token has been revoked", nil,
+				))
+			}
+
 			echo.Set("userID", claims.UserID)
 			echo.Set("role", claims.Role)
+			echo.Set("claims", claims)
 
 			return next(echo)
 		}
@@ -45,7 +92,7 @@ func AdminOnly() echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(echo echo.Context) error {
 			role := echo.Get("role")
-			if role != "ADMIN" {
+			if role != domain.RoleAdmin {
 				return echo.JSON(http.StatusForbidden, jsonres.Error(
 					"FORBIDDEN", "Admin access required", nil,
 				))
@@ -54,3 +101,131 @@ func AdminOnly() echo.MiddlewareFunc {
 		}
 	}
 }
+
+// RequireRoles lets the request through only when the authenticated user has
+// one of roles. Hotel-scoped checks still happen in the services.
+func RequireRoles(roles ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(echo echo.Context) error {
+			role, _ := echo.Get("role").(string)
+			if !slices.Contains(roles, role) {
+				return echo.JSON(http.StatusForbidden, jsonres.Error(
+					"FORBIDDEN", "Insufficient role", nil,
+				))
+			}
+			return next(echo)
+		}
+	}
+}
+
+// RequireUser refuses API keys on routes that act for the signed-in user,
+// such as booking a room or listing one's own bookings: a key has no user to
+// act for.
+func RequireUser() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(echo echo.Context) error {
+			if userID, _ := echo.Get("userID").(string); userID == "" {
+				return echo.JSON(http.StatusForbidden, jsonres.Error(
+					"FORBIDDEN", "This action requires a user account", nil,
+				))
+			}
+			return next(echo)
+		}
+	}
+}
+
+// EmailVerificationChecker reports whether a user has confirmed their email
+// address.
+type EmailVerificationChecker interface {
+	IsEmailVerified(userID string) (bool, error)
+}
+
+// RequireVerifiedEmail rejects users who have not verified their email
+// address. When required is false it lets every request through, so routes
+// can be wired the same way whichever the deployment chooses.
+func RequireVerifiedEmail(checker EmailVerificationChecker, required bool) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		if !required {
+			return next
+		}
+
+		return func(echo echo.Context) error {
+			userID, _ := echo.Get("userID").(string)
+
+			verified, err := checker.IsEmailVerified(userID)
+			if err != nil {
+				return echo.JSON(http.StatusInternalServerError, jsonres.Error(
+					"INTERNAL_ERROR", "Failed to check email verification", nil,
+				))
+			}
+			if !verified {
+				return echo.JSON(http.StatusForbidden, jsonres.Error(
+					"EMAIL_NOT_VERIFIED", "Verify your email address to continue", nil,
+				))
+			}
+
+			return next(echo)
+		}
+	}
+}
+
+// PermissionResolver looks up the permission codes granted to a user with the
+// given account role.
+type PermissionResolver interface {
+	ResolvePermissions(userID, role string) ([]string, error)
+}
+
+// RequirePermissionFunc builds middleware that lets the request through when
+// the authenticated user holds at least one of permissions.
+type RequirePermissionFunc func(permissions ...string) echo.MiddlewareFunc
+
+// NewRequirePermission returns a RequirePermissionFunc backed by resolver.
+// Permissions are resolved once per request, after AuthMiddleware, so changes
+// to custom roles take effect without reissuing tokens; they are stored in the
+// context under "permissions" for service-level checks.
+//
+// With requireAdminTwoFactor, admins whose session was not established with a
+// second factor are refused until they enrol and sign in again.
+func NewRequirePermission(resolver PermissionResolver, requireAdminTwoFactor bool) RequirePermissionFunc {
+	return func(permissions ...string) echo.MiddlewareFunc {
+		return func(next echo.HandlerFunc) echo.HandlerFunc {
+			return func(echo echo.Context) error {
+				if requireAdminTwoFactor && echo.Get("role") == domain.RoleAdmin {
+					if claims, _ := echo.Get("claims").(*util.JWTClaims); claims == nil || !claims.MFA {
+						return echo.JSON(http.StatusForbidden, jsonres.Error(
+							"TWO_FACTOR_REQUIRED", "Enable two-factor authentication and sign in again", nil,
+						))
+					}
+				}
+
+				granted, ok := echo.Get("permissions").([]string)
+				if !ok {
+					userID, _ := echo.Get("userID").(string)
+					role, _ := echo.Get("role").(string)
+
+					var err error
+					granted, err = resolver.ResolvePermissions(userID, role)
+					if err != nil {
+						return echo.JSON(http.StatusInternalServerError, jsonres.Error(
+							"INTERNAL_ERROR", "Failed to resolve permissions", nil,
+						))
+					}
+					echo.Set("permissions", granted)
+				}
+
+				if echo.Get("role") == domain.RoleAdmin {
+					return next(echo)
+				}
+				for _, permission := range permissions {
+					if slices.Contains(granted, permission) {
+						return next(echo)
+					}
+				}
+
+				return echo.JSON(http.StatusForbidden, jsonres.Error(
+					"FORBIDDEN", "Missing permission", permissions,
+				))
+			}
+		}
+	}
+}
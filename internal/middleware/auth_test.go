@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"errors"
+	"hotel-booking-api/internal/domain"
+	"hotel-booking-api/pkg/util"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// serve runs mw in front of a handler that answers 200, with values set on
+// the context first as AuthMiddleware would.
+func serve(mw echo.MiddlewareFunc, values map[string]any) *httptest.ResponseRecorder {
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+	for key, value := range values {
+		c.Set(key, value)
+	}
+
+	mw(func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})(c)
+
+	return rec
+}
+
+func TestRequireUser(t *testing.T) {
+	rec := serve(RequireUser(), map[string]any{"userID": "", "apiKeyID": "key-1"})
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = serve(RequireUser(), map[string]any{"userID": "user-1"})
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+type stubResolver struct {
+	permissions []string
+	err         error
+	calls       int
+}
+
+func (r *stubResolver) ResolvePermissions(userID, role string) ([]string, error) {
+	r.calls++
+	return r.permissions, r.err
+}
+
+func TestRequirePermission(t *testing.T) {
+	customer := map[string]any{"userID": "user-1", "role": domain.RoleCustomer}
+
+	t.Run("missing permission is forbidden", func(t *testing.T) {
+		resolver := &stubResolver{permissions: []string{domain.PermBookingCreate}}
+		rec := serve(NewRequirePermission(resolver, false)(domain.PermAuditRead), customer)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("any one of the permissions is enough", func(t *testing.T) {
+		resolver := &stubResolver{permissions: []string{domain.PermAuditRead}}
+		rec := serve(NewRequirePermission(resolver, false)(domain.PermUserManage, domain.PermAuditRead), customer)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("admins bypass the check", func(t *testing.T) {
+		resolver := &stubResolver{}
+		rec := serve(NewRequirePermission(resolver, false)(domain.PermAuditRead),
+			map[string]any{"userID": "admin-1", "role": domain.RoleAdmin})
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("permissions already on the context are reused", func(t *testing.T) {
+		resolver := &stubResolver{}
+		rec := serve(NewRequirePermission(resolver, false)(domain.PermAuditRead),
+			map[string]any{"userID": "user-1", "role": domain.RoleCustomer, "permissions": []string{domain.PermAuditRead}})
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Zero(t, resolver.calls)
+	})
+
+	t.Run("resolver failure", func(t *testing.T) {
+		resolver := &stubResolver{err: errors.New("connection refused")}
+		rec := serve(NewRequirePermission(resolver, false)(domain.PermAuditRead), customer)
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+
+	t.Run("admins need a second factor when required", func(t *testing.T) {
+		admin := map[string]any{"userID": "admin-1", "role": domain.RoleAdmin, "claims": &util.JWTClaims{}}
+		rec := serve(NewRequirePermission(&stubResolver{}, true)(domain.PermAuditRead), admin)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+
+		admin["claims"] = &util.JWTClaims{MFA: true}
+		rec = serve(NewRequirePermission(&stubResolver{}, true)(domain.PermAuditRead), admin)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+}
@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"fmt"
+	"hotel-booking-api/pkg/jsonres"
+	"hotel-booking-api/pkg/logger"
+	"hotel-booking-api/pkg/ratelimit"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RateLimitKeyFunc names the client a request is counted against.
+type RateLimitKeyFunc func(c echo.Context) string
+
+// KeyByIP counts requests per client address.
+func KeyByIP(c echo.Context) string {
+	return "ip:" + c.RealIP()
+}
+
+// KeyByClient counts requests per API key or signed-in user, falling back to
+// the client address. It must run after AuthMiddleware to see either.
+func KeyByClient(c echo.Context) string {
+	if id, _ := c.Get("apiKeyID").(string); id != "" {
+		return "apikey:" + id
+	}
+	if id, _ := c.Get("userID").(string); id != "" {
+		return "user:" + id
+	}
+
+	return KeyByIP(c)
+}
+
+// RateLimit allows each client limit's rate of requests, counted in buckets
+// named after name so every limited route group has its own budget. Every
+// response carries RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset
+// headers; refused requests get 429 with Retry-After.
+//
+// If the store fails the request is let through: an unavailable store should
+// not take the API down with it.
+func RateLimit(store ratelimit.Store, name string, limit ratelimit.Limit, key RateLimitKeyFunc) echo.MiddlewareFunc {
+	if !limit.Enabled() {
+		return func(next echo.HandlerFunc) echo.HandlerFunc {
+			return next
+		}
+	}
+
+	policy := fmt.Sprintf("%d;w=%d;burst=%d", limit.Requests, seconds(limit.Period), limit.Capacity())
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			result, err := store.Take(c.Request().Context(), name+":"+key(c), limit)
+			if err != nil {
+				logger.Error("Rate limit store failed", "limit", name, "error", err)
+				return next(c)
+			}
+
+			header := c.Response().Header()
+			header.Set("RateLimit-Policy", policy)
+			header.Set("RateLimit-Limit", strconv.Itoa(limit.Capacity()))
+			header.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			header.Set("RateLimit-Reset", strconv.Itoa(seconds(result.ResetAfter)))
+
+			if !result.Allowed {
+				header.Set(echo.HeaderRetryAfter, strconv.Itoa(seconds(result.RetryAfter)))
+				return c.JSON(http.StatusTooManyRequests, jsonres.Error(
+					"RATE_LIMITED", "Too many requests, please retry later", nil,
+				))
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// seconds rounds d up to whole seconds, as the headers require.
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"hotel-booking-api/internal/domain"
+	"maps"
+	"slices"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type AmenityRepository interface {
+	Create(amenity *domain.Amenity) error
+	Update(amenity *domain.Amenity) error
+	Delete(id string) error
+	FindAll(category string) ([]domain.Amenity, error)
+	FindByID(id string) (*domain.Amenity, error)
+	FindByCodes(codes []string) ([]domain.Amenity, error)
+	ReplaceHotelAmenities(hotel *domain.Hotel, amenities []domain.Amenity) error
+	ReplaceRoomAmenities(room *domain.Room, amenities []domain.Amenity) error
+}
+
+type amenityRepository struct {
+	DB *gorm.DB
+}
+
+func NewAmenityRepository(db *gorm.DB) AmenityRepository {
+	return &amenityRepository{DB: db}
+}
+
+func (r *amenityRepository) Create(amenity *domain.Amenity) error {
+	return r.DB.Create(amenity).Error
+}
+
+// Update renames an amenity everywhere it is shown, so the hotels and rooms
+// that link to it get a new version and hotels a rebuilt search text.
+func (r *amenityRepository) Update(amenity *domain.Amenity) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(amenity).Error; err != nil {
+			return err
+		}
+
+		hotelIDs, err := touchAmenityOwners(tx, amenity.ID.String())
+		if err != nil {
+			return err
+		}
+
+		return rebuildAmenityText(tx, hotelIDs)
+	})
+}
+
+// Delete unlinks the amenity from its hotels and rooms through the join
+// tables' cascade, so the owners are found and touched beforehand.
+func (r *amenityRepository) Delete(id string) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		hotelIDs, err := touchAmenityOwners(tx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&domain.Amenity{}, "id = ?", id).Error; err != nil {
+			return err
+		}
+
+		return rebuildAmenityText(tx, hotelIDs)
+	})
+}
+
+func (r *amenityRepository) FindAll(category string) ([]domain.Amenity, error) {
+	var amenities []domain.Amenity
+
+	db := r.DB.Order("category, name")
+	if category != "" {
+		db = db.Where("category = ?", category)
+	}
+
+	err := db.Find(&amenities).Error
+	return amenities, err
+}
+
+func (r *amenityRepository) FindByID(id string) (*domain.Amenity, error) {
+	var amenity domain.Amenity
+	err := r.DB.First(&amenity, "id = ?", id).Error
+
+	return &amenity, err
+}
+
+func (r *amenityRepository) FindByCodes(codes []string) ([]domain.Amenity, error) {
+	var amenities []domain.Amenity
+	err := r.DB.Where("code IN ?", codes).Order("category, name").Find(&amenities).Error
+
+	return amenities, err
+}
+
+func (r *amenityRepository) ReplaceHotelAmenities(hotel *domain.Hotel, amenities []domain.Amenity) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(hotel).Association("Amenities").Replace(amenities); err != nil {
+			return err
+		}
+
+		// Keep the denormalised text in step so full-text search sees it.
+		return tx.Model(hotel).UpdateColumns(map[string]any{
+			"amenity_text": amenitySearchText(amenities),
+			"version":      bumpVersion,
+		}).Error
+	})
+}
+
+func (r *amenityRepository) ReplaceRoomAmenities(room *domain.Room, amenities []domain.Amenity) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(room).Association("Amenities").Replace(amenities); err != nil {
+			return err
+		}
+
+		return touchRoom(tx, room)
+	})
+}
+
+// touchAmenityOwners bumps the version of every room linked to the amenity
+// and of every hotel that shows it, directly or through one of its rooms. It
+// returns the hotels linked directly, whose search text embeds the amenity.
+func touchAmenityOwners(tx *gorm.DB, amenityID string) ([]string, error) {
+	var hotelIDs []string
+	err := tx.Table("hotel_amenities").Where("amenity_id = ?", amenityID).Pluck("hotel_id", &hotelIDs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	roomIDs := tx.Table("room_amenities").Select("room_id").Where("amenity_id = ?", amenityID)
+	err = tx.Unscoped().Model(&domain.Room{}).Where("id IN (?)", roomIDs).
+		UpdateColumn("version", bumpVersion).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Unscoped().Model(&domain.Hotel{}).
+		Where("id IN (?) OR id IN (?)",
+			tx.Table("hotel_amenities").Select("hotel_id").Where("amenity_id = ?", amenityID),
+			tx.Table("rooms").Select("hotel_id").Where("id IN (?)", roomIDs)).
+		UpdateColumn("version", bumpVersion).Error
+
+	return hotelIDs, err
+}
+
+// rebuildAmenityText recomputes the search text of the given hotels from
+// the amenities they link to now.
+func rebuildAmenityText(tx *gorm.DB, hotelIDs []string) error {
+	if len(hotelIDs) == 0 {
+		return nil
+	}
+
+	var hotels []domain.Hotel
+	if err := tx.Unscoped().Preload("Amenities").Where("id IN ?", hotelIDs).Find(&hotels).Error; err != nil {
+		return err
+	}
+
+	for i := range hotels {
+		err := tx.Unscoped().Model(&hotels[i]).UpdateColumn("amenity_text", amenitySearchText(hotels[i].Amenities)).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// amenitySearchText flattens amenity names and their translations into the
+// hotel's denormalised amenity_text column.
+func amenitySearchText(amenities []domain.Amenity) string {
+	var words []string
+	for _, amenity := range amenities {
+		words = append(words, amenity.Name)
+		for _, lang := range slices.Sorted(maps.Keys(amenity.Translations)) {
+			words = append(words, amenity.Translations[lang])
+		}
+	}
+
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"hotel-booking-api/internal/domain"
+	"hotel-booking-api/pkg/pagination"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var APIKeyListOptions = pagination.Options{
+	SortFields: map[string]string{
+		"created_at": "api_keys.created_at",
+		"name":       "api_keys.name",
+	},
+	DefaultSort:  "created_at",
+	DefaultOrder: "desc",
+}
+
+type APIKeyRepository interface {
+	Create(key *domain.APIKey, audit *domain.AuditLog) error
+	FindByID(id string) (*domain.APIKey, error)
+	FindByHash(hash string) (*domain.APIKey, error)
+	List(query pagination.Query) ([]domain.APIKey, int64, error)
+	Revoke(id string, audit *domain.AuditLog) error
+	TouchLastUsed(id string, at time.Time, interval time.Duration) error
+}
+
+type apiKeyRepository struct {
+	DB *gorm.DB
+}
+
+func NewAPIKeyRepository(db *gorm.DB) APIKeyRepository {
+	return &apiKeyRepository{DB: db}
+}
+
+// Create stores the key with its permissions and hotels, which must already
+// exist.
+func (r *apiKeyRepository) Create(key *domain.APIKey, audit *domain.AuditLog) error {
+	return withAudit(r.DB, audit, func(tx *gorm.DB) error {
+		if err := tx.Omit("CreatedBy", "Permissions", "Hotels").Create(key).Error; err != nil {
+			return err
+		}
+
+		if len(key.Permissions) > 0 {
+			if err := tx.Model(key).Omit("Permissions.*").Association("Permissions").Append(key.Permissions); err != nil {
+				return err
+			}
+		}
+		if len(key.Hotels) > 0 {
+			if err := tx.Model(key).Omit("Hotels.*").Association("Hotels").Append(key.Hotels); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r *apiKeyRepository) FindByID(id string) (*domain.APIKey, error) {
+	var key domain.APIKey
+	err := r.DB.Preload("Permissions").Preload("Hotels").First(&key, "id = ?", id).Error
+
+	return &key, err
+}
+
+func (r *apiKeyRepository) FindByHash(hash string) (*domain.APIKey, error) {
+	var key domain.APIKey
+	err := r.DB.Preload("Permissions").Preload("Hotels").First(&key, "key_hash = ?", hash).Error
+
+	return &key, err
+}
+
+func (r *apiKeyRepository) List(query pagination.Query) ([]domain.APIKey, int64, error) {
+	db := query.Filter(r.DB.Model(&domain.APIKey{}), APIKeyListOptions)
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var keys []domain.APIKey
+	err := query.Paginate(db, APIKeyListOptions, "api_keys.id").
+		Preload("Permissions").Preload("Hotels").Find(&keys).Error
+
+	return keys, total, err
+}
+
+// Revoke marks an active key revoked. It returns gorm.ErrRecordNotFound when
+// there is no such key or it was already revoked.
+func (r *apiKeyRepository) Revoke(id string, audit *domain.AuditLog) error {
+	return withAudit(r.DB, audit, func(tx *gorm.DB) error {
+		result := tx.Model(&domain.APIKey{}).
+			Where("id = ? AND revoked_at IS NULL", id).
+			Update("revoked_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return nil
+	})
+}
+
+// TouchLastUsed records that the key was used at the given time. The row is
+// only written when the last recorded use is older than interval, so a busy
+// key does not cause a write per request.
+func (r *apiKeyRepository) TouchLastUsed(id string, at time.Time, interval time.Duration) error {
+	return r.DB.Model(&domain.APIKey{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, at.Add(-interval)).
+		UpdateColumn("last_used_at", at).Error
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"hotel-booking-api/internal/domain"
+	"hotel-booking-api/pkg/pagination"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AuditFilter narrows an audit log search. Zero values are ignored.
+type AuditFilter struct {
+	ActorID    string
+	EntityType string
+	EntityID   string
+	From       *time.Time
+	To         *time.Time
+	Query      pagination.Query
+}
+
+var AuditListOptions = pagination.Options{
+	SortFields: map[string]string{
+		"created_at": "created_at",
+	},
+	DefaultSort:  "created_at",
+	DefaultOrder: "desc",
+	Filters: map[string]pagination.Filter{
+		"action":     {Column: "action", Op: pagination.OpEqual},
+		"api_key_id": {Column: "api_key_id", Op: pagination.OpEqual},
+		"request_id": {Column: "request_id", Op: pagination.OpEqual},
+	},
+}
+
+// AuditRepository reads the audit log. Entries are written by the
+// repositories that make the audited changes, through withAudit.
+type AuditRepository interface {
+	List(filter AuditFilter) ([]domain.AuditLog, int64, error)
+}
+
+type auditRepository struct {
+	DB *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) AuditRepository {
+	return &auditRepository{DB: db}
+}
+
+func (r *auditRepository) List(filter AuditFilter) ([]domain.AuditLog, int64, error) {
+	query := r.DB.Model(&domain.AuditLog{})
+	if filter.ActorID != "" {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != "" {
+		query = query.Where("entity_id = ?", filter.EntityID)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at <= ?", *filter.To)
+	}
+	query = filter.Query.Filter(query, AuditListOptions)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var entries []domain.AuditLog
+	err := filter.Query.Paginate(query, AuditListOptions, "id").Find(&entries).Error
+
+	return entries, total, err
+}
+
+// withAudit runs write in a transaction that also appends entry to the audit
+// log, so a change is never kept without its entry or the other way round.
+// A nil entry leaves the change unaudited.
+func withAudit(db *gorm.DB, entry *domain.AuditLog, write func(tx *gorm.DB) error) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := write(tx); err != nil {
+			return err
+		}
+		if entry == nil {
+			return nil
+		}
+
+		return tx.Create(entry).Error
+	})
+}
@@ -1,17 +1,63 @@
 package repository
 
 import (
+	"errors"
 	"hotel-booking-api/internal/domain"
+	"hotel-booking-api/pkg/database"
+	"hotel-booking-api/pkg/pagination"
+	"time"
 
 	"gorm.io/gorm"
 )
 
+// BookingFilter narrows an admin booking search. Zero values are ignored.
+type BookingFilter struct {
+	HotelID      string
+	RoomID       string
+	GuestEmail   string
+	Reference    string
+	CheckInFrom  *time.Time
+	CheckInTo    *time.Time
+	CheckOutFrom *time.Time
+	CheckOutTo   *time.Time
+	CreatedFrom  *time.Time
+	CreatedTo    *time.Time
+	Query        pagination.Query
+}
+
+var BookingListOptions = pagination.Options{
+	SortFields: map[string]string{
+		"created_at":  "bookings.created_at",
+		"check_in":    "bookings.check_in",
+		"check_out":   "bookings.check_out",
+		"total_price": "bookings.total_price",
+		"status":      "bookings.status",
+	},
+	DefaultSort:  "created_at",
+	DefaultOrder: "desc",
+	Filters: map[string]pagination.Filter{
+		"status": {Column: "bookings.status", Op: pagination.OpEqual},
+	},
+}
+
+// ErrDuplicateReference is returned by Create when the booking's reference is
+// already taken by another booking.
+var ErrDuplicateReference = errors.New("booking reference already in use")
+
 type BookingRepository interface {
 	Create(booking *domain.Booking) error
-	Update(booking *domain.Booking) error
-	FindByUser(userID string) ([]domain.Booking, error)
+	Update(booking *domain.Booking, audit *domain.AuditLog) error
+	FindByUser(userID string, query pagination.Query) ([]domain.Booking, int64, error)
 	FindByID(id string) (*domain.Booking, error)
 	FindActiveByRoom(roomID string, checkIn, checkOut string) ([]domain.Booking, error)
+	Search(filter BookingFilter) ([]domain.Booking, int64, error)
+	CountUpcomingByUser(userID string) (int64, error)
+	CountUpcomingByRoom(roomID string) (int64, error)
+	CountUpcomingByHotel(hotelID string) (int64, error)
+	CountByRoom(roomID string) (int64, error)
+	CountByHotel(hotelID string) (int64, error)
+	// WithTx returns a repository that runs its queries in tx.
+	WithTx(tx *gorm.DB) BookingRepository
 }
 
 type bookingRepository struct {
@@ -22,26 +68,46 @@ func NewBookingRepository(db *gorm.DB) BookingRepository {
 	return &bookingRepository{DB: db}
 }
 
+func (r *bookingRepository) WithTx(tx *gorm.DB) BookingRepository {
+	return &bookingRepository{DB: tx}
+}
+
 func (r *bookingRepository) Create(booking *domain.Booking) error {
-	return r.DB.Create(booking).Error
+	err := r.DB.Create(booking).Error
+	if database.IsUniqueViolation(err, "idx_bookings_reference") {
+		return ErrDuplicateReference
+	}
+
+	return err
 }
 
-func (r *bookingRepository) Update(booking *domain.Booking) error {
-	return r.DB.Save(booking).Error
+func (r *bookingRepository) Update(booking *domain.Booking, audit *domain.AuditLog) error {
+	return withAudit(r.DB, audit, func(tx *gorm.DB) error {
+		return saveVersioned(tx, booking, &booking.Version)
+	})
 }
 
-func (r *bookingRepository) FindByUser(userID string) ([]domain.Booking, error) {
+func (r *bookingRepository) FindByUser(userID string, query pagination.Query) ([]domain.Booking, int64, error) {
+	db := query.Filter(r.DB.Model(&domain.Booking{}).Where("bookings.user_id = ?", userID), BookingListOptions)
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
 	var bookings []domain.Booking
+	err := query.Paginate(db, BookingListOptions, "bookings.id").
+		Preload("Room", withDeleted).Preload("Room.Hotel", withDeleted).Preload("Payment").
+		Find(&bookings).Error
 
-	err := r.DB.Preload("Room.Hotel").Preload("Payment").
-		Where("user_id = ?", userID).Order("created_at desc").Find(&bookings).Error
-	return bookings, err
+	return bookings, total, err
 }
 
 func (r *bookingRepository) FindByID(id string) (*domain.Booking, error) {
 	var booking domain.Booking
 
-	err := r.DB.Preload("Room.Hotel").Preload("User").Preload("Payment").
+	err := r.DB.Preload("Room", withDeleted).Preload("Room.Hotel", withDeleted).
+		Preload("User").Preload("Guest").Preload("Payment").
 		First(&booking, "id = ?", id).Error
 	return &booking, err
 }
@@ -54,3 +120,110 @@ func (r *bookingRepository) FindActiveByRoom(roomID string, checkIn, checkOut st
 		Find(&bookings).Error
 	return bookings, err
 }
+
+// CountUpcomingByUser counts the user's pending or confirmed bookings that
+// have not checked out yet.
+func (r *bookingRepository) CountUpcomingByUser(userID string) (int64, error) {
+	var count int64
+	err := r.DB.Model(&domain.Booking{}).
+		Where("user_id = ? AND status IN ? AND check_out > ?",
+			userID, []string{domain.BookingStatusPending, domain.BookingStatusConfirmed}, time.Now()).
+		Count(&count).Error
+
+	return count, err
+}
+
+// CountUpcomingByRoom counts pending or confirmed bookings of the room that
+// have not checked out yet.
+func (r *bookingRepository) CountUpcomingByRoom(roomID string) (int64, error) {
+	var count int64
+	err := r.DB.Model(&domain.Booking{}).
+		Where("room_id = ? AND status IN ? AND check_out > ?",
+			roomID, []string{domain.BookingStatusPending, domain.BookingStatusConfirmed}, time.Now()).
+		Count(&count).Error
+
+	return count, err
+}
+
+// CountUpcomingByHotel counts pending or confirmed bookings of any of the
+// hotel's rooms that have not checked out yet.
+func (r *bookingRepository) CountUpcomingByHotel(hotelID string) (int64, error) {
+	var count int64
+	err := r.DB.Model(&domain.Booking{}).
+		Where("room_id IN (SELECT id FROM rooms WHERE hotel_id = ?)", hotelID).
+		Where("status IN ? AND check_out > ?",
+			[]string{domain.BookingStatusPending, domain.BookingStatusConfirmed}, time.Now()).
+		Count(&count).Error
+
+	return count, err
+}
+
+// CountByRoom counts every booking of the room, past or future.
+func (r *bookingRepository) CountByRoom(roomID string) (int64, error) {
+	var count int64
+	err := r.DB.Model(&domain.Booking{}).Where("room_id = ?", roomID).Count(&count).Error
+
+	return count, err
+}
+
+// CountByHotel counts every booking of the hotel's rooms, deleted rooms
+// included.
+func (r *bookingRepository) CountByHotel(hotelID string) (int64, error) {
+	var count int64
+	err := r.DB.Model(&domain.Booking{}).
+		Where("room_id IN (SELECT id FROM rooms WHERE hotel_id = ?)", hotelID).
+		Count(&count).Error
+
+	return count, err
+}
+
+func (r *bookingRepository) Search(filter BookingFilter) ([]domain.Booking, int64, error) {
+	query := r.DB.Model(&domain.Booking{}).
+		Joins("JOIN rooms ON rooms.id = bookings.room_id").
+		Joins("LEFT JOIN users ON users.id = bookings.user_id").
+		Joins("LEFT JOIN guests ON guests.id = bookings.guest_id")
+
+	if filter.HotelID != "" {
+		query = query.Where("rooms.hotel_id = ?", filter.HotelID)
+	}
+	if filter.RoomID != "" {
+		query = query.Where("bookings.room_id = ?", filter.RoomID)
+	}
+	if filter.GuestEmail != "" {
+		query = query.Where("LOWER(users.email) = LOWER(?) OR LOWER(guests.email) = LOWER(?)", filter.GuestEmail, filter.GuestEmail)
+	}
+	if filter.Reference != "" {
+		query = query.Where("bookings.reference = UPPER(?)", filter.Reference)
+	}
+	if filter.CheckInFrom != nil {
+		query = query.Where("bookings.check_in >= ?", *filter.CheckInFrom)
+	}
+	if filter.CheckInTo != nil {
+		query = query.Where("bookings.check_in <= ?", *filter.CheckInTo)
+	}
+	if filter.CheckOutFrom != nil {
+		query = query.Where("bookings.check_out >= ?", *filter.CheckOutFrom)
+	}
+	if filter.CheckOutTo != nil {
+		query = query.Where("bookings.check_out <= ?", *filter.CheckOutTo)
+	}
+	if filter.CreatedFrom != nil {
+		query = query.Where("bookings.created_at >= ?", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		query = query.Where("bookings.created_at <= ?", *filter.CreatedTo)
+	}
+	query = filter.Query.Filter(query, BookingListOptions)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var bookings []domain.Booking
+	err := filter.Query.Paginate(query, BookingListOptions, "bookings.id").
+		Preload("Room", withDeleted).Preload("Room.Hotel", withDeleted).Preload("User").Preload("Guest").Preload("Payment").
+		Find(&bookings).Error
+
+	return bookings, total, err
+}
@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"database/sql"
+	"hotel-booking-api/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// UserData is everything stored about one user, as read for a data export.
+type UserData struct {
+	User     domain.User
+	Bookings []domain.Booking
+	Reviews  []domain.Review
+	Sessions []domain.RefreshToken
+	// AuditLog holds the changes the user made and those made to their
+	// account.
+	AuditLog []domain.AuditLog
+}
+
+type DataExportRepository interface {
+	Create(export *domain.DataExport) error
+	FindByID(id string) (*domain.DataExport, error)
+	ListByUser(userID string) ([]domain.DataExport, error)
+	CountActiveByUser(userID string) (int64, error)
+	ListRunnable(staleBefore time.Time) ([]domain.DataExport, error)
+	Claim(id string, staleBefore time.Time) (bool, error)
+	Complete(id, fileKey string, size int64, expiresAt time.Time) error
+	Fail(id, reason string) error
+	ListExpired(now time.Time) ([]domain.DataExport, error)
+	MarkExpired(id string) error
+	LoadUserData(userID string) (*UserData, error)
+}
+
+type dataExportRepository struct {
+	DB *gorm.DB
+}
+
+func NewDataExportRepository(db *gorm.DB) DataExportRepository {
+	return &dataExportRepository{DB: db}
+}
+
+func (r *dataExportRepository) Create(export *domain.DataExport) error {
+	return r.DB.Omit("User").Create(export).Error
+}
+
+func (r *dataExportRepository) FindByID(id string) (*domain.DataExport, error) {
+	var export domain.DataExport
+	if err := r.DB.First(&export, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+
+	return &export, nil
+}
+
+func (r *dataExportRepository) ListByUser(userID string) ([]domain.DataExport, error) {
+	var exports []domain.DataExport
+	err := r.DB.Where("user_id = ?", userID).Order("created_at DESC").Find(&exports).Error
+
+	return exports, err
+}
+
+// CountActiveByUser counts the user's exports that are still being built.
+func (r *dataExportRepository) CountActiveByUser(userID string) (int64, error) {
+	var count int64
+	err := r.DB.Model(&domain.DataExport{}).
+		Where("user_id = ? AND status IN ?", userID, []string{domain.ExportStatusPending, domain.ExportStatusProcessing}).
+		Count(&count).Error
+
+	return count, err
+}
+
+// ListRunnable returns pending exports, oldest first, together with exports
+// whose worker stopped updating them before staleBefore.
+func (r *dataExportRepository) ListRunnable(staleBefore time.Time) ([]domain.DataExport, error) {
+	var exports []domain.DataExport
+	err := r.DB.Where(runnableExport, domain.ExportStatusPending, domain.ExportStatusProcessing, staleBefore).
+		Order("created_at").Find(&exports).Error
+
+	return exports, err
+}
+
+// Claim marks a runnable export as processing. It reports false when another
+// worker got to it first.
+func (r *dataExportRepository) Claim(id string, staleBefore time.Time) (bool, error) {
+	result := r.DB.Model(&domain.DataExport{}).
+		Where("id = ?", id).
+		Where(runnableExport, domain.ExportStatusPending, domain.ExportStatusProcessing, staleBefore).
+		Update("status", domain.ExportStatusProcessing)
+
+	return result.RowsAffected == 1, result.Error
+}
+
+// Complete records the archive of an export being processed. It returns
+// gorm.ErrRecordNotFound when the export was cancelled in the meantime.
+func (r *dataExportRepository) Complete(id, fileKey string, size int64, expiresAt time.Time) error {
+	result := r.DB.Model(&domain.DataExport{}).
+		Where("id = ? AND status = ?", id, domain.ExportStatusProcessing).
+		Updates(map[string]any{
+			"status":       domain.ExportStatusReady,
+			"file_key":     fileKey,
+			"size":         size,
+			"completed_at": time.Now(),
+			"expires_at":   expiresAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (r *dataExportRepository) Fail(id, reason string) error {
+	return r.DB.Model(&domain.DataExport{}).Where("id = ?", id).Updates(map[string]any{
+		"status":       domain.ExportStatusFailed,
+		"error":        reason,
+		"completed_at": time.Now(),
+	}).Error
+}
+
+// ListExpired returns ready exports whose archive should be deleted.
+func (r *dataExportRepository) ListExpired(now time.Time) ([]domain.DataExport, error) {
+	var exports []domain.DataExport
+	err := r.DB.Where("status = ? AND expires_at <= ?", domain.ExportStatusReady, now).Find(&exports).Error
+
+	return exports, err
+}
+
+func (r *dataExportRepository) MarkExpired(id string) error {
+	return r.DB.Model(&domain.DataExport{}).Where("id = ?", id).Updates(map[string]any{
+		"status":   domain.ExportStatusExpired,
+		"file_key": "",
+	}).Error
+}
+
+// LoadUserData reads the user's records in one read-only snapshot, so the
+// export is consistent even while the user keeps using their account.
+func (r *dataExportRepository) LoadUserData(userID string) (*UserData, error) {
+	var data UserData
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&data.User, "id = ?", userID).Error; err != nil {
+			return err
+		}
+
+		err := tx.Where("user_id = ?", userID).
+			Preload("Room", withDeleted).Preload("Room.Hotel", withDeleted).Preload("Payment").
+			Order("created_at").Find(&data.Bookings).Error
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Where("user_id = ?", userID).Order("created_at").Find(&data.Reviews).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("user_id = ?", userID).Order("created_at").Find(&data.Sessions).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("actor_id = ? OR (entity_type = ? AND entity_id = ?)", userID, domain.AuditEntityUser, userID).
+			Order("created_at").Find(&data.AuditLog).Error
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+const runnableExport = "status = ? OR (status = ? AND updated_at < ?)"
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"hotel-booking-api/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+type GuestRepository interface {
+	Create(guest *domain.Guest) error
+	FindByEmail(email string) (*domain.Guest, error)
+}
+
+type guestRepository struct {
+	DB *gorm.DB
+}
+
+func NewGuestRepository(db *gorm.DB) GuestRepository {
+	return &guestRepository{DB: db}
+}
+
+func (r *guestRepository) Create(guest *domain.Guest) error {
+	return r.DB.Create(guest).Error
+}
+
+func (r *guestRepository) FindByEmail(email string) (*domain.Guest, error) {
+	var guest domain.Guest
+	if err := r.DB.Where("email = ?", email).First(&guest).Error; err != nil {
+		return nil, err
+	}
+
+	return &guest, nil
+}
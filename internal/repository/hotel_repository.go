@@ -1,49 +1,459 @@
 package repository
 
 import (
+	"database/sql"
 	"hotel-booking-api/internal/domain"
+	"hotel-booking-api/pkg/pagination"
+	"math"
+	"time"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+var HotelListOptions = pagination.Options{
+	SortFields: map[string]string{
+		"name":       "name",
+		"location":   "location",
+		"rating":     "average_rating",
+		"created_at": "created_at",
+	},
+	DefaultSort:  "created_at",
+	DefaultOrder: "desc",
+	Filters: map[string]pagination.Filter{
+		"name":       {Column: "name", Op: pagination.OpILike},
+		"location":   {Column: "location", Op: pagination.OpILike},
+		"min_rating": {Column: "average_rating", Op: pagination.OpGte},
+	},
+}
+
+// DeletedHotelListOptions lists soft-deleted hotels, most recently deleted
+// first.
+var DeletedHotelListOptions = pagination.Options{
+	SortFields: map[string]string{
+		"name":       "name",
+		"deleted_at": "deleted_at",
+	},
+	DefaultSort:  "deleted_at",
+	DefaultOrder: "desc",
+	Filters: map[string]pagination.Filter{
+		"name": {Column: "name", Op: pagination.OpILike},
+	},
+}
+
+// HotelSearchFilter describes a guest's search. Dates are optional but must be
+// given together; when present only rooms free for the whole stay count.
+type HotelSearchFilter struct {
+	Text        string
+	Destination string
+	CheckIn     *time.Time
+	CheckOut    *time.Time
+	Guests      int
+	MinPrice    float64
+	MaxPrice    float64
+	Near        *GeoPoint
+	RadiusKm    float64
+	Bounds      *GeoBounds
+
+	// Amenities must all be offered by the hotel; RoomAmenities must all be
+	// offered by the matching room.
+	Amenities     []string
+	RoomAmenities []string
+
+	Query pagination.Query
+}
+
+type GeoPoint struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// GeoBounds is a latitude/longitude box. MinLongitude greater than
+// MaxLongitude means the box crosses the antimeridian.
+type GeoBounds struct {
+	MinLatitude  float64
+	MinLongitude float64
+	MaxLatitude  float64
+	MaxLongitude float64
+}
+
+const earthRadiusKm = 6371.0
+
+// haversineSQL computes the great-circle distance in kilometres between a
+// hotel and a point with plain SQL so no PostGIS/earthdistance extension is
+// needed. LEAST guards ASIN against rounding just above 1. Parameters:
+// latitude, latitude, longitude.
+const haversineSQL = `(2 * 6371.0 * ASIN(LEAST(1, SQRT(
+	POWER(SIN(RADIANS(hotels.latitude - ?) / 2), 2) +
+	COS(RADIANS(?)) * COS(RADIANS(hotels.latitude)) *
+	POWER(SIN(RADIANS(hotels.longitude - ?) / 2), 2)))))`
+
+// hotelSearchDocumentSQL is the weighted full-text document for a hotel. The
+// GIN index created by MigrateSearchIndexes uses this exact expression, so
+// queries must reference it verbatim for the index to be picked up.
+const hotelSearchDocumentSQL = `(setweight(to_tsvector('simple', coalesce(hotels.name, '')), 'A') || ` +
+	`setweight(to_tsvector('simple', coalesce(hotels.city, '') || ' ' || coalesce(hotels.country, '') || ' ' || coalesce(hotels.location, '')), 'B') || ` +
+	`setweight(to_tsvector('simple', coalesce(hotels.amenity_text, '')), 'C') || ` +
+	`setweight(to_tsvector('simple', coalesce(hotels.description, '')), 'D'))`
+
+const headlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxWords=30, MinWords=10, MaxFragments=2"
+
+var HotelSearchOptions = pagination.Options{
+	SortFields: map[string]string{
+		"price":     "lowest_price",
+		"name":      "hotels.name",
+		"distance":  "distance_km",
+		"relevance": "relevance",
+		"rating":    "hotels.average_rating",
+	},
+	DefaultSort:  "price",
+	DefaultOrder: "asc",
+}
+
+// HotelTextSearchOptions are HotelSearchOptions for searches with free text
+// and no sort, which rank best matches first.
+var HotelTextSearchOptions = pagination.Options{
+	SortFields:   HotelSearchOptions.SortFields,
+	DefaultSort:  "relevance",
+	DefaultOrder: "desc",
+}
+
 type HotelRepository interface {
-	Create(hotel *domain.Hotel) error
-	Update(hotel *domain.Hotel) error
-	FindAll() ([]domain.Hotel, error)
+	Create(hotel *domain.Hotel, audit *domain.AuditLog) error
+	Update(hotel *domain.Hotel, audit *domain.AuditLog) error
+	FindAll(query pagination.Query, amenities []string) ([]domain.Hotel, int64, error)
 	FindByID(id string) (*domain.Hotel, error)
-	Delete(id string) error
+	FindByIDWithDeleted(id string) (*domain.Hotel, error)
+	FindDeleted(query pagination.Query) ([]domain.Hotel, int64, error)
+	Search(filter HotelSearchFilter) ([]domain.HotelSearchResult, int64, error)
+	Autocomplete(text string, limit int) ([]domain.SearchSuggestion, error)
+	Delete(id string, audit *domain.AuditLog) error
+	Restore(hotel *domain.Hotel, audit *domain.AuditLog) error
+	Purge(id string, audit *domain.AuditLog) error
 }
 
 type hotelRepository struct {
 	DB *gorm.DB
+	// trigram is set when pg_trgm is installed; see MigrateSearchIndexes.
+	trigram bool
 }
 
-func NewHotelRepository(db *gorm.DB) HotelRepository {
-	return &hotelRepository{DB: db}
+func NewHotelRepository(db *gorm.DB, trigram bool) HotelRepository {
+	return &hotelRepository{DB: db, trigram: trigram}
 }
 
-func (r *hotelRepository) Create(hotel *domain.Hotel) error {
-	return r.DB.Create(hotel).Error
+func (r *hotelRepository) Create(hotel *domain.Hotel, audit *domain.AuditLog) error {
+	return withAudit(r.DB, audit, func(tx *gorm.DB) error {
+		return tx.Create(hotel).Error
+	})
 }
 
-func (r *hotelRepository) Update(hotel *domain.Hotel) error {
-	return r.DB.Save(hotel).Error
+func (r *hotelRepository) Update(hotel *domain.Hotel, audit *domain.AuditLog) error {
+	return withAudit(r.DB, audit, func(tx *gorm.DB) error {
+		return saveVersioned(tx, hotel, &hotel.Version)
+	})
 }
 
-func (r *hotelRepository) FindAll() ([]domain.Hotel, error) {
+func (r *hotelRepository) FindAll(query pagination.Query, amenities []string) ([]domain.Hotel, int64, error) {
+	db := query.Filter(r.DB.Model(&domain.Hotel{}), HotelListOptions)
+	if len(amenities) > 0 {
+		db = db.Where("hotels.id IN (?)", hotelsWithAmenities(r.DB, amenities))
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
 	var hotels []domain.Hotel
-	err := r.DB.Preload("Rooms").Find(&hotels).Error
+	err := query.Paginate(db, HotelListOptions, "id").
+		Preload("Amenities").Preload("Photos", coverPhoto).Find(&hotels).Error
 
-	return hotels, err
+	return hotels, total, err
 }
 
 func (r *hotelRepository) FindByID(id string) (*domain.Hotel, error) {
 	var hotel domain.Hotel
-	err := r.DB.Preload("Rooms").First(&hotel, "id = ?", id).Error
+	err := r.DB.Preload("Rooms.Amenities").Preload("Rooms.Photos", orderedPhotos).
+		Preload("Amenities").Preload("Photos", hotelPhotos).First(&hotel, "id = ?", id).Error
+
+	return &hotel, err
+}
+
+// FindByIDWithDeleted finds a hotel whether or not it is deleted.
+func (r *hotelRepository) FindByIDWithDeleted(id string) (*domain.Hotel, error) {
+	var hotel domain.Hotel
+	err := r.DB.Unscoped().First(&hotel, "id = ?", id).Error
 
 	return &hotel, err
 }
 
-func (r *hotelRepository) Delete(id string) error {
-	return r.DB.Delete(&domain.Hotel{}, "id = ?", id).Error
+func (r *hotelRepository) FindDeleted(query pagination.Query) ([]domain.Hotel, int64, error) {
+	db := query.Filter(r.DB.Unscoped().Model(&domain.Hotel{}).Where("deleted_at IS NOT NULL"), DeletedHotelListOptions)
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var hotels []domain.Hotel
+	err := query.Paginate(db, DeletedHotelListOptions, "id").Find(&hotels).Error
+
+	return hotels, total, err
+}
+
+func (r *hotelRepository) Search(filter HotelSearchFilter) ([]domain.HotelSearchResult, int64, error) {
+	rooms := r.DB.Table("hotels").
+		Joins("JOIN rooms ON rooms.hotel_id = hotels.id").
+		Where("hotels.deleted_at IS NULL AND rooms.deleted_at IS NULL").
+		Where("rooms.availability > 0")
+
+	if filter.Destination != "" {
+		pattern := "%" + pagination.EscapeLike(filter.Destination) + "%"
+		rooms = rooms.Where("(hotels.name ILIKE @pattern OR hotels.city ILIKE @pattern OR hotels.country ILIKE @pattern OR hotels.location ILIKE @pattern)",
+			sql.Named("pattern", pattern))
+	}
+	if filter.Guests > 0 {
+		rooms = rooms.Where("rooms.capacity >= ?", filter.Guests)
+	}
+	if filter.MinPrice > 0 {
+		rooms = rooms.Where("rooms.price_per_night >= ?", filter.MinPrice)
+	}
+	if filter.MaxPrice > 0 {
+		rooms = rooms.Where("rooms.price_per_night <= ?", filter.MaxPrice)
+	}
+	if filter.CheckIn != nil && filter.CheckOut != nil {
+		rooms = rooms.Where(`NOT EXISTS (
+			SELECT 1 FROM bookings
+			WHERE bookings.room_id = rooms.id
+			AND bookings.status IN ?
+			AND bookings.check_in < ? AND bookings.check_out > ?)`,
+			[]string{domain.BookingStatusPending, domain.BookingStatusConfirmed}, *filter.CheckOut, *filter.CheckIn)
+	}
+
+	if len(filter.Amenities) > 0 {
+		rooms = rooms.Where("hotels.id IN (?)", hotelsWithAmenities(r.DB, filter.Amenities))
+	}
+	if len(filter.RoomAmenities) > 0 {
+		rooms = rooms.Where("rooms.id IN (?)", roomsWithAmenities(r.DB, filter.RoomAmenities))
+	}
+	if filter.Bounds != nil {
+		b := filter.Bounds
+		rooms = rooms.Where("hotels.latitude BETWEEN ? AND ?", b.MinLatitude, b.MaxLatitude)
+		if b.MinLongitude <= b.MaxLongitude {
+			rooms = rooms.Where("hotels.longitude BETWEEN ? AND ?", b.MinLongitude, b.MaxLongitude)
+		} else {
+			rooms = rooms.Where("(hotels.longitude >= ? OR hotels.longitude <= ?)", b.MinLongitude, b.MaxLongitude)
+		}
+	}
+
+	columns := "hotels.*, MIN(rooms.price_per_night) AS lowest_price, COUNT(rooms.id) AS available_rooms"
+	var columnArgs []any
+	if filter.Near != nil {
+		p := filter.Near
+		distance := gorm.Expr(haversineSQL, p.Latitude, p.Latitude, p.Longitude)
+
+		// A cheap latitude pre-filter lets the coordinates index discard
+		// most rows before the exact distance is computed.
+		latDelta := filter.RadiusKm / (math.Pi * earthRadiusKm / 180)
+		rooms = rooms.Where("hotels.latitude BETWEEN ? AND ?", p.Latitude-latDelta, p.Latitude+latDelta).
+			Where("? <= ?", distance, filter.RadiusKm)
+
+		columns += ", ? AS distance_km"
+		columnArgs = append(columnArgs, distance)
+	}
+
+	if filter.Text != "" {
+		// Full-text matches use the GIN document index; partialMatch catches
+		// typos and partial words.
+		tsQuery := gorm.Expr("websearch_to_tsquery('simple', ?)", filter.Text)
+		partial, similarity := r.partialMatch(filter.Text)
+		rooms = rooms.Where("("+hotelSearchDocumentSQL+" @@ ? OR ?)", tsQuery, partial)
+
+		columns += ", ts_rank_cd(" + hotelSearchDocumentSQL + ", ?) + ? AS relevance" +
+			", ts_headline('simple', hotels.name, ?, ?) AS name_highlight" +
+			", ts_headline('simple', coalesce(hotels.description, ''), ?, ?) AS description_snippet"
+		columnArgs = append(columnArgs, tsQuery, similarity,
+			tsQuery, headlineOptions, tsQuery, headlineOptions)
+	}
+
+	matches := rooms.Select(columns, columnArgs...).Group("hotels.id")
+
+	var total int64
+	if err := r.DB.Table("(?) AS matches", matches).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var results []domain.HotelSearchResult
+	if err := filter.Query.Paginate(matches, HotelSearchOptions, "hotels.id").Scan(&results).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := r.loadRelations(results); err != nil {
+		return nil, 0, err
+	}
+
+	return results, total, nil
+}
+
+// partialMatch matches text against a hotel's name, location and city and
+// scores the match. With pg_trgm the word similarity operator (<%) tolerates
+// typos; without it text must appear as a substring and scores nothing.
+func (r *hotelRepository) partialMatch(text string) (match, score clause.Expr) {
+	if r.trigram {
+		return gorm.Expr("(? <% hotels.name OR ? <% hotels.location OR ? <% hotels.city)", text, text, text),
+			gorm.Expr("GREATEST(word_similarity(?, hotels.name), word_similarity(?, hotels.location), word_similarity(?, hotels.city))", text, text, text)
+	}
+
+	pattern := "%" + pagination.EscapeLike(text) + "%"
+	return gorm.Expr("(hotels.name ILIKE ? OR hotels.location ILIKE ? OR hotels.city ILIKE ?)", pattern, pattern, pattern),
+		gorm.Expr("0")
+}
+
+// loadRelations fills in hotel amenities and cover photos for search results,
+// which are read with Scan and therefore cannot use Preload.
+func (r *hotelRepository) loadRelations(results []domain.HotelSearchResult) error {
+	if len(results) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, len(results))
+	for i := range results {
+		ids[i] = results[i].ID
+	}
+
+	var hotels []domain.Hotel
+	err := r.DB.Select("id").Preload("Amenities").Preload("Photos", coverPhoto).
+		Find(&hotels, "id IN ?", ids).Error
+	if err != nil {
+		return err
+	}
+
+	loaded := make(map[uuid.UUID]domain.Hotel, len(hotels))
+	for _, hotel := range hotels {
+		loaded[hotel.ID] = hotel
+	}
+	for i := range results {
+		results[i].Amenities = loaded[results[i].ID].Amenities
+		results[i].Photos = loaded[results[i].ID].Photos
+	}
+
+	return nil
+}
+
+// orderedPhotos, hotelPhotos and coverPhoto scope photo preloads. Hotel
+// photos exclude those belonging to one of its rooms; lists only carry the
+// cover.
+func orderedPhotos(db *gorm.DB) *gorm.DB {
+	return db.Order("position, created_at")
+}
+
+func hotelPhotos(db *gorm.DB) *gorm.DB {
+	return orderedPhotos(db).Where("room_id IS NULL")
+}
+
+func coverPhoto(db *gorm.DB) *gorm.DB {
+	return db.Where("room_id IS NULL AND is_cover")
+}
+
+// withDeleted lets a preload see soft-deleted rows, for records such as
+// bookings that keep pointing at a hotel or room after it is deleted.
+func withDeleted(db *gorm.DB) *gorm.DB {
+	return db.Unscoped()
+}
+
+// hotelsWithAmenities selects IDs of hotels offering every amenity code.
+func hotelsWithAmenities(db *gorm.DB, codes []string) *gorm.DB {
+	return db.Table("hotel_amenities").
+		Select("hotel_amenities.hotel_id").
+		Joins("JOIN amenities ON amenities.id = hotel_amenities.amenity_id").
+		Where("amenities.code IN ?", codes).
+		Group("hotel_amenities.hotel_id").
+		Having("COUNT(DISTINCT amenities.code) = ?", len(codes))
+}
+
+// roomsWithAmenities selects IDs of rooms offering every amenity code.
+func roomsWithAmenities(db *gorm.DB, codes []string) *gorm.DB {
+	return db.Table("room_amenities").
+		Select("room_amenities.room_id").
+		Joins("JOIN amenities ON amenities.id = room_amenities.amenity_id").
+		Where("amenities.code IN ?", codes).
+		Group("room_amenities.room_id").
+		Having("COUNT(DISTINCT amenities.code) = ?", len(codes))
+}
+
+// Delete soft-deletes the hotel together with its rooms. The rooms are
+// stamped with the hotel's deletion time so Restore can tell them apart from
+// rooms deleted on their own.
+func (r *hotelRepository) Delete(id string, audit *domain.AuditLog) error {
+	return withAudit(r.DB, audit, func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Model(&domain.Room{}).Where("hotel_id = ?", id).Update("deleted_at", now).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&domain.Hotel{}).Where("id = ?", id).Update("deleted_at", now).Error
+	})
+}
+
+// Restore undeletes the hotel and the rooms that were deleted with it.
+func (r *hotelRepository) Restore(hotel *domain.Hotel, audit *domain.AuditLog) error {
+	return withAudit(r.DB, audit, func(tx *gorm.DB) error {
+		err := tx.Unscoped().Model(&domain.Room{}).
+			Where("hotel_id = ? AND deleted_at = ?", hotel.ID, hotel.DeletedAt.Time).
+			Update("deleted_at", nil).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Unscoped().Model(&domain.Hotel{}).Where("id = ?", hotel.ID).Update("deleted_at", nil).Error
+	})
+}
+
+// Purge permanently deletes the hotel and, through the foreign keys, its
+// rooms, photos and staff assignments. Bookings restrict the delete, so a
+// hotel with booking history cannot be purged.
+func (r *hotelRepository) Purge(id string, audit *domain.AuditLog) error {
+	return withAudit(r.DB, audit, func(tx *gorm.DB) error {
+		return tx.Unscoped().Delete(&domain.Hotel{}, "id = ?", id).Error
+	})
+}
+
+func (r *hotelRepository) Autocomplete(text string, limit int) ([]domain.SearchSuggestion, error) {
+	prefix := pagination.EscapeLike(text) + "%"
+
+	// Without pg_trgm only prefixes match.
+	similar := func(column string) (match, score string) {
+		if !r.trigram {
+			return "FALSE", "0"
+		}
+		return "@text <% " + column, "word_similarity(@text, " + column + ")"
+	}
+	placeMatch, placeScore := similar("place")
+	nameMatch, nameScore := similar("hotels.name")
+
+	var suggestions []domain.SearchSuggestion
+	err := r.DB.Raw(`
+		WITH destinations AS (
+			SELECT DISTINCT place FROM hotels, UNNEST(ARRAY[hotels.city, hotels.location]) AS place
+			WHERE place <> '' AND hotels.deleted_at IS NULL
+		)
+		SELECT * FROM (
+			SELECT 'destination' AS type, NULL::uuid AS hotel_id, place AS text,
+				`+placeScore+` + CASE WHEN place ILIKE @prefix THEN 1 ELSE 0 END AS score
+			FROM destinations
+			WHERE place ILIKE @prefix OR `+placeMatch+`
+			UNION ALL
+			SELECT 'hotel' AS type, hotels.id AS hotel_id, hotels.name AS text,
+				`+nameScore+` + CASE WHEN hotels.name ILIKE @prefix THEN 1 ELSE 0 END AS score
+			FROM hotels
+			WHERE (hotels.name ILIKE @prefix OR `+nameMatch+`) AND hotels.deleted_at IS NULL
+		) AS suggestions
+		ORDER BY score DESC, text
+		LIMIT @limit`,
+		sql.Named("text", text), sql.Named("prefix", prefix), sql.Named("limit", limit),
+	).Scan(&suggestions).Error
+
+	return suggestions, err
 }
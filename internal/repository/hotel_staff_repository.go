@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"hotel-booking-api/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type HotelStaffRepository interface {
+	Assign(staff *domain.HotelStaff, audit *domain.AuditLog) error
+	Remove(hotelID, userID string, audit *domain.AuditLog) error
+	FindRole(userID, hotelID string) (string, error)
+	ListByHotel(hotelID string) ([]domain.HotelStaff, error)
+	// WithTx returns a repository that runs its queries in tx.
+	WithTx(tx *gorm.DB) HotelStaffRepository
+}
+
+type hotelStaffRepository struct {
+	DB *gorm.DB
+}
+
+func NewHotelStaffRepository(db *gorm.DB) HotelStaffRepository {
+	return &hotelStaffRepository{DB: db}
+}
+
+func (r *hotelStaffRepository) WithTx(tx *gorm.DB) HotelStaffRepository {
+	return &hotelStaffRepository{DB: tx}
+}
+
+// Assign adds or updates the user's role at the hotel and brings the user's
+// account role in line with their assignments.
+func (r *hotelStaffRepository) Assign(staff *domain.HotelStaff, audit *domain.AuditLog) error {
+	return withAudit(r.DB, audit, func(tx *gorm.DB) error {
+		err := tx.Omit("Hotel", "User").Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "hotel_id"}, {Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"role"}),
+		}).Create(staff).Error
+		if err != nil {
+			return err
+		}
+
+		return syncStaffRole(tx, staff.UserID)
+	})
+}
+
+func (r *hotelStaffRepository) Remove(hotelID, userID string, audit *domain.AuditLog) error {
+	return withAudit(r.DB, audit, func(tx *gorm.DB) error {
+		result := tx.Delete(&domain.HotelStaff{}, "hotel_id = ? AND user_id = ?", hotelID, userID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		uid, err := uuid.Parse(userID)
+		if err != nil {
+			return err
+		}
+
+		return syncStaffRole(tx, uid)
+	})
+}
+
+func (r *hotelStaffRepository) FindRole(userID, hotelID string) (string, error) {
+	var staff domain.HotelStaff
+	err := r.DB.Select("role").Where("user_id = ? AND hotel_id = ?", userID, hotelID).Take(&staff).Error
+
+	return staff.Role, err
+}
+
+func (r *hotelStaffRepository) ListByHotel(hotelID string) ([]domain.HotelStaff, error) {
+	var staff []domain.HotelStaff
+	err := r.DB.Preload("User").Where("hotel_id = ?", hotelID).Order("created_at").Find(&staff).Error
+
+	return staff, err
+}
+
+// syncStaffRole sets a non-admin user's role from their strongest remaining
+// assignment, falling back to CUSTOMER when they have none.
+func syncStaffRole(tx *gorm.DB, userID uuid.UUID) error {
+	var roles []string
+	if err := tx.Model(&domain.HotelStaff{}).Where("user_id = ?", userID).Distinct().Pluck("role", &roles).Error; err != nil {
+		return err
+	}
+
+	role := domain.RoleCustomer
+	for _, r := range roles {
+		if r == domain.RoleHotelManager {
+			role = domain.RoleHotelManager
+			break
+		}
+		role = domain.RoleStaff
+	}
+
+	return tx.Model(&domain.User{}).Where("id = ? AND role <> ?", userID, domain.RoleAdmin).Update("role", role).Error
+}
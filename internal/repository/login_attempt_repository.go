@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"hotel-booking-api/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type LoginAttemptRepository interface {
+	Find(subjects ...string) ([]domain.LoginAttempt, error)
+	RecordFailure(subject string, window time.Duration) (*domain.LoginAttempt, error)
+	Lock(subject string, until time.Time, audit *domain.AuditLog) error
+	Reset(subject string, audit *domain.AuditLog) error
+}
+
+type loginAttemptRepository struct {
+	DB *gorm.DB
+}
+
+func NewLoginAttemptRepository(db *gorm.DB) LoginAttemptRepository {
+	return &loginAttemptRepository{DB: db}
+}
+
+func (r *loginAttemptRepository) Find(subjects ...string) ([]domain.LoginAttempt, error) {
+	var attempts []domain.LoginAttempt
+	err := r.DB.Where("subject IN ?", subjects).Find(&attempts).Error
+
+	return attempts, err
+}
+
+// RecordFailure counts a failed login for subject and returns the updated
+// counter. Failures older than window are forgotten, so the count restarts.
+func (r *loginAttemptRepository) RecordFailure(subject string, window time.Duration) (*domain.LoginAttempt, error) {
+	var attempt domain.LoginAttempt
+	now := time.Now()
+
+	err := r.DB.Raw(`
+		INSERT INTO login_attempts (subject, failures, last_failure_at)
+		VALUES (?, 1, ?)
+		ON CONFLICT (subject) DO UPDATE SET
+			failures = CASE
+				WHEN login_attempts.last_failure_at < ? THEN 1
+				ELSE login_attempts.failures + 1
+			END,
+			last_failure_at = EXCLUDED.last_failure_at
+		RETURNING *`,
+		subject, now, now.Add(-window),
+	).Scan(&attempt).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &attempt, nil
+}
+
+func (r *loginAttemptRepository) Lock(subject string, until time.Time, audit *domain.AuditLog) error {
+	return withAudit(r.DB, audit, func(tx *gorm.DB) error {
+		return tx.Model(&domain.LoginAttempt{}).Where("subject = ?", subject).Update("locked_until", until).Error
+	})
+}
+
+func (r *loginAttemptRepository) Reset(subject string, audit *domain.AuditLog) error {
+	return withAudit(r.DB, audit, func(tx *gorm.DB) error {
+		return tx.Delete(&domain.LoginAttempt{}, "subject = ?", subject).Error
+	})
+}
@@ -9,7 +9,10 @@ import (
 type PaymentRepository interface {
 	Create(payment *domain.Payment) error
 	Update(payment *domain.Payment) error
+	Settle(payment *domain.Payment, booking *domain.Booking, audit *domain.AuditLog) error
 	FindByBookingID(bookingID string) (*domain.Payment, error)
+	// WithTx returns a repository that runs its queries in tx.
+	WithTx(tx *gorm.DB) PaymentRepository
 }
 
 type paymentRepository struct {
@@ -20,6 +23,10 @@ func NewPaymentRepository(db *gorm.DB) PaymentRepository {
 	return &paymentRepository{DB: db}
 }
 
+func (r *paymentRepository) WithTx(tx *gorm.DB) PaymentRepository {
+	return &paymentRepository{DB: tx}
+}
+
 func (r *paymentRepository) Create(payment *domain.Payment) error {
 	return r.DB.Create(payment).Error
 }
@@ -28,6 +35,18 @@ func (r *paymentRepository) Update(payment *domain.Payment) error {
 	return r.DB.Save(payment).Error
 }
 
+// Settle saves the outcome of a payment together with the booking status it
+// leads to.
+func (r *paymentRepository) Settle(payment *domain.Payment, booking *domain.Booking, audit *domain.AuditLog) error {
+	return withAudit(r.DB, audit, func(tx *gorm.DB) error {
+		if err := tx.Save(payment).Error; err != nil {
+			return err
+		}
+
+		return saveVersioned(tx, booking, &booking.Version)
+	})
+}
+
 func (r *paymentRepository) FindByBookingID(bookingID string) (*domain.Payment, error) {
 	var payment domain.Payment
 
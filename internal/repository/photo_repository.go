@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"hotel-booking-api/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+type PhotoRepository interface {
+	Create(photo *domain.Photo) error
+	Delete(id string) error
+	FindByID(id string) (*domain.Photo, error)
+	FindByHotel(hotelID string) ([]domain.Photo, error)
+	FindByRoom(roomID string) ([]domain.Photo, error)
+	UpdatePositions(positions map[string]int) error
+	SetCover(photo *domain.Photo) error
+}
+
+type photoRepository struct {
+	DB *gorm.DB
+}
+
+func NewPhotoRepository(db *gorm.DB) PhotoRepository {
+	return &photoRepository{DB: db}
+}
+
+func (r *photoRepository) Create(photo *domain.Photo) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(photo).Error; err != nil {
+			return err
+		}
+
+		return touchPhotoOwners(tx, photo.ID.String())
+	})
+}
+
+func (r *photoRepository) Delete(id string) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := touchPhotoOwners(tx, id); err != nil {
+			return err
+		}
+
+		return tx.Delete(&domain.Photo{}, "id = ?", id).Error
+	})
+}
+
+func (r *photoRepository) FindByID(id string) (*domain.Photo, error) {
+	var photo domain.Photo
+	err := r.DB.First(&photo, "id = ?", id).Error
+
+	return &photo, err
+}
+
+// FindByHotel returns the hotel-level photos, excluding room photos.
+func (r *photoRepository) FindByHotel(hotelID string) ([]domain.Photo, error) {
+	var photos []domain.Photo
+	err := r.DB.Where("hotel_id = ? AND room_id IS NULL", hotelID).
+		Order("position, created_at").Find(&photos).Error
+
+	return photos, err
+}
+
+func (r *photoRepository) FindByRoom(roomID string) ([]domain.Photo, error) {
+	var photos []domain.Photo
+	err := r.DB.Where("room_id = ?", roomID).Order("position, created_at").Find(&photos).Error
+
+	return photos, err
+}
+
+func (r *photoRepository) UpdatePositions(positions map[string]int) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		ids := make([]string, 0, len(positions))
+		for id, position := range positions {
+			if err := tx.Model(&domain.Photo{}).Where("id = ?", id).Update("position", position).Error; err != nil {
+				return err
+			}
+			ids = append(ids, id)
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		return touchPhotoOwners(tx, ids...)
+	})
+}
+
+// SetCover makes photo the only cover among the photos of the same hotel or
+// room.
+func (r *photoRepository) SetCover(photo *domain.Photo) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		siblings := tx.Model(&domain.Photo{}).Where("hotel_id = ?", photo.HotelID)
+		if photo.RoomID == nil {
+			siblings = siblings.Where("room_id IS NULL")
+		} else {
+			siblings = siblings.Where("room_id = ?", *photo.RoomID)
+		}
+
+		if err := siblings.Update("is_cover", false).Error; err != nil {
+			return err
+		}
+
+		photo.IsCover = true
+		if err := tx.Model(photo).Update("is_cover", true).Error; err != nil {
+			return err
+		}
+
+		return touchPhotoOwners(tx, photo.ID.String())
+	})
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"hotel-booking-api/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type RecoveryCodeRepository interface {
+	Replace(userID uuid.UUID, hashes []string) error
+	Consume(userID, hash string) (bool, error)
+	DeleteAll(userID string) error
+}
+
+type recoveryCodeRepository struct {
+	DB *gorm.DB
+}
+
+func NewRecoveryCodeRepository(db *gorm.DB) RecoveryCodeRepository {
+	return &recoveryCodeRepository{DB: db}
+}
+
+// Replace swaps the user's recovery codes for a new set.
+func (r *recoveryCodeRepository) Replace(userID uuid.UUID, hashes []string) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&domain.RecoveryCode{}, "user_id = ?", userID).Error; err != nil {
+			return err
+		}
+
+		codes := make([]domain.RecoveryCode, len(hashes))
+		for i, hash := range hashes {
+			codes[i] = domain.RecoveryCode{UserID: userID, CodeHash: hash}
+		}
+
+		return tx.Omit("User").Create(&codes).Error
+	})
+}
+
+// Consume marks an unused code as used, reporting whether one matched.
+func (r *recoveryCodeRepository) Consume(userID, hash string) (bool, error) {
+	result := r.DB.Model(&domain.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hash).
+		Update("used_at", time.Now())
+
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *recoveryCodeRepository) DeleteAll(userID string) error {
+	return r.DB.Delete(&domain.RecoveryCode{}, "user_id = ?", userID).Error
+}
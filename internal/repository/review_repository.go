@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"hotel-booking-api/internal/domain"
+	"hotel-booking-api/pkg/pagination"
+
+	"github.com/google/uuid"
+
+	"gorm.io/gorm"
+)
+
+var reviewSortFields = map[string]string{
+	"created_at": "created_at",
+	"rating":     "rating",
+}
+
+// HotelReviewListOptions applies to a hotel's public reviews, which are
+// always the visible ones.
+var HotelReviewListOptions = pagination.Options{
+	SortFields:   reviewSortFields,
+	DefaultSort:  "created_at",
+	DefaultOrder: "desc",
+	Filters: map[string]pagination.Filter{
+		"min_rating": {Column: "rating", Op: pagination.OpGte},
+		"max_rating": {Column: "rating", Op: pagination.OpLte},
+	},
+}
+
+// ReviewListOptions applies to the moderation list across all hotels.
+var ReviewListOptions = pagination.Options{
+	SortFields:   reviewSortFields,
+	DefaultSort:  "created_at",
+	DefaultOrder: "desc",
+	Filters: map[string]pagination.Filter{
+		"status":     {Column: "status", Op: pagination.OpEqual},
+		"hotel_id":   {Column: "hotel_id", Op: pagination.OpEqual},
+		"min_rating": {Column: "rating", Op: pagination.OpGte},
+		"max_rating": {Column: "rating", Op: pagination.OpLte},
+	},
+}
+
+type ReviewRepository interface {
+	Create(review *domain.Review) error
+	Update(review *domain.Review) error
+	FindByID(id string) (*domain.Review, error)
+	ExistsForBooking(bookingID string) (bool, error)
+	ListVisibleByHotel(hotelID string, query pagination.Query) ([]domain.Review, int64, error)
+	List(query pagination.Query) ([]domain.Review, int64, error)
+}
+
+type reviewRepository struct {
+	DB *gorm.DB
+}
+
+func NewReviewRepository(db *gorm.DB) ReviewRepository {
+	return &reviewRepository{DB: db}
+}
+
+func (r *reviewRepository) Create(review *domain.Review) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(review).Error; err != nil {
+			return err
+		}
+
+		return refreshHotelRating(tx, review.HotelID)
+	})
+}
+
+// Update saves the review and refreshes the hotel rating, since moderation
+// can move a review in or out of the visible set.
+func (r *reviewRepository) Update(review *domain.Review) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Booking", "Hotel", "User").Save(review).Error; err != nil {
+			return err
+		}
+
+		return refreshHotelRating(tx, review.HotelID)
+	})
+}
+
+func (r *reviewRepository) FindByID(id string) (*domain.Review, error) {
+	var review domain.Review
+	err := r.DB.Preload("User").First(&review, "id = ?", id).Error
+
+	return &review, err
+}
+
+func (r *reviewRepository) ExistsForBooking(bookingID string) (bool, error) {
+	var count int64
+	err := r.DB.Model(&domain.Review{}).Where("booking_id = ?", bookingID).Count(&count).Error
+
+	return count > 0, err
+}
+
+func (r *reviewRepository) ListVisibleByHotel(hotelID string, query pagination.Query) ([]domain.Review, int64, error) {
+	db := r.DB.Model(&domain.Review{}).Where("hotel_id = ? AND status = ?", hotelID, domain.ReviewStatusVisible)
+
+	return r.list(db, query, HotelReviewListOptions)
+}
+
+func (r *reviewRepository) List(query pagination.Query) ([]domain.Review, int64, error) {
+	return r.list(r.DB.Model(&domain.Review{}), query, ReviewListOptions)
+}
+
+func (r *reviewRepository) list(db *gorm.DB, query pagination.Query, opts pagination.Options) ([]domain.Review, int64, error) {
+	db = query.Filter(db, opts)
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var reviews []domain.Review
+	err := query.Paginate(db, opts, "id").Preload("User").Find(&reviews).Error
+
+	return reviews, total, err
+}
+
+// refreshHotelRating recomputes the hotel's average rating and review count
+// from its visible reviews.
+func refreshHotelRating(tx *gorm.DB, hotelID uuid.UUID) error {
+	visible := tx.Model(&domain.Review{}).Where("hotel_id = ? AND status = ?", hotelID, domain.ReviewStatusVisible)
+
+	return tx.Model(&domain.Hotel{}).Where("id = ?", hotelID).Updates(map[string]any{
+		"average_rating": visible.Session(&gorm.Session{}).Select("COALESCE(ROUND(AVG(rating)::numeric, 2), 0)"),
+		"review_count":   visible.Session(&gorm.Session{}).Select("COUNT(*)"),
+		"version":        bumpVersion,
+	}).Error
+}
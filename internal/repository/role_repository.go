@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"hotel-booking-api/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+type RoleRepository interface {
+	ListPermissions() ([]domain.Permission, error)
+	FindPermissionsByCodes(codes []string) ([]domain.Permission, error)
+	ListRoles() ([]domain.Role, error)
+	FindRoleByID(id string) (*domain.Role, error)
+	FindRolesByIDs(ids []string) ([]domain.Role, error)
+	CreateRole(role *domain.Role, audit *domain.AuditLog) error
+	UpdateRole(role *domain.Role, permissions []domain.Permission, audit *domain.AuditLog) error
+	DeleteRole(id string, audit *domain.AuditLog) error
+	FindUserRoles(userID string) ([]domain.Role, error)
+	ReplaceUserRoles(user *domain.User, roles []domain.Role, audit *domain.AuditLog) error
+	ResolvePermissions(userID, systemRole string) ([]string, error)
+}
+
+type roleRepository struct {
+	DB *gorm.DB
+}
+
+func NewRoleRepository(db *gorm.DB) RoleRepository {
+	return &roleRepository{DB: db}
+}
+
+func (r *roleRepository) ListPermissions() ([]domain.Permission, error) {
+	var permissions []domain.Permission
+	err := r.DB.Order("code").Find(&permissions).Error
+
+	return permissions, err
+}
+
+func (r *roleRepository) FindPermissionsByCodes(codes []string) ([]domain.Permission, error) {
+	var permissions []domain.Permission
+	if len(codes) == 0 {
+		return permissions, nil
+	}
+
+	err := r.DB.Where("code IN ?", codes).Find(&permissions).Error
+	return permissions, err
+}
+
+func (r *roleRepository) ListRoles() ([]domain.Role, error) {
+	var roles []domain.Role
+	err := r.DB.Preload("Permissions").Order("is_system DESC, name").Find(&roles).Error
+
+	return roles, err
+}
+
+func (r *roleRepository) FindRoleByID(id string) (*domain.Role, error) {
+	var role domain.Role
+	err := r.DB.Preload("Permissions").First(&role, "id = ?", id).Error
+
+	return &role, err
+}
+
+func (r *roleRepository) FindRolesByIDs(ids []string) ([]domain.Role, error) {
+	var roles []domain.Role
+	if len(ids) == 0 {
+		return roles, nil
+	}
+
+	err := r.DB.Preload("Permissions").Where("id IN ?", ids).Find(&roles).Error
+	return roles, err
+}
+
+func (r *roleRepository) CreateRole(role *domain.Role, audit *domain.AuditLog) error {
+	return withAudit(r.DB, audit, func(tx *gorm.DB) error {
+		return tx.Create(role).Error
+	})
+}
+
+func (r *roleRepository) UpdateRole(role *domain.Role, permissions []domain.Permission, audit *domain.AuditLog) error {
+	return withAudit(r.DB, audit, func(tx *gorm.DB) error {
+		if err := tx.Omit("Permissions").Save(role).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(role).Association("Permissions").Replace(permissions); err != nil {
+			return err
+		}
+
+		role.Permissions = permissions
+		return nil
+	})
+}
+
+func (r *roleRepository) DeleteRole(id string, audit *domain.AuditLog) error {
+	return withAudit(r.DB, audit, func(tx *gorm.DB) error {
+		return tx.Delete(&domain.Role{}, "id = ? AND NOT is_system", id).Error
+	})
+}
+
+func (r *roleRepository) FindUserRoles(userID string) ([]domain.Role, error) {
+	var user domain.User
+	err := r.DB.Select("id").Preload("Roles.Permissions").First(&user, "id = ?", userID).Error
+
+	return user.Roles, err
+}
+
+func (r *roleRepository) ReplaceUserRoles(user *domain.User, roles []domain.Role, audit *domain.AuditLog) error {
+	return withAudit(r.DB, audit, func(tx *gorm.DB) error {
+		// Only the links change; the roles' own permissions are left alone.
+		assigned := make([]domain.Role, len(roles))
+		for i, role := range roles {
+			role.Permissions = nil
+			assigned[i] = role
+		}
+
+		return tx.Model(user).Association("Roles").Replace(assigned)
+	})
+}
+
+// ResolvePermissions returns the codes granted by the system role named
+// systemRole together with every custom role assigned to the user.
+func (r *roleRepository) ResolvePermissions(userID, systemRole string) ([]string, error) {
+	var codes []string
+	err := r.DB.Table("permissions").
+		Distinct("permissions.code").
+		Joins("JOIN role_permissions ON role_permissions.permission_id = permissions.id").
+		Joins("JOIN roles ON roles.id = role_permissions.role_id").
+		Where("(roles.is_system AND roles.name = ?) OR roles.id IN (?)", systemRole,
+			r.DB.Table("user_roles").Select("role_id").Where("user_id = ?", userID)).
+		Pluck("permissions.code", &codes).Error
+
+	return codes, err
+}
@@ -2,16 +2,41 @@ package repository
 
 import (
 	"hotel-booking-api/internal/domain"
+	"hotel-booking-api/pkg/pagination"
 
 	"gorm.io/gorm"
 )
 
+var RoomListOptions = pagination.Options{
+	SortFields: map[string]string{
+		"price_per_night": "price_per_night",
+		"room_type":       "room_type",
+		"availability":    "availability",
+		"created_at":      "created_at",
+	},
+	DefaultSort:  "price_per_night",
+	DefaultOrder: "asc",
+	Filters: map[string]pagination.Filter{
+		"room_type": {Column: "room_type", Op: pagination.OpILike},
+		"min_price": {Column: "price_per_night", Op: pagination.OpGte},
+		"max_price": {Column: "price_per_night", Op: pagination.OpLte},
+	},
+}
+
 type RoomRepository interface {
-	Create(room *domain.Room) error
-	Update(room *domain.Room) error
+	Create(room *domain.Room, audit *domain.AuditLog) error
+	Update(room *domain.Room, audit *domain.AuditLog) error
 	FindByHotel(hotelID string) ([]domain.Room, error)
+	ListByHotel(hotelID string, amenities []string, query pagination.Query) ([]domain.Room, int64, error)
 	FindByID(id string) (*domain.Room, error)
+	FindByIDWithDeleted(id string) (*domain.Room, error)
+	FindAvailableByHotel(hotelID string, checkIn, checkOut string) ([]domain.Room, error)
 	UpdateAvailability(id string, availability int) error
+	Delete(id string, audit *domain.AuditLog) error
+	Restore(id string, audit *domain.AuditLog) error
+	Purge(id string, audit *domain.AuditLog) error
+	// WithTx returns a repository that runs its queries in tx.
+	WithTx(tx *gorm.DB) RoomRepository
 }
 
 type roomRepository struct {
@@ -22,12 +47,28 @@ func NewRoomRepository(db *gorm.DB) RoomRepository {
 	return &roomRepository{DB: db}
 }
 
-func (r *roomRepository) Create(room *domain.Room) error {
-	return r.DB.Create(room).Error
+func (r *roomRepository) WithTx(tx *gorm.DB) RoomRepository {
+	return &roomRepository{DB: tx}
+}
+
+func (r *roomRepository) Create(room *domain.Room, audit *domain.AuditLog) error {
+	return withAudit(r.DB, audit, func(tx *gorm.DB) error {
+		if err := tx.Create(room).Error; err != nil {
+			return err
+		}
+
+		return touchHotel(tx, room.HotelID)
+	})
 }
 
-func (r *roomRepository) Update(room *domain.Room) error {
-	return r.DB.Save(room).Error
+func (r *roomRepository) Update(room *domain.Room, audit *domain.AuditLog) error {
+	return withAudit(r.DB, audit, func(tx *gorm.DB) error {
+		if err := saveVersioned(tx, room, &room.Version); err != nil {
+			return err
+		}
+
+		return touchHotel(tx, room.HotelID)
+	})
 }
 
 func (r *roomRepository) FindByHotel(hotelID string) ([]domain.Room, error) {
@@ -37,13 +78,95 @@ func (r *roomRepository) FindByHotel(hotelID string) ([]domain.Room, error) {
 	return rooms, err
 }
 
+func (r *roomRepository) ListByHotel(hotelID string, amenities []string, query pagination.Query) ([]domain.Room, int64, error) {
+	db := query.Filter(r.DB.Model(&domain.Room{}).Where("hotel_id = ?", hotelID), RoomListOptions)
+	if len(amenities) > 0 {
+		db = db.Where("rooms.id IN (?)", roomsWithAmenities(r.DB, amenities))
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var rooms []domain.Room
+	err := query.Paginate(db, RoomListOptions, "id").
+		Preload("Amenities").Preload("Photos", orderedPhotos).Find(&rooms).Error
+
+	return rooms, total, err
+}
+
 func (r *roomRepository) FindByID(id string) (*domain.Room, error) {
 	var room domain.Room
-	err := r.DB.First(&room, "id = ?", id).Error
+	err := r.DB.Preload("Amenities").Preload("Photos", orderedPhotos).First(&room, "id = ?", id).Error
+
+	return &room, err
+}
+
+// FindByIDWithDeleted finds a room whether or not it is deleted.
+func (r *roomRepository) FindByIDWithDeleted(id string) (*domain.Room, error) {
+	var room domain.Room
+	err := r.DB.Unscoped().First(&room, "id = ?", id).Error
 
 	return &room, err
 }
 
+func (r *roomRepository) FindAvailableByHotel(hotelID string, checkIn, checkOut string) ([]domain.Room, error) {
+	var rooms []domain.Room
+	err := r.DB.Where("hotel_id = ? AND availability > 0", hotelID).
+		Where(`NOT EXISTS (
+			SELECT 1 FROM bookings
+			WHERE bookings.room_id = rooms.id
+			AND bookings.status IN ?
+			AND bookings.check_in < ? AND bookings.check_out > ?)`,
+			[]string{domain.BookingStatusPending, domain.BookingStatusConfirmed}, checkOut, checkIn).
+		Order("price_per_night").Find(&rooms).Error
+
+	return rooms, err
+}
+
 func (r *roomRepository) UpdateAvailability(id string, availability int) error {
-	return r.DB.Model(&domain.Room{}).Where("id = ?", id).Update("availability", availability).Error
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&domain.Room{}).Where("id = ?", id).Updates(map[string]any{
+			"availability": availability,
+			"version":      bumpVersion,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		return touchHotelOfRoom(tx, id)
+	})
+}
+
+func (r *roomRepository) Delete(id string, audit *domain.AuditLog) error {
+	return withAudit(r.DB, audit, func(tx *gorm.DB) error {
+		if err := tx.Delete(&domain.Room{}, "id = ?", id).Error; err != nil {
+			return err
+		}
+
+		return touchHotelOfRoom(tx, id)
+	})
+}
+
+func (r *roomRepository) Restore(id string, audit *domain.AuditLog) error {
+	return withAudit(r.DB, audit, func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&domain.Room{}).Where("id = ?", id).Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+
+		return touchHotelOfRoom(tx, id)
+	})
+}
+
+// Purge permanently deletes the room. Bookings restrict the delete, so a room
+// with booking history cannot be purged.
+func (r *roomRepository) Purge(id string, audit *domain.AuditLog) error {
+	return withAudit(r.DB, audit, func(tx *gorm.DB) error {
+		if err := touchHotelOfRoom(tx, id); err != nil {
+			return err
+		}
+
+		return tx.Unscoped().Delete(&domain.Room{}, "id = ?", id).Error
+	})
 }
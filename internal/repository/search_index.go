@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// MigrateSearchIndexes adds the hotel search indexes that GORM tags cannot
+// express. It runs after the tables are migrated. With trigram set it also
+// creates the pg_trgm extension and the trigram indexes that typo-tolerant
+// matching uses; the hotel repository must be built with the same setting.
+func MigrateSearchIndexes(db *gorm.DB, trigram bool) error {
+	statements := []string{
+		// Superseded by the v2 document, which also covers amenities.
+		"DROP INDEX IF EXISTS idx_hotels_search_document",
+		"CREATE INDEX IF NOT EXISTS idx_hotels_search_document_v2 ON hotels USING GIN (" + hotelSearchDocumentSQL + ")",
+	}
+	if trigram {
+		statements = append(statements,
+			"CREATE EXTENSION IF NOT EXISTS pg_trgm",
+			"CREATE INDEX IF NOT EXISTS idx_hotels_name_trgm ON hotels USING GIN (name gin_trgm_ops)",
+			"CREATE INDEX IF NOT EXISTS idx_hotels_location_trgm ON hotels USING GIN (location gin_trgm_ops)",
+			"CREATE INDEX IF NOT EXISTS idx_hotels_city_trgm ON hotels USING GIN (city gin_trgm_ops)",
+		)
+	}
+
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			return fmt.Errorf("failed to create search indexes: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"hotel-booking-api/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SessionRepository interface {
+	Create(token *domain.RefreshToken) error
+	FindByHash(hash string) (*domain.RefreshToken, error)
+	Rotate(current, next *domain.RefreshToken) error
+	RevokeFamily(familyID uuid.UUID) error
+	RevokeUser(userID string) error
+	RevokeAccessToken(token *domain.RevokedToken) error
+	IsAccessTokenRevoked(jti string) (bool, error)
+}
+
+type sessionRepository struct {
+	DB *gorm.DB
+}
+
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &sessionRepository{DB: db}
+}
+
+func (r *sessionRepository) Create(token *domain.RefreshToken) error {
+	return r.DB.Omit("User").Create(token).Error
+}
+
+func (r *sessionRepository) FindByHash(hash string) (*domain.RefreshToken, error) {
+	var token domain.RefreshToken
+	if err := r.DB.Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// Rotate marks current as used and stores next in its place. It returns
+// gorm.ErrRecordNotFound when current was rotated or revoked concurrently.
+func (r *sessionRepository) Rotate(current, next *domain.RefreshToken) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.RefreshToken{}).
+			Where("id = ? AND rotated_at IS NULL AND revoked_at IS NULL", current.ID).
+			Update("rotated_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return tx.Omit("User").Create(next).Error
+	})
+}
+
+// RevokeFamily revokes every refresh token issued from one login together
+// with the access tokens issued alongside them.
+func (r *sessionRepository) RevokeFamily(familyID uuid.UUID) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		return revokeSessions(tx, "family_id = ?", familyID)
+	})
+}
+
+// RevokeUser signs the user out of every session.
+func (r *sessionRepository) RevokeUser(userID string) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		return revokeSessions(tx, "user_id = ?", userID)
+	})
+}
+
+func (r *sessionRepository) RevokeAccessToken(token *domain.RevokedToken) error {
+	return r.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(token).Error
+}
+
+func (r *sessionRepository) IsAccessTokenRevoked(jti string) (bool, error) {
+	var count int64
+	err := r.DB.Model(&domain.RevokedToken{}).Where("jti = ?", jti).Count(&count).Error
+
+	return count > 0, err
+}
+
+// revokeSessions deny-lists the unexpired access tokens of the matching
+// refresh tokens, revokes the refresh tokens and drops deny-list entries that
+// have expired on their own.
+func revokeSessions(tx *gorm.DB, query string, args ...any) error {
+	now := time.Now()
+
+	err := tx.Exec(`
+		INSERT INTO revoked_tokens (jti, user_id, expires_at, created_at)
+		SELECT access_jti, user_id, access_expires_at, ?
+		FROM refresh_tokens
+		WHERE `+query+` AND access_expires_at > ?
+		ON CONFLICT (jti) DO NOTHING`,
+		append(append([]any{now}, args...), now)...,
+	).Error
+	if err != nil {
+		return err
+	}
+
+	err = tx.Model(&domain.RefreshToken{}).
+		Where(query, args...).
+		Where("revoked_at IS NULL").
+		Update("revoked_at", now).Error
+	if err != nil {
+		return err
+	}
+
+	return tx.Where("expires_at <= ?", now).Delete(&domain.RevokedToken{}).Error
+}
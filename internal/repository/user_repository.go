@@ -1,15 +1,62 @@
 package repository
 
 import (
+	"fmt"
 	"hotel-booking-api/internal/domain"
+	"hotel-booking-api/pkg/pagination"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
 )
 
+// UserFilter narrows an admin user search. Zero values are ignored.
+type UserFilter struct {
+	Search string
+	Status string
+	Query  pagination.Query
+}
+
+// User statuses accepted by UserFilter.Status.
+const (
+	UserStatusActive      = "active"
+	UserStatusDeactivated = "deactivated"
+)
+
+var UserListOptions = pagination.Options{
+	SortFields: map[string]string{
+		"created_at": "users.created_at",
+		"name":       "users.name",
+		"email":      "users.email",
+		"role":       "users.role",
+	},
+	DefaultSort:  "created_at",
+	DefaultOrder: "desc",
+	Filters: map[string]pagination.Filter{
+		"role": {Column: "users.role", Op: pagination.OpEqual},
+	},
+}
+
 type UserRepository interface {
-	Create(user *domain.User) error
+	Create(user *domain.User, audit *domain.AuditLog) error
 	FindByEmail(email string) (*domain.User, error)
 	FindByID(id string) (*domain.User, error)
+	MarkEmailVerified(id string) error
+	UpdatePassword(id, hash string) error
+	SetTOTPSecret(id, secret string) error
+	EnableTOTP(id string, step int64) error
+	DisableTOTP(id string) error
+	AdvanceTOTPStep(id string, step int64) (bool, error)
+	List(filter UserFilter) ([]domain.User, int64, error)
+	CountActiveAdmins() (int64, error)
+	UpdateRole(id, role string, audit *domain.AuditLog) error
+	SetDeactivated(id string, at *time.Time, audit *domain.AuditLog) error
+	UpdateProfile(user *domain.User) error
+	SetPendingEmail(id, email string) error
+	ChangeEmail(id, email string) error
+	Anonymize(id string, at time.Time) error
+	// WithTx returns a repository that runs its queries in tx.
+	WithTx(tx *gorm.DB) UserRepository
 }
 
 type userRepository struct {
@@ -20,8 +67,14 @@ func NewUserRepository(db *gorm.DB) UserRepository {
 	return &userRepository{DB: db}
 }
 
-func (r *userRepository) Create(user *domain.User) error {
-	return r.DB.Create(user).Error
+func (r *userRepository) WithTx(tx *gorm.DB) UserRepository {
+	return &userRepository{DB: tx}
+}
+
+func (r *userRepository) Create(user *domain.User, audit *domain.AuditLog) error {
+	return withAudit(r.DB, audit, func(tx *gorm.DB) error {
+		return tx.Create(user).Error
+	})
 }
 
 func (r *userRepository) FindByEmail(email string) (*domain.User, error) {
@@ -41,3 +94,205 @@ func (r *userRepository) FindByID(id string) (*domain.User, error) {
 
 	return &user, nil
 }
+
+func (r *userRepository) MarkEmailVerified(id string) error {
+	return r.DB.Model(&domain.User{}).
+		Where("id = ? AND email_verified_at IS NULL", id).
+		Update("email_verified_at", time.Now()).Error
+}
+
+func (r *userRepository) UpdatePassword(id, hash string) error {
+	return r.DB.Model(&domain.User{}).Where("id = ?", id).Update("password", hash).Error
+}
+
+// SetTOTPSecret stores a pending secret for an account that has not enabled
+// two-factor authentication yet.
+func (r *userRepository) SetTOTPSecret(id, secret string) error {
+	return r.DB.Model(&domain.User{}).
+		Where("id = ? AND totp_enabled_at IS NULL", id).
+		Update("totp_secret", secret).Error
+}
+
+func (r *userRepository) EnableTOTP(id string, step int64) error {
+	return r.DB.Model(&domain.User{}).Where("id = ?", id).Updates(map[string]any{
+		"totp_enabled_at": time.Now(),
+		"totp_last_step":  step,
+	}).Error
+}
+
+func (r *userRepository) DisableTOTP(id string) error {
+	return r.DB.Model(&domain.User{}).Where("id = ?", id).Updates(map[string]any{
+		"totp_secret":     "",
+		"totp_enabled_at": nil,
+		"totp_last_step":  0,
+	}).Error
+}
+
+// AdvanceTOTPStep records step as the last accepted TOTP step. It reports
+// false when that step or a later one was already used, so a code cannot be
+// replayed.
+func (r *userRepository) AdvanceTOTPStep(id string, step int64) (bool, error) {
+	result := r.DB.Model(&domain.User{}).
+		Where("id = ? AND totp_last_step < ?", id, step).
+		Update("totp_last_step", step)
+
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *userRepository) List(filter UserFilter) ([]domain.User, int64, error) {
+	query := r.DB.Model(&domain.User{})
+	if filter.Search != "" {
+		pattern := "%" + pagination.EscapeLike(filter.Search) + "%"
+		query = query.Where("users.name ILIKE ? OR users.email ILIKE ?", pattern, pattern)
+	}
+	switch filter.Status {
+	case UserStatusActive:
+		query = query.Where("users.deactivated_at IS NULL")
+	case UserStatusDeactivated:
+		query = query.Where("users.deactivated_at IS NOT NULL")
+	}
+	query = filter.Query.Filter(query, UserListOptions)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users []domain.User
+	err := filter.Query.Paginate(query, UserListOptions, "users.id").Find(&users).Error
+
+	return users, total, err
+}
+
+func (r *userRepository) CountActiveAdmins() (int64, error) {
+	var count int64
+	err := r.DB.Model(&domain.User{}).
+		Where("role = ? AND deactivated_at IS NULL", domain.RoleAdmin).
+		Count(&count).Error
+
+	return count, err
+}
+
+// UpdateRole sets the user's account role. Demoting to CUSTOMER restores the
+// role implied by the user's hotel assignments, if any.
+func (r *userRepository) UpdateRole(id, role string, audit *domain.AuditLog) error {
+	return withAudit(r.DB, audit, func(tx *gorm.DB) error {
+		var user domain.User
+		if err := tx.Select("id").First(&user, "id = ?", id).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&user).Update("role", role).Error; err != nil {
+			return err
+		}
+		if role == domain.RoleCustomer {
+			return syncStaffRole(tx, user.ID)
+		}
+
+		return nil
+	})
+}
+
+// SetDeactivated deactivates the user at the given time, or reactivates them
+// when at is nil.
+func (r *userRepository) SetDeactivated(id string, at *time.Time, audit *domain.AuditLog) error {
+	return withAudit(r.DB, audit, func(tx *gorm.DB) error {
+		return tx.Model(&domain.User{}).Where("id = ?", id).Update("deactivated_at", at).Error
+	})
+}
+
+// UpdateProfile saves the fields a user may edit about themselves.
+func (r *userRepository) UpdateProfile(user *domain.User) error {
+	return r.DB.Model(user).
+		Select("name", "phone", "preferred_currency", "preferred_language").
+		Updates(user).Error
+}
+
+// SetPendingEmail records the address the user asked to move to until they
+// confirm it.
+func (r *userRepository) SetPendingEmail(id, email string) error {
+	return r.DB.Model(&domain.User{}).Where("id = ?", id).Update("pending_email", email).Error
+}
+
+// ChangeEmail makes the user's pending address their email, verified. It
+// returns gorm.ErrRecordNotFound when email is no longer the pending address.
+func (r *userRepository) ChangeEmail(id, email string) error {
+	result := r.DB.Model(&domain.User{}).
+		Where("id = ? AND pending_email = ?", id, email).
+		Updates(map[string]any{
+			"email":             email,
+			"pending_email":     "",
+			"email_verified_at": time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// Anonymize erases the personal data of a closed account while keeping the
+// row, so its bookings, payments and reviews stay intact for the hotels'
+// records. The account can no longer sign in: its credentials, sessions,
+// tokens, roles and hotel assignments are removed in the same transaction,
+// and its data exports are expired.
+func (r *userRepository) Anonymize(id string, at time.Time) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		var user domain.User
+		if err := tx.First(&user, "id = ?", id).Error; err != nil {
+			return err
+		}
+
+		err := tx.Model(&user).Updates(map[string]any{
+			"name":               "Deleted user",
+			"email":              fmt.Sprintf("deleted-%s@users.invalid", user.ID),
+			"pending_email":      "",
+			"password":           "",
+			"role":               domain.RoleCustomer,
+			"phone":              "",
+			"preferred_currency": "",
+			"preferred_language": "",
+			"email_verified_at":  nil,
+			"totp_secret":        "",
+			"totp_enabled_at":    nil,
+			"totp_last_step":     0,
+			"deactivated_at":     at,
+			"anonymized_at":      at,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Model(&user).Association("Roles").Clear(); err != nil {
+			return err
+		}
+		for _, model := range []any{&domain.HotelStaff{}, &domain.UserToken{}, &domain.RecoveryCode{}} {
+			if err := tx.Where("user_id = ?", user.ID).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Delete(&domain.LoginAttempt{}, "subject = ?", "account:"+strings.ToLower(user.Email)).Error; err != nil {
+			return err
+		}
+
+		// Archives already built are purged by the export worker; ones still
+		// queued are never built.
+		err = tx.Model(&domain.DataExport{}).
+			Where("user_id = ? AND status = ?", user.ID, domain.ExportStatusReady).
+			Update("expires_at", at).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&domain.DataExport{}).
+			Where("user_id = ? AND status IN ?", user.ID, []string{domain.ExportStatusPending, domain.ExportStatusProcessing}).
+			Updates(map[string]any{"status": domain.ExportStatusFailed, "error": "account deleted"}).Error
+		if err != nil {
+			return err
+		}
+
+		return revokeSessions(tx, "user_id = ?", user.ID)
+	})
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"hotel-booking-api/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type UserTokenRepository interface {
+	Create(token *domain.UserToken) error
+	Consume(hash, purpose string) (*domain.UserToken, error)
+}
+
+type userTokenRepository struct {
+	DB *gorm.DB
+}
+
+func NewUserTokenRepository(db *gorm.DB) UserTokenRepository {
+	return &userTokenRepository{DB: db}
+}
+
+// Create stores token and invalidates any earlier unused token the user holds
+// for the same purpose, so only the most recent email works.
+func (r *userTokenRepository) Create(token *domain.UserToken) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&domain.UserToken{}).
+			Where("user_id = ? AND purpose = ? AND used_at IS NULL", token.UserID, token.Purpose).
+			Update("used_at", time.Now()).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Omit("User").Create(token).Error
+	})
+}
+
+// Consume marks an unused, unexpired token as used and returns it. It returns
+// gorm.ErrRecordNotFound for unknown, used or expired tokens.
+func (r *userTokenRepository) Consume(hash, purpose string) (*domain.UserToken, error) {
+	var tokens []domain.UserToken
+	now := time.Now()
+
+	result := r.DB.Model(&tokens).
+		Clauses(clause.Returning{}).
+		Where("token_hash = ? AND purpose = ? AND used_at IS NULL AND expires_at > ?", hash, purpose, now).
+		Update("used_at", now)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if len(tokens) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return &tokens[0], nil
+}
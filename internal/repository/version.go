@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"errors"
+	"hotel-booking-api/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// ErrVersionConflict means a row was changed by someone else after it was
+// read, so saving it would overwrite their change.
+var ErrVersionConflict = errors.New("the record was changed by someone else")
+
+// saveVersioned saves a row with a version column only if it is still at the
+// version it was read at, and moves version on by one. On failure version is
+// left as it was.
+func saveVersioned(tx *gorm.DB, value any, version *int) error {
+	read := *version
+	*version = read + 1
+
+	// Selecting every column keeps Save from falling back to an insert when
+	// the version check matches no row.
+	result := tx.Select("*").Where("version = ?", read).Save(value)
+	if result.Error == nil && result.RowsAffected == 0 {
+		result.Error = ErrVersionConflict
+	}
+	if result.Error != nil {
+		*version = read
+	}
+
+	return result.Error
+}
+
+// bumpVersion moves a row's version on for updates of single columns, so
+// clients holding the old ETag see the change.
+var bumpVersion = gorm.Expr("version + 1")
+
+// A hotel's representation embeds its rooms, their amenities and the photos
+// of both, and a room's embeds its amenities and photos. Changes to those
+// rows move the parent's version on too, so its ETag changes with what a
+// client would see.
+
+func touchHotel(tx *gorm.DB, hotelID any) error {
+	return tx.Model(&domain.Hotel{}).Where("id = ?", hotelID).UpdateColumn("version", bumpVersion).Error
+}
+
+func touchHotelOfRoom(tx *gorm.DB, roomID any) error {
+	return tx.Model(&domain.Hotel{}).Where("id = (SELECT hotel_id FROM rooms WHERE id = ?)", roomID).
+		UpdateColumn("version", bumpVersion).Error
+}
+
+func touchRoom(tx *gorm.DB, room *domain.Room) error {
+	if err := tx.Model(&domain.Room{}).Where("id = ?", room.ID).UpdateColumn("version", bumpVersion).Error; err != nil {
+		return err
+	}
+
+	return touchHotel(tx, room.HotelID)
+}
+
+// touchPhotoOwners moves on the hotels and rooms showing any of photoIDs. It
+// must run before the photos are deleted.
+func touchPhotoOwners(tx *gorm.DB, photoIDs ...string) error {
+	if err := tx.Model(&domain.Room{}).Where("id IN (SELECT room_id FROM photos WHERE id IN ?)", photoIDs).
+		UpdateColumn("version", bumpVersion).Error; err != nil {
+		return err
+	}
+
+	return tx.Model(&domain.Hotel{}).Where("id IN (SELECT hotel_id FROM photos WHERE id IN ?)", photoIDs).
+		UpdateColumn("version", bumpVersion).Error
+}
@@ -1,31 +1,63 @@
 package router
 
 import (
+	"hotel-booking-api/internal/domain"
 	"hotel-booking-api/internal/handler"
+	"hotel-booking-api/internal/middleware"
 
 	"github.com/labstack/echo/v4"
 )
 
-func SetupAuthRoutes(api *echo.Group, handler *handler.AuthHandler) {
+func SetupAuthRoutes(api *echo.Group, handler *handler.AuthHandler, authMiddleware echo.MiddlewareFunc, perm middleware.RequirePermissionFunc, limit echo.MiddlewareFunc) {
 	auth := api.Group("/auth")
-	auth.POST("/register", handler.Register)
-	auth.POST("/login", handler.Login)
+	auth.POST("/register", handler.Register, limit)
+	auth.POST("/login", handler.Login, limit)
+	auth.POST("/refresh", handler.Refresh, limit)
+	auth.POST("/verify-email", handler.VerifyEmail)
+	auth.POST("/confirm-email", handler.ConfirmEmailChange)
+	auth.POST("/forgot-password", handler.ForgotPassword, limit)
+	auth.POST("/reset-password", handler.ResetPassword, limit)
+	auth.POST("/2fa/verify", handler.VerifyTwoFactor, limit)
+
+	// Protected routes
+	account := perm(domain.PermAccountManage)
+	auth.POST("/verify-email/resend", handler.ResendVerificationEmail, authMiddleware, account)
+	auth.POST("/2fa/disable", handler.DisableTwoFactor, authMiddleware, account)
+	auth.POST("/2fa/recovery-codes", handler.RegenerateRecoveryCodes, authMiddleware, account)
+
+	// Admins who must use two-factor authentication are refused by perm until
+	// they have it, so signing out and enrolling stay open to any user.
+	auth.POST("/logout", handler.Logout, authMiddleware)
+	auth.POST("/2fa/setup", handler.SetupTwoFactor, authMiddleware)
+	auth.POST("/2fa/enable", handler.EnableTwoFactor, authMiddleware)
+
+	api.POST("/admin/users/:id/unlock", handler.UnlockAccount, authMiddleware, perm(domain.PermUserManage))
+	api.DELETE("/admin/users/:id/2fa", handler.ResetTwoFactor, authMiddleware, perm(domain.PermUserManage))
 }
 
-func SetupHotelRoutes(api *echo.Group, handler *handler.HotelHandler, auth echo.MiddlewareFunc) {
+func SetupHotelRoutes(api *echo.Group, handler *handler.HotelHandler, auth echo.MiddlewareFunc, perm middleware.RequirePermissionFunc) {
 	hotels := api.Group("/hotels")
 
 	// Public routes
 	hotels.GET("", handler.ListHotels)
+	hotels.GET("/search", handler.SearchHotels)
+	hotels.GET("/autocomplete", handler.AutocompleteHotels)
 	hotels.GET("/:id", handler.GetHotel)
 
 	// Protected routes
-	hotels.POST("", handler.CreateHotel, auth)
-	hotels.PUT("/:id", handler.UpdateHotel, auth)
-	hotels.DELETE("/:id", handler.DeleteHotel, auth)
+	hotels.POST("", handler.CreateHotel, auth, perm(domain.PermHotelCreate))
+	hotels.PUT("/:id", handler.UpdateHotel, auth, perm(domain.PermHotelUpdate))
+	hotels.PATCH("/:id", handler.PatchHotel, auth, perm(domain.PermHotelUpdate))
+	hotels.DELETE("/:id", handler.DeleteHotel, auth, perm(domain.PermHotelDelete))
+
+	// Deleted hotels
+	admin := api.Group("/admin/hotels", auth, perm(domain.PermHotelDelete))
+	admin.GET("/deleted", handler.ListDeletedHotels)
+	admin.POST("/:id/restore", handler.RestoreHotel)
+	admin.DELETE("/:id", handler.PurgeHotel)
 }
 
-func SetupRoomRoutes(api *echo.Group, handler *handler.RoomHandler, auth echo.MiddlewareFunc) {
+func SetupRoomRoutes(api *echo.Group, handler *handler.RoomHandler, auth echo.MiddlewareFunc, perm middleware.RequirePermissionFunc) {
 	rooms := api.Group("/rooms")
 
 	// Public routes
@@ -33,20 +65,155 @@ func SetupRoomRoutes(api *echo.Group, handler *handler.RoomHandler, auth echo.Mi
 	rooms.GET("/:id", handler.GetRoom)
 
 	// Protected routes
-	rooms.POST("", handler.CreateRoom, auth)
-	rooms.PUT("/:id", handler.UpdateRoom, auth)
+	rooms.POST("", handler.CreateRoom, auth, perm(domain.PermRoomWrite))
+	rooms.PUT("/:id", handler.UpdateRoom, auth, perm(domain.PermRoomWrite))
+	rooms.PATCH("/:id", handler.PatchRoom, auth, perm(domain.PermRoomWrite))
+	rooms.DELETE("/:id", handler.DeleteRoom, auth, perm(domain.PermRoomWrite))
+
+	// Deleted rooms
+	admin := api.Group("/admin/rooms", auth, perm(domain.PermHotelDelete))
+	admin.POST("/:id/restore", handler.RestoreRoom)
+	admin.DELETE("/:id", handler.PurgeRoom)
 }
 
-func SetupBookingRoutes(api *echo.Group, handler *handler.BookingHandler, auth echo.MiddlewareFunc) {
-	bookings := api.Group("/bookings", auth)
+func SetupAmenityRoutes(api *echo.Group, handler *handler.AmenityHandler, auth echo.MiddlewareFunc, perm middleware.RequirePermissionFunc) {
+	amenities := api.Group("/amenities")
+
+	// Public routes
+	amenities.GET("", handler.ListAmenities)
+
+	// Protected routes
+	write := perm(domain.PermAmenityWrite)
+	amenities.POST("", handler.CreateAmenity, auth, write)
+	amenities.PUT("/:id", handler.UpdateAmenity, auth, write)
+	amenities.DELETE("/:id", handler.DeleteAmenity, auth, write)
+	api.PUT("/hotels/:id/amenities", handler.SetHotelAmenities, auth, write)
+	api.PUT("/rooms/:id/amenities", handler.SetRoomAmenities, auth, write)
+}
+
+func SetupPhotoRoutes(api *echo.Group, handler *handler.PhotoHandler, auth echo.MiddlewareFunc, perm middleware.RequirePermissionFunc) {
+	// Protected routes
+	write := perm(domain.PermPhotoWrite)
+	api.POST("/hotels/:id/photos", handler.UploadHotelPhoto, auth, write)
+	api.PUT("/hotels/:id/photos/order", handler.ReorderHotelPhotos, auth, write)
+	api.POST("/rooms/:id/photos", handler.UploadRoomPhoto, auth, write)
+	api.PUT("/rooms/:id/photos/order", handler.ReorderRoomPhotos, auth, write)
+	api.PATCH("/photos/:id/cover", handler.SetCoverPhoto, auth, write)
+	api.DELETE("/photos/:id", handler.DeletePhoto, auth, write)
+}
+
+func SetupReviewRoutes(api *echo.Group, handler *handler.ReviewHandler, auth echo.MiddlewareFunc, perm middleware.RequirePermissionFunc) {
+	// Public routes
+	api.GET("/hotels/:id/reviews", handler.ListHotelReviews)
+
+	// Protected routes
+	api.POST("/bookings/:id/review", handler.CreateReview, auth, middleware.RequireUser(), perm(domain.PermReviewWrite))
+	api.PUT("/reviews/:id/reply", handler.ReplyToReview, auth, perm(domain.PermReviewReply))
+
+	// Moderation routes
+	moderation := api.Group("/admin/reviews", auth, perm(domain.PermReviewModerate))
+	moderation.GET("", handler.ListReviews)
+	moderation.PATCH("/:id/moderation", handler.ModerateReview)
+}
+
+func SetupBookingRoutes(api *echo.Group, handler *handler.BookingHandler, auth echo.MiddlewareFunc, perm middleware.RequirePermissionFunc, verified, limit echo.MiddlewareFunc) {
+	bookings := api.Group("/bookings", auth, limit)
+
+	// Protected routes
+	bookings.POST("", handler.CreateBooking, middleware.RequireUser(), perm(domain.PermBookingCreate), verified)
+	bookings.GET("", handler.GetUserBookings, middleware.RequireUser(), perm(domain.PermBookingRead))
+	bookings.GET("/:id", handler.GetBooking, perm(domain.PermBookingRead, domain.PermBookingReadAny))
+	bookings.PATCH("/:id/cancel", handler.CancelBooking, perm(domain.PermBookingCancel, domain.PermBookingCancelAny))
+	bookings.PATCH("/:id/complete", handler.CompleteBooking, perm(domain.PermBookingComplete))
+	api.GET("/hotels/:id/bookings", handler.ListHotelBookings, auth, perm(domain.PermBookingRead, domain.PermBookingReadAny))
+
+	// Back-office routes
+	api.GET("/admin/bookings", handler.SearchBookings, auth, perm(domain.PermBookingReadAny))
+}
 
+func SetupStaffRoutes(api *echo.Group, handler *handler.StaffHandler, auth echo.MiddlewareFunc, perm middleware.RequirePermissionFunc) {
 	// Protected routes
-	bookings.POST("", handler.CreateBooking)
-	bookings.GET("", handler.GetUserBookings)
-	bookings.PATCH("/:id/cancel", handler.CancelBooking)
+	staff := api.Group("/admin/hotels/:id/staff", auth, perm(domain.PermStaffManage))
+	staff.GET("", handler.ListHotelStaff)
+	staff.PUT("/:userId", handler.AssignStaff)
+	staff.DELETE("/:userId", handler.RemoveStaff)
 }
 
-func SetupPaymentRoutes(api *echo.Group, handler *handler.PaymentHandler) {
+func SetupRoleRoutes(api *echo.Group, handler *handler.RoleHandler, auth echo.MiddlewareFunc, perm middleware.RequirePermissionFunc) {
+	// Protected routes
+	admin := api.Group("/admin", auth, perm(domain.PermRoleManage))
+	admin.GET("/permissions", handler.ListPermissions)
+	admin.GET("/roles", handler.ListRoles)
+	admin.POST("/roles", handler.CreateRole)
+	admin.PUT("/roles/:id", handler.UpdateRole)
+	admin.DELETE("/roles/:id", handler.DeleteRole)
+	admin.GET("/users/:id/roles", handler.GetUserAccess)
+	admin.PUT("/users/:id/roles", handler.SetUserRoles)
+}
+
+func SetupUserRoutes(api *echo.Group, handler *handler.UserHandler, auth echo.MiddlewareFunc, perm middleware.RequirePermissionFunc) {
+	// Protected routes
+	users := api.Group("/admin/users", auth, perm(domain.PermUserManage))
+	users.GET("", handler.ListUsers)
+	users.POST("", handler.CreateUser)
+	users.GET("/:id", handler.GetUser)
+	users.PATCH("/:id/role", handler.ChangeUserRole)
+	users.POST("/:id/deactivate", handler.DeactivateUser)
+	users.POST("/:id/reactivate", handler.ReactivateUser)
+}
+
+func SetupProfileRoutes(api *echo.Group, handler *handler.ProfileHandler, auth echo.MiddlewareFunc, perm middleware.RequirePermissionFunc) {
+	// Protected routes
+	me := api.Group("/users/me", auth, perm(domain.PermAccountManage))
+	me.GET("", handler.GetProfile)
+	me.PUT("", handler.UpdateProfile)
+	me.DELETE("", handler.DeleteAccount)
+	me.PUT("/password", handler.ChangePassword)
+	me.PUT("/email", handler.ChangeEmail)
+}
+
+func SetupDataExportRoutes(api *echo.Group, handler *handler.DataExportHandler, auth echo.MiddlewareFunc, perm middleware.RequirePermissionFunc) {
+	// Protected routes
+	me := api.Group("/users/me/exports", auth, perm(domain.PermAccountManage))
+	me.POST("", handler.RequestMyExport)
+	me.GET("", handler.ListMyExports)
+	me.GET("/:id", handler.GetMyExport)
+	me.GET("/:id/download", handler.DownloadMyExport)
+
+	admin := api.Group("/admin/users/:id/exports", auth, perm(domain.PermUserManage))
+	admin.POST("", handler.RequestUserExport)
+	admin.GET("", handler.ListUserExports)
+	admin.GET("/:exportId/download", handler.DownloadUserExport)
+}
+
+func SetupAPIKeyRoutes(api *echo.Group, handler *handler.APIKeyHandler, auth echo.MiddlewareFunc, perm middleware.RequirePermissionFunc) {
+	// Protected routes
+	keys := api.Group("/admin/api-keys", auth, perm(domain.PermAPIKeyManage))
+	keys.GET("", handler.ListAPIKeys)
+	keys.POST("", handler.CreateAPIKey)
+	keys.DELETE("/:id", handler.RevokeAPIKey)
+}
+
+func SetupAuditRoutes(api *echo.Group, handler *handler.AuditHandler, auth echo.MiddlewareFunc, perm middleware.RequirePermissionFunc) {
+	// Protected routes
+	api.GET("/admin/audit-logs", handler.ListAuditLogs, auth, perm(domain.PermAuditRead))
+}
+
+func SetupGuestBookingRoutes(api *echo.Group, handler *handler.GuestBookingHandler, limit echo.MiddlewareFunc) {
+	guest := api.Group("/guest/bookings")
+
+	// Public routes, authorised by the signed manage-booking token
+	guest.POST("", handler.CreateGuestBooking, limit)
+	guest.GET("/:token", handler.GetGuestBooking).Name = "guest-booking-detail"
+	guest.PATCH("/:token/cancel", handler.CancelGuestBooking)
+	guest.POST("/:token/pay", handler.PayGuestBooking)
+}
+
+func SetupPaymentRoutes(api *echo.Group, handler *handler.PaymentHandler, limit echo.MiddlewareFunc) {
 	payments := api.Group("/payments")
-	payments.POST("/webhook", handler.HandleWebhook)
+	payments.POST("/webhook", handler.HandleWebhook, limit)
+}
+
+func SetupJWKSRoutes(wellKnown *echo.Group, handler *handler.JWKSHandler) {
+	wellKnown.GET("/jwks.json", handler.GetJWKS)
 }
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"errors"
+	"hotel-booking-api/internal/domain"
+	"hotel-booking-api/internal/repository"
+	"slices"
+)
+
+var (
+	ErrHotelForbidden   = errors.New("you do not have access to this hotel")
+	ErrPermissionDenied = errors.New("you do not have permission to perform this action")
+)
+
+// Actor is the authenticated caller on whose behalf a service method runs.
+// Permissions are those resolved for the request by RequirePermission.
+//
+// An API key acts with no user: APIKeyID is set instead of UserID, and
+// HotelIDs lists the hotels the key is limited to, none meaning every hotel.
+// RequestID and IP identify the request in the audit log.
+type Actor struct {
+	UserID      string
+	Role        string
+	Permissions []string
+	APIKeyID    string
+	HotelIDs    []string
+	RequestID   string
+	IP          string
+}
+
+func (a Actor) IsAdmin() bool {
+	return a.Role == domain.RoleAdmin
+}
+
+// Can reports whether the actor holds permission. Admins hold every
+// permission.
+func (a Actor) Can(permission string) bool {
+	return a.IsAdmin() || slices.Contains(a.Permissions, permission)
+}
+
+// hotelAccess answers whether an actor may act on a hotel. Admins may act on
+// every hotel; managers and staff only on hotels they are assigned to, with
+// the assignment role being one of those allowed. API keys may act on the
+// hotels they are limited to, or on every hotel when they are not.
+type hotelAccess struct {
+	staffRepo repository.HotelStaffRepository
+}
+
+func (a hotelAccess) authorize(actor Actor, hotelID string, roles ...string) error {
+	if actor.IsAdmin() {
+		return nil
+	}
+
+	if actor.APIKeyID != "" {
+		if len(actor.HotelIDs) == 0 || slices.Contains(actor.HotelIDs, hotelID) {
+			return nil
+		}
+		return ErrHotelForbidden
+	}
+
+	if hotelID == "" {
+		return ErrHotelForbidden
+	}
+
+	role, err := a.staffRepo.FindRole(actor.UserID, hotelID)
+	if err != nil || !slices.Contains(roles, role) {
+		return ErrHotelForbidden
+	}
+
+	return nil
+}
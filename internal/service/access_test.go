@@ -0,0 +1,79 @@
+package service
+
+import (
+	"hotel-booking-api/internal/domain"
+	"hotel-booking-api/internal/repository"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockHotelStaffRepository struct {
+	mock.Mock
+}
+
+func (m *MockHotelStaffRepository) WithTx(tx *gorm.DB) repository.HotelStaffRepository {
+	return m
+}
+
+func (m *MockHotelStaffRepository) Assign(staff *domain.HotelStaff, audit *domain.AuditLog) error {
+	args := m.Called(staff, audit)
+	return args.Error(0)
+}
+
+func (m *MockHotelStaffRepository) Remove(hotelID, userID string, audit *domain.AuditLog) error {
+	args := m.Called(hotelID, userID, audit)
+	return args.Error(0)
+}
+
+func (m *MockHotelStaffRepository) FindRole(userID, hotelID string) (string, error) {
+	args := m.Called(userID, hotelID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockHotelStaffRepository) ListByHotel(hotelID string) ([]domain.HotelStaff, error) {
+	args := m.Called(hotelID)
+	return args.Get(0).([]domain.HotelStaff), args.Error(1)
+}
+
+func TestHotelAccess_Authorize(t *testing.T) {
+	hotelID := uuid.NewString()
+	manager := Actor{UserID: uuid.NewString(), Role: domain.RoleHotelManager}
+	staff := Actor{UserID: uuid.NewString(), Role: domain.RoleStaff}
+	outsider := Actor{UserID: uuid.NewString(), Role: domain.RoleHotelManager}
+
+	staffRepo := new(MockHotelStaffRepository)
+	staffRepo.On("FindRole", manager.UserID, hotelID).Return(domain.RoleHotelManager, nil)
+	staffRepo.On("FindRole", staff.UserID, hotelID).Return(domain.RoleStaff, nil)
+	staffRepo.On("FindRole", outsider.UserID, hotelID).Return("", gorm.ErrRecordNotFound)
+	access := hotelAccess{staffRepo: staffRepo}
+
+	tests := []struct {
+		name    string
+		actor   Actor
+		hotelID string
+		roles   []string
+		want    error
+	}{
+		{"manager may manage", manager, hotelID, []string{domain.RoleHotelManager}, nil},
+		{"staff may not manage", staff, hotelID, []string{domain.RoleHotelManager}, ErrHotelForbidden},
+		{"staff may operate", staff, hotelID, []string{domain.RoleHotelManager, domain.RoleStaff}, nil},
+		{"unassigned user", outsider, hotelID, []string{domain.RoleHotelManager, domain.RoleStaff}, ErrHotelForbidden},
+		{"no hotel", manager, "", []string{domain.RoleHotelManager}, ErrHotelForbidden},
+		{"admin", Actor{UserID: uuid.NewString(), Role: domain.RoleAdmin}, hotelID, []string{domain.RoleHotelManager}, nil},
+		{"unlimited API key", Actor{APIKeyID: uuid.NewString()}, hotelID, nil, nil},
+		{"API key for the hotel", Actor{APIKeyID: uuid.NewString(), HotelIDs: []string{hotelID}}, hotelID, nil, nil},
+		{"API key for another hotel", Actor{APIKeyID: uuid.NewString(), HotelIDs: []string{uuid.NewString()}}, hotelID, nil, ErrHotelForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, access.authorize(tt.actor, tt.hotelID, tt.roles...))
+		})
+	}
+
+	staffRepo.AssertNotCalled(t, "FindRole", manager.UserID, "")
+}
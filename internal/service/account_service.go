@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hotel-booking-api/internal/domain"
+	"hotel-booking-api/internal/repository"
+	"hotel-booking-api/pkg/mailer"
+	"hotel-booking-api/pkg/util"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidUserToken     = errors.New("invalid or expired token")
+	ErrEmailAlreadyVerified = errors.New("email is already verified")
+	ErrWrongPassword        = errors.New("current password is incorrect")
+)
+
+const (
+	emailVerificationTTL = 48 * time.Hour
+	passwordResetTTL     = time.Hour
+	emailChangeTTL       = 24 * time.Hour
+)
+
+// AccountService runs the email-based account flows: address verification,
+// password reset and changing the password or email address.
+type AccountService interface {
+	SendVerificationEmail(user *domain.User) error
+	ResendVerificationEmail(userID string) error
+	VerifyEmail(token string) error
+	ForgotPassword(email string) error
+	ResetPassword(token, password string) error
+	IsEmailVerified(userID string) (bool, error)
+	ChangePassword(userID, current, password string) error
+	RequestEmailChange(userID, password, email string) error
+	ConfirmEmailChange(token string) error
+}
+
+type accountService struct {
+	userRepo    repository.UserRepository
+	tokenRepo   repository.UserTokenRepository
+	sessionRepo repository.SessionRepository
+	mail        mailer.Mailer
+	publicURL   string
+}
+
+func NewAccountService(userRepo repository.UserRepository, tokenRepo repository.UserTokenRepository, sessionRepo repository.SessionRepository, mail mailer.Mailer, publicURL string) AccountService {
+	return &accountService{
+		userRepo:    userRepo,
+		tokenRepo:   tokenRepo,
+		sessionRepo: sessionRepo,
+		mail:        mail,
+		publicURL:   strings.TrimRight(publicURL, "/"),
+	}
+}
+
+func (s *accountService) SendVerificationEmail(user *domain.User) error {
+	token, err := s.issueToken(user, domain.TokenPurposeEmailVerification, emailVerificationTTL)
+	if err != nil {
+		return err
+	}
+
+	return s.mail.Send(context.Background(), mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nPlease confirm your email address by opening the link below:\n\n%s/verify-email?token=%s\n\nThe link expires in %d hours.\n",
+			user.Name, s.publicURL, token, int(emailVerificationTTL.Hours()),
+		),
+	})
+}
+
+func (s *accountService) ResendVerificationEmail(userID string) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+
+	if user.EmailVerifiedAt != nil {
+		return ErrEmailAlreadyVerified
+	}
+
+	return s.SendVerificationEmail(user)
+}
+
+func (s *accountService) VerifyEmail(token string) error {
+	userToken, err := s.tokenRepo.Consume(util.HashToken(token), domain.TokenPurposeEmailVerification)
+	if err != nil {
+		return ErrInvalidUserToken
+	}
+
+	return s.userRepo.MarkEmailVerified(userToken.UserID.String())
+}
+
+// ForgotPassword mails a reset link when email belongs to an account. It
+// reports success either way so the endpoint cannot be used to discover
+// registered addresses.
+func (s *accountService) ForgotPassword(email string) error {
+	user, err := s.userRepo.FindByEmail(email)
+	if err != nil {
+		return nil
+	}
+
+	token, err := s.issueToken(user, domain.TokenPurposePasswordReset, passwordResetTTL)
+	if err != nil {
+		return err
+	}
+
+	return s.mail.Send(context.Background(), mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nWe received a request to reset your password. Open the link below to choose a new one:\n\n%s/reset-password?token=%s\n\nThe link expires in %d minutes. If you did not ask for this, you can ignore this email.\n",
+			user.Name, s.publicURL, token, int(passwordResetTTL.Minutes()),
+		),
+	})
+}
+
+// ResetPassword sets a new password and signs the user out everywhere. Since
+// the token arrived by email, it also confirms the address.
+func (s *accountService) ResetPassword(token, password string) error {
+	userToken, err := s.tokenRepo.Consume(util.HashToken(token), domain.TokenPurposePasswordReset)
+	if err != nil {
+		return ErrInvalidUserToken
+	}
+
+	hash, err := util.HashPassword(password)
+	if err != nil {
+		return errors.New("failed to hash password")
+	}
+
+	userID := userToken.UserID.String()
+	if err := s.userRepo.UpdatePassword(userID, hash); err != nil {
+		return err
+	}
+
+	if err := s.userRepo.MarkEmailVerified(userID); err != nil {
+		return err
+	}
+
+	return s.sessionRepo.RevokeUser(userID)
+}
+
+func (s *accountService) IsEmailVerified(userID string) (bool, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return false, err
+	}
+
+	return user.EmailVerifiedAt != nil, nil
+}
+
+// ChangePassword replaces the password once the current one is confirmed and
+// signs the user out everywhere, including the session that changed it.
+func (s *accountService) ChangePassword(userID, current, password string) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+
+	if !util.CheckPassword(current, user.Password) {
+		return ErrWrongPassword
+	}
+
+	hash, err := util.HashPassword(password)
+	if err != nil {
+		return errors.New("failed to hash password")
+	}
+
+	if err := s.userRepo.UpdatePassword(userID, hash); err != nil {
+		return err
+	}
+
+	return s.sessionRepo.RevokeUser(userID)
+}
+
+// RequestEmailChange mails a confirmation link to the new address. The
+// account keeps its current email until the link is opened, and the current
+// address is told about the request in case someone else made it.
+func (s *accountService) RequestEmailChange(userID, password, email string) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+
+	if !util.CheckPassword(password, user.Password) {
+		return ErrWrongPassword
+	}
+
+	email = strings.TrimSpace(email)
+	if strings.EqualFold(email, user.Email) {
+		return errors.New("new email must differ from the current one")
+	}
+	if _, err := s.userRepo.FindByEmail(email); err == nil {
+		return ErrEmailTaken
+	}
+
+	if err := s.userRepo.SetPendingEmail(userID, email); err != nil {
+		return err
+	}
+
+	token, err := s.issueToken(user, domain.TokenPurposeEmailChange, emailChangeTTL)
+	if err != nil {
+		return err
+	}
+
+	err = s.mail.Send(context.Background(), mailer.Message{
+		To:      email,
+		Subject: "Confirm your new email address",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nPlease confirm that you want to use this address for your account by opening the link below:\n\n%s/confirm-email?token=%s\n\nThe link expires in %d hours.\n",
+			user.Name, s.publicURL, token, int(emailChangeTTL.Hours()),
+		),
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.mail.Send(context.Background(), mailer.Message{
+		To:      user.Email,
+		Subject: "Your email address is being changed",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nSomeone asked to change the email address of your account to %s. It will change once the new address is confirmed.\n\nIf this was not you, reset your password straight away.\n",
+			user.Name, email,
+		),
+	})
+}
+
+// ConfirmEmailChange switches the account to the address the token was
+// mailed to.
+func (s *accountService) ConfirmEmailChange(token string) error {
+	userToken, err := s.tokenRepo.Consume(util.HashToken(token), domain.TokenPurposeEmailChange)
+	if err != nil {
+		return ErrInvalidUserToken
+	}
+
+	userID := userToken.UserID.String()
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil || user.PendingEmail == "" {
+		return ErrInvalidUserToken
+	}
+
+	if _, err := s.userRepo.FindByEmail(user.PendingEmail); err == nil {
+		return ErrEmailTaken
+	}
+
+	if err := s.userRepo.ChangeEmail(userID, user.PendingEmail); err != nil {
+		return ErrInvalidUserToken
+	}
+
+	return nil
+}
+
+func (s *accountService) issueToken(user *domain.User, purpose string, ttl time.Duration) (string, error) {
+	token, err := util.GenerateOpaqueToken()
+	if err != nil {
+		return "", errors.New("failed to generate token")
+	}
+
+	err = s.tokenRepo.Create(&domain.UserToken{
+		UserID:    user.ID,
+		Purpose:   purpose,
+		TokenHash: util.HashToken(token),
+		ExpiresAt: time.Now().Add(ttl),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"hotel-booking-api/internal/domain"
+	"hotel-booking-api/pkg/mailer"
+	"hotel-booking-api/pkg/util"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockUserTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserTokenRepository) Create(token *domain.UserToken) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockUserTokenRepository) Consume(hash, purpose string) (*domain.UserToken, error) {
+	args := m.Called(hash, purpose)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.UserToken), args.Error(1)
+}
+
+type recordingMailer struct {
+	sent []mailer.Message
+}
+
+func (m *recordingMailer) Send(ctx context.Context, msg mailer.Message) error {
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+func TestAccountService_ForgotPassword_UnknownEmail(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mail := &recordingMailer{}
+	service := NewAccountService(mockRepo, new(MockUserTokenRepository), new(MockSessionRepository), mail, "http://localhost:3000")
+
+	mockRepo.On("FindByEmail", "nobody@example.com").Return(nil, errors.New("not found"))
+
+	err := service.ForgotPassword("nobody@example.com")
+
+	assert.NoError(t, err)
+	assert.Empty(t, mail.sent)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAccountService_ForgotPassword_SendsHashedToken(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockTokens := new(MockUserTokenRepository)
+	mail := &recordingMailer{}
+	service := NewAccountService(mockRepo, mockTokens, new(MockSessionRepository), mail, "http://localhost:3000/")
+
+	user := &domain.User{ID: uuid.New(), Name: "Test User", Email: "test@example.com"}
+	mockRepo.On("FindByEmail", "test@example.com").Return(user, nil)
+
+	var stored *domain.UserToken
+	mockTokens.On("Create", mock.AnythingOfType("*domain.UserToken")).Run(func(args mock.Arguments) {
+		stored = args.Get(0).(*domain.UserToken)
+	}).Return(nil)
+
+	err := service.ForgotPassword("test@example.com")
+
+	assert.NoError(t, err)
+	assert.Len(t, mail.sent, 1)
+	assert.Equal(t, domain.TokenPurposePasswordReset, stored.Purpose)
+
+	_, token, found := strings.Cut(mail.sent[0].Body, "http://localhost:3000/reset-password?token=")
+	assert.True(t, found)
+	token, _, _ = strings.Cut(token, "\n")
+	assert.Equal(t, util.HashToken(token), stored.TokenHash)
+}
+
+func TestAccountService_ResetPassword_RevokesSessions(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockTokens := new(MockUserTokenRepository)
+	mockSessions := new(MockSessionRepository)
+	service := NewAccountService(mockRepo, mockTokens, mockSessions, &recordingMailer{}, "http://localhost:3000")
+
+	userID := uuid.New()
+	mockTokens.On("Consume", util.HashToken("reset-token"), domain.TokenPurposePasswordReset).
+		Return(&domain.UserToken{UserID: userID}, nil)
+	mockRepo.On("UpdatePassword", userID.String(), mock.AnythingOfType("string")).Return(nil)
+	mockRepo.On("MarkEmailVerified", userID.String()).Return(nil)
+	mockSessions.On("RevokeUser", userID.String()).Return(nil)
+
+	err := service.ResetPassword("reset-token", "new-password")
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockTokens.AssertExpectations(t)
+	mockSessions.AssertExpectations(t)
+}
+
+func TestAccountService_ResetPassword_InvalidToken(t *testing.T) {
+	mockTokens := new(MockUserTokenRepository)
+	service := NewAccountService(new(MockUserRepository), mockTokens, new(MockSessionRepository), &recordingMailer{}, "http://localhost:3000")
+
+	mockTokens.On("Consume", util.HashToken("used-token"), domain.TokenPurposePasswordReset).
+		Return(nil, errors.New("record not found"))
+
+	err := service.ResetPassword("used-token", "new-password")
+
+	assert.ErrorIs(t, err, ErrInvalidUserToken)
+}
+
+func TestAccountService_ChangePassword_WrongPassword(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewAccountService(mockRepo, new(MockUserTokenRepository), new(MockSessionRepository), &recordingMailer{}, "http://localhost:3000")
+
+	hash, _ := util.HashPassword("password123")
+	user := &domain.User{ID: uuid.New(), Password: hash}
+	mockRepo.On("FindByID", user.ID.String()).Return(user, nil)
+
+	err := service.ChangePassword(user.ID.String(), "wrong-password", "new-password")
+
+	assert.ErrorIs(t, err, ErrWrongPassword)
+	mockRepo.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything)
+}
+
+func TestAccountService_RequestEmailChange_MailsNewAddress(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockTokens := new(MockUserTokenRepository)
+	mail := &recordingMailer{}
+	service := NewAccountService(mockRepo, mockTokens, new(MockSessionRepository), mail, "http://localhost:3000")
+
+	hash, _ := util.HashPassword("password123")
+	user := &domain.User{ID: uuid.New(), Name: "Test User", Email: "old@example.com", Password: hash}
+	mockRepo.On("FindByID", user.ID.String()).Return(user, nil)
+	mockRepo.On("FindByEmail", "new@example.com").Return(nil, errors.New("not found"))
+	mockRepo.On("SetPendingEmail", user.ID.String(), "new@example.com").Return(nil)
+	mockTokens.On("Create", mock.MatchedBy(func(token *domain.UserToken) bool {
+		return token.Purpose == domain.TokenPurposeEmailChange
+	})).Return(nil)
+
+	err := service.RequestEmailChange(user.ID.String(), "password123", " new@example.com ")
+
+	assert.NoError(t, err)
+	if assert.Len(t, mail.sent, 2) {
+		assert.Equal(t, "new@example.com", mail.sent[0].To)
+		assert.Contains(t, mail.sent[0].Body, "http://localhost:3000/confirm-email?token=")
+		assert.Equal(t, "old@example.com", mail.sent[1].To)
+	}
+	mockRepo.AssertNotCalled(t, "ChangeEmail", mock.Anything, mock.Anything)
+	mockRepo.AssertExpectations(t)
+	mockTokens.AssertExpectations(t)
+}
+
+func TestAccountService_ConfirmEmailChange(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockTokens := new(MockUserTokenRepository)
+	service := NewAccountService(mockRepo, mockTokens, new(MockSessionRepository), &recordingMailer{}, "http://localhost:3000")
+
+	user := &domain.User{ID: uuid.New(), Email: "old@example.com", PendingEmail: "new@example.com"}
+	mockTokens.On("Consume", util.HashToken("change-token"), domain.TokenPurposeEmailChange).
+		Return(&domain.UserToken{UserID: user.ID}, nil)
+	mockRepo.On("FindByID", user.ID.String()).Return(user, nil)
+	mockRepo.On("FindByEmail", "new@example.com").Return(nil, errors.New("not found"))
+	mockRepo.On("ChangeEmail", user.ID.String(), "new@example.com").Return(nil)
+
+	err := service.ConfirmEmailChange("change-token")
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
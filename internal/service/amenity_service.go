@@ -0,0 +1,163 @@
+package service
+
+import (
+	"errors"
+	"hotel-booking-api/internal/domain"
+	"hotel-booking-api/internal/repository"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var amenityCodePattern = regexp.MustCompile(`^[a-z0-9]+(_[a-z0-9]+)*$`)
+
+var amenityCategories = []string{
+	domain.AmenityCategoryGeneral,
+	domain.AmenityCategoryRoom,
+	domain.AmenityCategoryBathroom,
+	domain.AmenityCategoryFood,
+	domain.AmenityCategoryBed,
+	domain.AmenityCategoryView,
+	domain.AmenityCategoryAccessibility,
+}
+
+type AmenityService interface {
+	CreateAmenity(amenity *domain.Amenity) error
+	UpdateAmenity(amenity *domain.Amenity) error
+	DeleteAmenity(id string) error
+	GetAmenity(id string) (*domain.Amenity, error)
+	ListAmenities(category string) ([]domain.Amenity, error)
+	SetHotelAmenities(hotelID string, codes []string) ([]domain.Amenity, error)
+	SetRoomAmenities(roomID string, codes []string) ([]domain.Amenity, error)
+}
+
+type amenityService struct {
+	amenityRepo repository.AmenityRepository
+	hotelRepo   repository.HotelRepository
+	roomRepo    repository.RoomRepository
+}
+
+func NewAmenityService(amenityRepo repository.AmenityRepository, hotelRepo repository.HotelRepository, roomRepo repository.RoomRepository) AmenityService {
+	return &amenityService{
+		amenityRepo: amenityRepo,
+		hotelRepo:   hotelRepo,
+		roomRepo:    roomRepo,
+	}
+}
+
+func (s *amenityService) CreateAmenity(amenity *domain.Amenity) error {
+	if err := validateAmenity(amenity); err != nil {
+		return err
+	}
+
+	if existing, _ := s.amenityRepo.FindByCodes([]string{amenity.Code}); len(existing) > 0 {
+		return errors.New("amenity code already exists")
+	}
+
+	return s.amenityRepo.Create(amenity)
+}
+
+func (s *amenityService) UpdateAmenity(amenity *domain.Amenity) error {
+	if err := validateAmenity(amenity); err != nil {
+		return err
+	}
+
+	existing, _ := s.amenityRepo.FindByCodes([]string{amenity.Code})
+	if len(existing) > 0 && existing[0].ID != amenity.ID {
+		return errors.New("amenity code already exists")
+	}
+
+	return s.amenityRepo.Update(amenity)
+}
+
+func (s *amenityService) DeleteAmenity(id string) error {
+	if _, err := s.amenityRepo.FindByID(id); err != nil {
+		return errors.New("amenity not found")
+	}
+
+	return s.amenityRepo.Delete(id)
+}
+
+func (s *amenityService) GetAmenity(id string) (*domain.Amenity, error) {
+	amenity, err := s.amenityRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("amenity not found")
+	}
+
+	return amenity, nil
+}
+
+func (s *amenityService) ListAmenities(category string) ([]domain.Amenity, error) {
+	return s.amenityRepo.FindAll(strings.ToUpper(category))
+}
+
+func (s *amenityService) SetHotelAmenities(hotelID string, codes []string) ([]domain.Amenity, error) {
+	hotel, err := s.hotelRepo.FindByID(hotelID)
+	if err != nil {
+		return nil, errors.New("hotel not found")
+	}
+
+	amenities, err := s.resolveCodes(codes)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.amenityRepo.ReplaceHotelAmenities(hotel, amenities); err != nil {
+		return nil, err
+	}
+
+	return amenities, nil
+}
+
+func (s *amenityService) SetRoomAmenities(roomID string, codes []string) ([]domain.Amenity, error) {
+	room, err := s.roomRepo.FindByID(roomID)
+	if err != nil {
+		return nil, errors.New("room not found")
+	}
+
+	amenities, err := s.resolveCodes(codes)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.amenityRepo.ReplaceRoomAmenities(room, amenities); err != nil {
+		return nil, err
+	}
+
+	return amenities, nil
+}
+
+// resolveCodes loads the amenities for codes, failing if any code is unknown.
+func (s *amenityService) resolveCodes(codes []string) ([]domain.Amenity, error) {
+	if len(codes) == 0 {
+		return []domain.Amenity{}, nil
+	}
+
+	amenities, err := s.amenityRepo.FindByCodes(codes)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, code := range codes {
+		if !slices.ContainsFunc(amenities, func(a domain.Amenity) bool { return a.Code == code }) {
+			return nil, errors.New("unknown amenity code: " + code)
+		}
+	}
+
+	return amenities, nil
+}
+
+func validateAmenity(amenity *domain.Amenity) error {
+	amenity.Code = strings.ToLower(strings.TrimSpace(amenity.Code))
+	amenity.Category = strings.ToUpper(strings.TrimSpace(amenity.Category))
+
+	if !amenityCodePattern.MatchString(amenity.Code) {
+		return errors.New("amenity code must be lowercase letters, digits and underscores")
+	}
+
+	if !slices.Contains(amenityCategories, amenity.Category) {
+		return errors.New("amenity category must be one of: " + strings.Join(amenityCategories, ", "))
+	}
+
+	return nil
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"hotel-booking-api/internal/domain"
+	"hotel-booking-api/internal/repository"
+	"hotel-booking-api/pkg/logger"
+	"hotel-booking-api/pkg/pagination"
+	"hotel-booking-api/pkg/util"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrAPIKeyNotFound = errors.New("API key not found")
+	ErrInvalidAPIKey  = errors.New("invalid, expired or revoked API key")
+)
+
+// apiKeyUseInterval is how stale an API key's last-used time may get before
+// it is written again.
+const apiKeyUseInterval = time.Minute
+
+// NewAPIKeyInput describes an API key to create. Without HotelIDs the key may
+// act on every hotel.
+type NewAPIKeyInput struct {
+	Name        string
+	Permissions []string
+	HotelIDs    []string
+	ExpiresAt   *time.Time
+}
+
+// CreatedAPIKey is a newly created key together with its secret, which is
+// shown this once and never stored.
+type CreatedAPIKey struct {
+	Key    *domain.APIKey
+	Secret string
+}
+
+type APIKeyService interface {
+	CreateAPIKey(actor Actor, input NewAPIKeyInput) (*CreatedAPIKey, error)
+	ListAPIKeys(query pagination.Query) ([]domain.APIKey, int64, error)
+	RevokeAPIKey(actor Actor, id string) error
+	AuthenticateAPIKey(key string) (*domain.APIKey, error)
+}
+
+type apiKeyService struct {
+	apiKeyRepo repository.APIKeyRepository
+	roleRepo   repository.RoleRepository
+	hotelRepo  repository.HotelRepository
+	access     hotelAccess
+}
+
+func NewAPIKeyService(apiKeyRepo repository.APIKeyRepository, roleRepo repository.RoleRepository, hotelRepo repository.HotelRepository, staffRepo repository.HotelStaffRepository) APIKeyService {
+	return &apiKeyService{
+		apiKeyRepo: apiKeyRepo,
+		roleRepo:   roleRepo,
+		hotelRepo:  hotelRepo,
+		access:     hotelAccess{staffRepo: staffRepo},
+	}
+}
+
+// CreateAPIKey issues a key. A key can never do more than its creator: every
+// permission must be one the actor holds, and only admins may create keys
+// that are not limited to hotels, or limited to hotels they do not manage.
+// Keys never hold user-bound permissions, and keys limited to hotels hold
+// only permissions that are checked against a hotel, so the limit cannot be
+// bypassed.
+func (s *apiKeyService) CreateAPIKey(actor Actor, input NewAPIKeyInput) (*CreatedAPIKey, error) {
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(time.Now()) {
+		return nil, errors.New("expires_at must be in the future")
+	}
+
+	for _, code := range input.Permissions {
+		if !actor.Can(code) {
+			return nil, ErrPermissionDenied
+		}
+		if slices.Contains(domain.UserBoundPermissions, code) {
+			return nil, fmt.Errorf("API keys cannot hold %s, which acts for a user", code)
+		}
+		if len(input.HotelIDs) > 0 && !slices.Contains(domain.HotelScopedPermissions, code) {
+			return nil, fmt.Errorf("keys limited to hotels cannot hold %s, which is not limited to a hotel", code)
+		}
+	}
+
+	if len(input.HotelIDs) == 0 && !actor.IsAdmin() {
+		return nil, ErrPermissionDenied
+	}
+
+	permissions, err := findPermissions(s.roleRepo, input.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	hotels := make([]domain.Hotel, 0, len(input.HotelIDs))
+	for _, hotelID := range input.HotelIDs {
+		hotel, err := s.hotelRepo.FindByID(hotelID)
+		if err != nil {
+			return nil, errors.New("hotel not found")
+		}
+		if err := s.access.authorize(actor, hotelID, domain.RoleHotelManager); err != nil {
+			return nil, err
+		}
+		hotels = append(hotels, *hotel)
+	}
+
+	secret, prefix, err := util.GenerateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	key := &domain.APIKey{
+		ID:          uuid.New(),
+		Name:        strings.TrimSpace(input.Name),
+		Prefix:      prefix,
+		KeyHash:     util.HashToken(secret),
+		CreatedByID: util.ParseUUID(actor.UserID),
+		ExpiresAt:   input.ExpiresAt,
+		Permissions: permissions,
+		Hotels:      hotels,
+	}
+	audit := auditEntry(actor, domain.AuditAPIKeyCreate, domain.AuditEntityAPIKey, key.ID.String(), nil, map[string]any{
+		"name":        key.Name,
+		"prefix":      key.Prefix,
+		"expires_at":  key.ExpiresAt,
+		"permissions": key.PermissionCodes(),
+		"hotel_ids":   key.HotelIDs(),
+	})
+	if err := s.apiKeyRepo.Create(key, audit); err != nil {
+		return nil, errors.New("failed to create API key")
+	}
+
+	return &CreatedAPIKey{Key: key, Secret: secret}, nil
+}
+
+func (s *apiKeyService) ListAPIKeys(query pagination.Query) ([]domain.APIKey, int64, error) {
+	return s.apiKeyRepo.List(query)
+}
+
+func (s *apiKeyService) RevokeAPIKey(actor Actor, id string) error {
+	if err := s.apiKeyRepo.Revoke(id, auditEntry(actor, domain.AuditAPIKeyRevoke, domain.AuditEntityAPIKey, id, nil, nil)); err != nil {
+		return ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+// AuthenticateAPIKey returns the active key matching key and records that it
+// was used.
+func (s *apiKeyService) AuthenticateAPIKey(key string) (*domain.APIKey, error) {
+	apiKey, err := s.apiKeyRepo.FindByHash(util.HashToken(key))
+	if err != nil || apiKey.RevokedAt != nil {
+		return nil, ErrInvalidAPIKey
+	}
+
+	now := time.Now()
+	if apiKey.ExpiresAt != nil && now.After(*apiKey.ExpiresAt) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	// Losing a last-used update is not worth failing the request over.
+	if err := s.apiKeyRepo.TouchLastUsed(apiKey.ID.String(), now, apiKeyUseInterval); err != nil {
+		logger.Warn("Failed to record API key use", "api_key", apiKey.Prefix, "error", err)
+	}
+
+	return apiKey, nil
+}
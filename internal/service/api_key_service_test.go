@@ -0,0 +1,156 @@
+package service
+
+import (
+	"hotel-booking-api/internal/domain"
+	"hotel-booking-api/pkg/pagination"
+	"hotel-booking-api/pkg/util"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepository) Create(key *domain.APIKey, audit *domain.AuditLog) error {
+	args := m.Called(key, audit)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) FindByID(id string) (*domain.APIKey, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) FindByHash(hash string) (*domain.APIKey, error) {
+	args := m.Called(hash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) List(query pagination.Query) ([]domain.APIKey, int64, error) {
+	args := m.Called(query)
+	return args.Get(0).([]domain.APIKey), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockAPIKeyRepository) Revoke(id string, audit *domain.AuditLog) error {
+	args := m.Called(id, audit)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) TouchLastUsed(id string, at time.Time, interval time.Duration) error {
+	args := m.Called(id, at, interval)
+	return args.Error(0)
+}
+
+func TestAPIKeyService_CreateAPIKey_CannotExceedCreator(t *testing.T) {
+	mockKeys := new(MockAPIKeyRepository)
+	service := NewAPIKeyService(mockKeys, nil, nil, nil)
+
+	actor := Actor{
+		UserID:      uuid.NewString(),
+		Role:        domain.RoleHotelManager,
+		Permissions: []string{domain.PermAPIKeyManage, domain.PermBookingRead},
+	}
+	_, err := service.CreateAPIKey(actor, NewAPIKeyInput{
+		Name:        "Channel manager",
+		Permissions: []string{domain.PermBookingReadAny},
+		HotelIDs:    []string{uuid.NewString()},
+	})
+
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+	mockKeys.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestAPIKeyService_CreateAPIKey_UnscopedRequiresAdmin(t *testing.T) {
+	mockKeys := new(MockAPIKeyRepository)
+	service := NewAPIKeyService(mockKeys, nil, nil, nil)
+
+	actor := Actor{
+		UserID:      uuid.NewString(),
+		Role:        domain.RoleHotelManager,
+		Permissions: []string{domain.PermAPIKeyManage, domain.PermBookingRead},
+	}
+	_, err := service.CreateAPIKey(actor, NewAPIKeyInput{
+		Name:        "Channel manager",
+		Permissions: []string{domain.PermBookingRead},
+	})
+
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+}
+
+func TestAPIKeyService_CreateAPIKey_ScopedKeyHoldsOnlyHotelPermissions(t *testing.T) {
+	service := NewAPIKeyService(new(MockAPIKeyRepository), nil, nil, nil)
+
+	for _, code := range []string{
+		domain.PermBookingReadAny, domain.PermPhotoWrite, domain.PermAmenityWrite,
+		domain.PermReviewModerate, domain.PermHotelCreate, domain.PermHotelDelete,
+	} {
+		_, err := service.CreateAPIKey(Actor{Role: domain.RoleAdmin}, NewAPIKeyInput{
+			Name:        "Channel manager",
+			Permissions: []string{code},
+			HotelIDs:    []string{uuid.NewString()},
+		})
+
+		assert.EqualError(t, err, "keys limited to hotels cannot hold "+code+", which is not limited to a hotel")
+	}
+}
+
+func TestAPIKeyService_CreateAPIKey_RefusesUserBoundPermissions(t *testing.T) {
+	service := NewAPIKeyService(new(MockAPIKeyRepository), nil, nil, nil)
+
+	for _, code := range domain.UserBoundPermissions {
+		_, err := service.CreateAPIKey(Actor{Role: domain.RoleAdmin}, NewAPIKeyInput{
+			Name:        "Travel agent",
+			Permissions: []string{code},
+		})
+
+		assert.EqualError(t, err, "API keys cannot hold "+code+", which acts for a user")
+	}
+}
+
+func TestAPIKeyService_AuthenticateAPIKey(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name  string
+		key   *domain.APIKey
+		valid bool
+	}{
+		{"active", &domain.APIKey{ID: uuid.New()}, true},
+		{"revoked", &domain.APIKey{ID: uuid.New(), RevokedAt: &past}, false},
+		{"expired", &domain.APIKey{ID: uuid.New(), ExpiresAt: &past}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockKeys := new(MockAPIKeyRepository)
+			service := NewAPIKeyService(mockKeys, nil, nil, nil)
+
+			mockKeys.On("FindByHash", util.HashToken("hbk_0000_secret")).Return(tt.key, nil)
+			mockKeys.On("TouchLastUsed", tt.key.ID.String(), mock.AnythingOfType("time.Time"), apiKeyUseInterval).Return(nil)
+
+			key, err := service.AuthenticateAPIKey("hbk_0000_secret")
+
+			if tt.valid {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.key, key)
+				mockKeys.AssertCalled(t, "TouchLastUsed", tt.key.ID.String(), mock.AnythingOfType("time.Time"), apiKeyUseInterval)
+			} else {
+				assert.ErrorIs(t, err, ErrInvalidAPIKey)
+				mockKeys.AssertNotCalled(t, "TouchLastUsed", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
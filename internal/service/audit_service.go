@@ -0,0 +1,114 @@
+package service
+
+import (
+	"encoding/json"
+	"hotel-booking-api/internal/domain"
+	"hotel-booking-api/internal/repository"
+	"reflect"
+
+	"github.com/google/uuid"
+)
+
+type AuditService interface {
+	ListEntries(filter repository.AuditFilter) ([]domain.AuditLog, int64, error)
+}
+
+type auditService struct {
+	auditRepo repository.AuditRepository
+}
+
+func NewAuditService(auditRepo repository.AuditRepository) AuditService {
+	return &auditService{
+		auditRepo: auditRepo,
+	}
+}
+
+func (s *auditService) ListEntries(filter repository.AuditFilter) ([]domain.AuditLog, int64, error) {
+	return s.auditRepo.List(filter)
+}
+
+// auditEntry describes action by actor on an entity, recording the fields
+// that differ between before and after. Both are anything that marshals to a
+// JSON object, usually the entity itself; before is nil for creations and
+// after is nil for deletions.
+func auditEntry(actor Actor, action, entityType, entityID string, before, after any) *domain.AuditLog {
+	entry := &domain.AuditLog{
+		ActorRole:  actor.Role,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Changes:    auditChanges(before, after),
+		RequestID:  actor.RequestID,
+		IP:         actor.IP,
+	}
+	if id, err := uuid.Parse(actor.UserID); err == nil {
+		entry.ActorID = &id
+	}
+	if id, err := uuid.Parse(actor.APIKeyID); err == nil {
+		entry.APIKeyID = &id
+	}
+
+	return entry
+}
+
+// auditChanges compares the JSON fields of before and after. Timestamps and
+// versions that move on every write are left out, and so are nested objects: those are
+// related records, audited in their own right if at all.
+func auditChanges(before, after any) map[string]domain.AuditChange {
+	from, to := auditFields(before), auditFields(after)
+
+	changes := make(map[string]domain.AuditChange)
+	for name, value := range from {
+		if !reflect.DeepEqual(value, to[name]) {
+			changes[name] = domain.AuditChange{From: value, To: to[name]}
+		}
+	}
+	for name, value := range to {
+		if _, ok := from[name]; !ok {
+			changes[name] = domain.AuditChange{To: value}
+		}
+	}
+
+	return changes
+}
+
+// auditFields flattens v to its auditable JSON fields. Empty values are
+// dropped so that a missing field and an empty one compare equal.
+func auditFields(v any) map[string]any {
+	fields := make(map[string]any)
+	if v == nil {
+		return fields
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return fields
+	}
+	var all map[string]any
+	if err := json.Unmarshal(raw, &all); err != nil {
+		return fields
+	}
+
+	for name, value := range all {
+		if name == "created_at" || name == "updated_at" || name == "version" || isRelated(value) {
+			continue
+		}
+		if list, ok := value.([]any); value == nil || ok && len(list) == 0 {
+			continue
+		}
+		fields[name] = value
+	}
+
+	return fields
+}
+
+func isRelated(value any) bool {
+	switch value := value.(type) {
+	case map[string]any:
+		return true
+	case []any:
+		return len(value) > 0 && isRelated(value[0])
+	default:
+		return false
+	}
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"hotel-booking-api/internal/domain"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditChanges_Update(t *testing.T) {
+	before := &domain.Room{ID: uuid.New(), RoomType: "Deluxe", PricePerNight: 100, Availability: 3}
+	after := *before
+	after.PricePerNight = 120
+	after.Hotel = domain.Hotel{Name: "Preloaded relation"}
+
+	changes := auditChanges(before, &after)
+
+	assert.Equal(t, map[string]domain.AuditChange{
+		"price_per_night": {From: float64(100), To: float64(120)},
+	}, changes)
+}
+
+func TestAuditChanges_CreateAndDelete(t *testing.T) {
+	state := map[string]any{"name": "Auditor", "permissions": []string{"audit:read"}}
+
+	created := auditChanges(nil, state)
+	deleted := auditChanges(state, nil)
+
+	assert.Equal(t, domain.AuditChange{To: "Auditor"}, created["name"])
+	assert.Equal(t, domain.AuditChange{From: []any{"audit:read"}}, deleted["permissions"])
+}
+
+func TestAuditEntry_Actor(t *testing.T) {
+	keyID := uuid.New()
+	actor := Actor{APIKeyID: keyID.String(), RequestID: "req-1", IP: "192.0.2.1"}
+
+	entry := auditEntry(actor, domain.AuditHotelUpdate, domain.AuditEntityHotel, "hotel-1", nil, nil)
+
+	assert.Nil(t, entry.ActorID)
+	assert.Equal(t, &keyID, entry.APIKeyID)
+	assert.Equal(t, "req-1", entry.RequestID)
+	assert.Equal(t, "192.0.2.1", entry.IP)
+	assert.Empty(t, entry.Changes)
+}
@@ -1,32 +1,133 @@
 package service
 
 import (
+	"crypto/rand"
 	"errors"
 	"hotel-booking-api/internal/domain"
 	"hotel-booking-api/internal/repository"
+	"hotel-booking-api/pkg/logger"
 	"hotel-booking-api/pkg/util"
+	"strings"
+	"time"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
 )
 
+var (
+	ErrInvalidCredentials   = errors.New("invalid email or password")
+	ErrTooManyLoginAttempts = errors.New("too many failed login attempts, try again later")
+	ErrInvalidRefreshToken  = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused   = errors.New("refresh token has already been used; the session has been revoked")
+)
+
+// Login throttling. After loginFreeAttempts failures each further attempt
+// must wait twice as long as the previous one, up to loginMaxDelay; reaching
+// a lock threshold blocks the subject for loginLockDuration. Failures are
+// forgotten after loginFailureWindow without another one.
+const (
+	loginFailureWindow   = 15 * time.Minute
+	loginFreeAttempts    = 3
+	loginMaxDelay        = 30 * time.Second
+	accountLockThreshold = 10
+	ipLockThreshold      = 50
+	loginLockDuration    = 15 * time.Minute
+)
+
+// maxAuditEntityID is the size of the audit log's entity_id column.
+const maxAuditEntityID = 100
+
+// dummyPasswordHash is compared against when the email is unknown, so a
+// failed login takes as long whether or not the account exists.
+const dummyPasswordHash = "$2a$14$9kMIwj8TTExC7A.ph8UaW.XUqJ08USbzQfytaXx55sTT93zl2zCp2"
+
+// LoginThrottledError is returned while a login is being delayed or the
+// account or client is locked out.
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	return ErrTooManyLoginAttempts.Error()
+}
+
+func (e *LoginThrottledError) Unwrap() error {
+	return ErrTooManyLoginAttempts
+}
+
 type AuthService interface {
-	Register(name, email, password, role string) (*domain.User, error)
-	Login(email, password string) (string, *domain.User, error)
+	Register(name, email, password string) (*domain.User, error)
+	Login(email, password, clientIP string) (*LoginResult, error)
+	VerifyTwoFactor(challengeToken, code, clientIP string) (*LoginResult, error)
+	Refresh(refreshToken string) (*AuthTokens, *domain.User, error)
+	Logout(claims *util.JWTClaims, refreshToken string, allSessions bool) error
+	IsTokenRevoked(jti string) (bool, error)
+	UnlockAccount(actor Actor, userID string) error
+	SetupTwoFactor(userID string) (*TwoFactorSetup, error)
+	EnableTwoFactor(userID, code string) ([]string, error)
+	DisableTwoFactor(userID, password, code string) error
+	RegenerateRecoveryCodes(userID, code string) ([]string, error)
+	ResetTwoFactor(userID string) error
+}
+
+// AuthOptions configures token lifetimes and the two-factor policy.
+type AuthOptions struct {
+	AccessTTL             time.Duration
+	RefreshTTL            time.Duration
+	TOTPIssuer            string
+	RequireAdminTwoFactor bool
+	// AuditHashKey keys the hash recorded for lockouts of unknown emails. A
+	// random key is generated when it is empty.
+	AuditHashKey []byte
+}
+
+// LoginResult is the outcome of a login step: either a token pair or, when
+// the account has two-factor authentication, a challenge to complete with
+// VerifyTwoFactor.
+type LoginResult struct {
+	User      *domain.User
+	Tokens    *AuthTokens
+	Challenge *TwoFactorChallenge
+}
+
+// AuthTokens is a short-lived access token and the refresh token that renews
+// it.
+type AuthTokens struct {
+	AccessToken      string
+	AccessExpiresAt  time.Time
+	RefreshToken     string
+	RefreshExpiresAt time.Time
 }
 
 type authService struct {
-	userRepo repository.UserRepository
-	validate *validator.Validate
+	userRepo     repository.UserRepository
+	sessionRepo  repository.SessionRepository
+	attemptRepo  repository.LoginAttemptRepository
+	recoveryRepo repository.RecoveryCodeRepository
+	validate     *validator.Validate
+	opts         AuthOptions
+	// now is the clock TOTP codes are checked against.
+	now func() time.Time
 }
 
-func NewAuthService(userRepo repository.UserRepository, validate *validator.Validate) AuthService {
+func NewAuthService(userRepo repository.UserRepository, sessionRepo repository.SessionRepository, attemptRepo repository.LoginAttemptRepository, recoveryRepo repository.RecoveryCodeRepository, validate *validator.Validate, opts AuthOptions) AuthService {
+	if len(opts.AuditHashKey) == 0 {
+		opts.AuditHashKey = make([]byte, 32)
+		rand.Read(opts.AuditHashKey)
+	}
+
 	return &authService{
-		userRepo: userRepo,
-		validate: validate,
+		userRepo:     userRepo,
+		sessionRepo:  sessionRepo,
+		attemptRepo:  attemptRepo,
+		recoveryRepo: recoveryRepo,
+		validate:     validate,
+		opts:         opts,
+		now:          time.Now,
 	}
 }
 
-func (s *authService) Register(name, email, password, role string) (*domain.User, error) {
+func (s *authService) Register(name, email, password string) (*domain.User, error) {
 	if err := s.validate.Var(email, "required,email"); err != nil {
 		return nil, errors.New("invalid email format")
 	}
@@ -48,30 +149,319 @@ func (s *authService) Register(name, email, password, role string) (*domain.User
 		Name:     name,
 		Email:    email,
 		Password: hash,
-		Role:     role,
+		Role:     domain.RoleCustomer,
 	}
 
-	if err := s.userRepo.Create(user); err != nil {
+	if err := s.userRepo.Create(user, nil); err != nil {
 		return nil, err
 	}
 
 	return user, nil
 }
 
-func (s *authService) Login(email, password string) (string, *domain.User, error) {
+// Login checks the credentials, throttling repeated failures per account and
+// per client IP. Unknown emails and wrong passwords fail the same way.
+// Accounts with two-factor authentication get a challenge instead of tokens.
+func (s *authService) Login(email, password, clientIP string) (*LoginResult, error) {
+	accountKey := accountSubject(email)
+	ipKey := "ip:" + clientIP
+
+	if err := s.checkLoginThrottle(accountKey, ipKey); err != nil {
+		return nil, err
+	}
+
 	user, err := s.userRepo.FindByEmail(email)
 	if err != nil {
-		return "", nil, errors.New("user not found")
+		util.CheckPassword(password, dummyPasswordHash)
+		return nil, s.loginFailed(ErrInvalidCredentials, clientIP, s.unknownAccountLock(email), ipLock(ipKey))
 	}
 
 	if !util.CheckPassword(password, user.Password) {
-		return "", nil, errors.New("incorrect password")
+		return nil, s.loginFailed(ErrInvalidCredentials, clientIP, userLock(accountKey, user.ID.String()), ipLock(ipKey))
+	}
+
+	if user.DeactivatedAt != nil {
+		return nil, ErrAccountDeactivated
+	}
+
+	// The IP counter is left to decay so an attacker cannot clear it by
+	// logging in to an account of their own.
+	if err := s.attemptRepo.Reset(accountKey, nil); err != nil {
+		return nil, err
+	}
+
+	if user.TOTPEnabledAt != nil {
+		challenge, err := s.newTwoFactorChallenge(user)
+		if err != nil {
+			return nil, err
+		}
+		return &LoginResult{User: user, Challenge: challenge}, nil
 	}
 
-	token, err := util.GenerateJWT(user.ID.String(), user.Role)
+	return s.startSession(user, false)
+}
+
+// Refresh exchanges a refresh token for a new token pair. Each refresh token
+// works once; presenting one that was already exchanged means it has leaked,
+// so every token issued from the same login is revoked.
+func (s *authService) Refresh(refreshToken string) (*AuthTokens, *domain.User, error) {
+	current, err := s.sessionRepo.FindByHash(util.HashToken(refreshToken))
+	if err != nil || current.RevokedAt != nil {
+		return nil, nil, ErrInvalidRefreshToken
+	}
+
+	if current.RotatedAt != nil {
+		if err := s.sessionRepo.RevokeFamily(current.FamilyID); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, ErrRefreshTokenReused
+	}
+
+	if time.Now().After(current.ExpiresAt) {
+		return nil, nil, ErrInvalidRefreshToken
+	}
+
+	user, err := s.userRepo.FindByID(current.UserID.String())
+	if err != nil || user.DeactivatedAt != nil {
+		return nil, nil, ErrInvalidRefreshToken
+	}
+
+	tokens, next, err := s.issueTokens(user, current.FamilyID, current.MFA)
 	if err != nil {
-		return "", nil, errors.New("failed to generate token")
+		return nil, nil, err
+	}
+
+	if err := s.sessionRepo.Rotate(current, next); err != nil {
+		// Lost a race with another exchange of the same token.
+		if revokeErr := s.sessionRepo.RevokeFamily(current.FamilyID); revokeErr != nil {
+			return nil, nil, revokeErr
+		}
+		return nil, nil, ErrRefreshTokenReused
+	}
+
+	return tokens, user, nil
+}
+
+// Logout revokes the access token in claims and the session of refreshToken,
+// or every session of the user when allSessions is set.
+func (s *authService) Logout(claims *util.JWTClaims, refreshToken string, allSessions bool) error {
+	if allSessions {
+		if err := s.sessionRepo.RevokeUser(claims.UserID); err != nil {
+			return err
+		}
+	} else if refreshToken != "" {
+		session, err := s.sessionRepo.FindByHash(util.HashToken(refreshToken))
+		if err == nil && session.UserID.String() == claims.UserID {
+			if err := s.sessionRepo.RevokeFamily(session.FamilyID); err != nil {
+				return err
+			}
+		}
+	}
+
+	return s.sessionRepo.RevokeAccessToken(&domain.RevokedToken{
+		JTI:       claims.ID,
+		UserID:    util.ParseUUID(claims.UserID),
+		ExpiresAt: claims.ExpiresAt.Time,
+	})
+}
+
+func (s *authService) IsTokenRevoked(jti string) (bool, error) {
+	return s.sessionRepo.IsAccessTokenRevoked(jti)
+}
+
+// UnlockAccount clears the failed-login counter and any lockout of the user's
+// account.
+func (s *authService) UnlockAccount(actor Actor, userID string) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+
+	subject := accountSubject(user.Email)
+	attempts, err := s.attemptRepo.Find(subject)
+	if err != nil {
+		return err
+	}
+
+	var before map[string]any
+	if len(attempts) > 0 {
+		before = map[string]any{"failures": attempts[0].Failures, "locked_until": attempts[0].LockedUntil}
+	}
+	audit := auditEntry(actor, domain.AuditUserUnlock, domain.AuditEntityUser, user.ID.String(),
+		before, map[string]any{"failures": 0})
+
+	if err := s.attemptRepo.Reset(subject, audit); err != nil {
+		return err
+	}
+
+	logger.Info("Login unlocked", "user_id", user.ID)
+
+	return nil
+}
+
+func (s *authService) checkLoginThrottle(subjects ...string) error {
+	attempts, err := s.attemptRepo.Find(subjects...)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var wait time.Duration
+	for _, attempt := range attempts {
+		if attempt.LockedUntil != nil && attempt.LockedUntil.After(now) {
+			wait = max(wait, attempt.LockedUntil.Sub(now))
+			continue
+		}
+
+		if now.Sub(attempt.LastFailureAt) > loginFailureWindow {
+			continue
+		}
+
+		if next := attempt.LastFailureAt.Add(loginDelay(attempt.Failures)); next.After(now) {
+			wait = max(wait, next.Sub(now))
+		}
+	}
+
+	if wait > 0 {
+		return &LoginThrottledError{RetryAfter: wait}
+	}
+
+	return nil
+}
+
+// lockSubject is a throttled subject and the audit entity its lockout is
+// recorded under. The audit log is append-only, so it is keyed by user ID or
+// a keyed hash rather than by the email in the subject.
+type lockSubject struct {
+	key        string
+	threshold  int
+	entityType string
+	entityID   string
+}
+
+// userLock throttles an existing account, or another per-user subject.
+func userLock(key, userID string) lockSubject {
+	return lockSubject{key: key, threshold: accountLockThreshold, entityType: domain.AuditEntityUser, entityID: userID}
+}
+
+// unknownAccountLock throttles an email that matches no account.
+func (s *authService) unknownAccountLock(email string) lockSubject {
+	return lockSubject{
+		key:        accountSubject(email),
+		threshold:  accountLockThreshold,
+		entityType: domain.AuditEntityLogin,
+		entityID:   "account:" + util.KeyedHash(s.opts.AuditHashKey, normalizeEmail(email)),
+	}
+}
+
+func ipLock(key string) lockSubject {
+	return lockSubject{key: key, threshold: ipLockThreshold, entityType: domain.AuditEntityLogin, entityID: auditSubject(key)}
+}
+
+// loginFailed counts a failed attempt against the account (or other per-user
+// subject) and the client, locking and auditing either one that reaches its
+// threshold. It returns cause unless recording the failure fails.
+func (s *authService) loginFailed(cause error, clientIP string, subjects ...lockSubject) error {
+	for _, subject := range subjects {
+		attempt, err := s.attemptRepo.RecordFailure(subject.key, loginFailureWindow)
+		if err != nil {
+			return err
+		}
+
+		if attempt.Failures < subject.threshold {
+			continue
+		}
+
+		until := time.Now().Add(loginLockDuration)
+		audit := auditEntry(Actor{IP: clientIP}, domain.AuditLoginLock, subject.entityType, subject.entityID,
+			nil, map[string]any{"failures": attempt.Failures, "locked_until": until})
+		if err := s.attemptRepo.Lock(subject.key, until, audit); err != nil {
+			return err
+		}
+
+		logger.Warn("Login locked", "entity_type", subject.entityType, "entity_id", subject.entityID,
+			"failures", attempt.Failures, "locked_until", until)
+	}
+
+	return cause
+}
+
+// loginDelay is how long a subject with the given number of recent failures
+// must wait after the last one before trying again.
+func loginDelay(failures int) time.Duration {
+	if failures < loginFreeAttempts {
+		return 0
+	}
+
+	shift := failures - loginFreeAttempts
+	if shift >= 5 {
+		return loginMaxDelay
+	}
+
+	return min(time.Second<<shift, loginMaxDelay)
+}
+
+func accountSubject(email string) string {
+	return "account:" + normalizeEmail(email)
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// auditSubject fits a throttled subject into an audit entity ID. Client
+// addresses can come from forwarded headers, so a subject can be any length.
+func auditSubject(subject string) string {
+	if len(subject) <= maxAuditEntityID {
+		return subject
+	}
+
+	return strings.ToValidUTF8(subject[:maxAuditEntityID], "")
+}
+
+// startSession issues the first token pair of a new session.
+func (s *authService) startSession(user *domain.User, mfa bool) (*LoginResult, error) {
+	tokens, session, err := s.issueTokens(user, uuid.New(), mfa)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.sessionRepo.Create(session); err != nil {
+		return nil, errors.New("failed to create session")
+	}
+
+	return &LoginResult{User: user, Tokens: tokens}, nil
+}
+
+// issueTokens creates an access token for user and a refresh token in the
+// given family, returning the session record to store for the latter.
+func (s *authService) issueTokens(user *domain.User, familyID uuid.UUID, mfa bool) (*AuthTokens, *domain.RefreshToken, error) {
+	accessToken, claims, err := util.GenerateJWT(user.ID.String(), user.Role, mfa, s.opts.AccessTTL)
+	if err != nil {
+		return nil, nil, errors.New("failed to generate token")
+	}
+
+	refreshToken, err := util.GenerateOpaqueToken()
+	if err != nil {
+		return nil, nil, errors.New("failed to generate token")
+	}
+
+	session := &domain.RefreshToken{
+		UserID:          user.ID,
+		FamilyID:        familyID,
+		TokenHash:       util.HashToken(refreshToken),
+		AccessJTI:       claims.ID,
+		AccessExpiresAt: claims.ExpiresAt.Time,
+		MFA:             mfa,
+		ExpiresAt:       time.Now().Add(s.opts.RefreshTTL),
+	}
+
+	tokens := &AuthTokens{
+		AccessToken:      accessToken,
+		AccessExpiresAt:  session.AccessExpiresAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: session.ExpiresAt,
 	}
 
-	return token, user, nil
+	return tokens, session, nil
 }
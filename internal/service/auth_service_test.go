@@ -3,19 +3,30 @@ package service
 import (
 	"errors"
 	"hotel-booking-api/internal/domain"
+	"hotel-booking-api/internal/repository"
+	"hotel-booking-api/pkg/util"
 	"hotel-booking-api/pkg/validator"
+	"strings"
 	"testing"
+	"time"
+	"unicode/utf8"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
 )
 
 type MockUserRepository struct {
 	mock.Mock
 }
 
-func (m *MockUserRepository) Create(user *domain.User) error {
-	args := m.Called(user)
+func (m *MockUserRepository) WithTx(tx *gorm.DB) repository.UserRepository {
+	return m
+}
+
+func (m *MockUserRepository) Create(user *domain.User, audit *domain.AuditLog) error {
+	args := m.Called(user, audit)
 	return args.Error(0)
 }
 
@@ -37,15 +48,168 @@ func (m *MockUserRepository) FindByID(id string) (*domain.User, error) {
 	return args.Get(0).(*domain.User), args.Error(1)
 }
 
+func (m *MockUserRepository) MarkEmailVerified(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UpdatePassword(id, hash string) error {
+	args := m.Called(id, hash)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) SetTOTPSecret(id, secret string) error {
+	args := m.Called(id, secret)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) EnableTOTP(id string, step int64) error {
+	args := m.Called(id, step)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) DisableTOTP(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) AdvanceTOTPStep(id string, step int64) (bool, error) {
+	args := m.Called(id, step)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) List(filter repository.UserFilter) ([]domain.User, int64, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]domain.User), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockUserRepository) CountActiveAdmins() (int64, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockUserRepository) UpdateRole(id, role string, audit *domain.AuditLog) error {
+	args := m.Called(id, role, audit)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) SetDeactivated(id string, at *time.Time, audit *domain.AuditLog) error {
+	args := m.Called(id, at, audit)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UpdateProfile(user *domain.User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) SetPendingEmail(id, email string) error {
+	args := m.Called(id, email)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) ChangeEmail(id, email string) error {
+	args := m.Called(id, email)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) Anonymize(id string, at time.Time) error {
+	args := m.Called(id, at)
+	return args.Error(0)
+}
+
+var testAuthOptions = AuthOptions{
+	AccessTTL:    15 * time.Minute,
+	RefreshTTL:   time.Hour,
+	TOTPIssuer:   "Hotel Booking",
+	AuditHashKey: []byte("audit-hash-key"),
+}
+
+type MockSessionRepository struct {
+	mock.Mock
+}
+
+func (m *MockSessionRepository) Create(token *domain.RefreshToken) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) FindByHash(hash string) (*domain.RefreshToken, error) {
+	args := m.Called(hash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.RefreshToken), args.Error(1)
+}
+
+func (m *MockSessionRepository) Rotate(current, next *domain.RefreshToken) error {
+	args := m.Called(current, next)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) RevokeFamily(familyID uuid.UUID) error {
+	args := m.Called(familyID)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) RevokeUser(userID string) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) RevokeAccessToken(token *domain.RevokedToken) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) IsAccessTokenRevoked(jti string) (bool, error) {
+	args := m.Called(jti)
+	return args.Bool(0), args.Error(1)
+}
+
+type MockLoginAttemptRepository struct {
+	mock.Mock
+}
+
+func (m *MockLoginAttemptRepository) Find(subjects ...string) ([]domain.LoginAttempt, error) {
+	called := make([]any, len(subjects))
+	for i, subject := range subjects {
+		called[i] = subject
+	}
+
+	args := m.Called(called...)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]domain.LoginAttempt), args.Error(1)
+}
+
+func (m *MockLoginAttemptRepository) RecordFailure(subject string, window time.Duration) (*domain.LoginAttempt, error) {
+	args := m.Called(subject, window)
+	return args.Get(0).(*domain.LoginAttempt), args.Error(1)
+}
+
+func (m *MockLoginAttemptRepository) Lock(subject string, until time.Time, audit *domain.AuditLog) error {
+	args := m.Called(subject, until, audit)
+	return args.Error(0)
+}
+
+func (m *MockLoginAttemptRepository) Reset(subject string, audit *domain.AuditLog) error {
+	args := m.Called(subject, audit)
+	return args.Error(0)
+}
+
 func TestAuthService_Register_Success(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	validate := validator.New()
-	service := NewAuthService(mockRepo, validate)
+	service := NewAuthService(mockRepo, new(MockSessionRepository), new(MockLoginAttemptRepository), new(MockRecoveryCodeRepository), validate, testAuthOptions)
 
 	mockRepo.On("FindByEmail", "test@example.com").Return(nil, errors.New("not found"))
-	mockRepo.On("Create", mock.AnythingOfType("*domain.User")).Return(nil)
+	mockRepo.On("Create", mock.AnythingOfType("*domain.User"), (*domain.AuditLog)(nil)).Return(nil)
 
-	user, err := service.Register("Test User", "test@example.com", "password123", "CUSTOMER")
+	user, err := service.Register("Test User", "test@example.com", "password123")
 
 	assert.NoError(t, err)
 	assert.NotNil(t, user)
@@ -58,12 +222,12 @@ func TestAuthService_Register_Success(t *testing.T) {
 func TestAuthService_Register_EmailExists(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	validate := validator.New()
-	service := NewAuthService(mockRepo, validate)
+	service := NewAuthService(mockRepo, new(MockSessionRepository), new(MockLoginAttemptRepository), new(MockRecoveryCodeRepository), validate, testAuthOptions)
 
 	existingUser := &domain.User{Email: "test@example.com"}
 	mockRepo.On("FindByEmail", "test@example.com").Return(existingUser, nil)
 
-	user, err := service.Register("Test User", "test@example.com", "password123", "CUSTOMER")
+	user, err := service.Register("Test User", "test@example.com", "password123")
 
 	assert.Error(t, err)
 	assert.Nil(t, user)
@@ -74,9 +238,9 @@ func TestAuthService_Register_EmailExists(t *testing.T) {
 func TestAuthService_Register_InvalidEmail(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	validate := validator.New()
-	service := NewAuthService(mockRepo, validate)
+	service := NewAuthService(mockRepo, new(MockSessionRepository), new(MockLoginAttemptRepository), new(MockRecoveryCodeRepository), validate, testAuthOptions)
 
-	user, err := service.Register("Test User", "invalid-email", "password123", "CUSTOMER")
+	user, err := service.Register("Test User", "invalid-email", "password123")
 
 	assert.Error(t, err)
 	assert.Nil(t, user)
@@ -86,9 +250,9 @@ func TestAuthService_Register_InvalidEmail(t *testing.T) {
 func TestAuthService_Register_ShortPassword(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	validate := validator.New()
-	service := NewAuthService(mockRepo, validate)
+	service := NewAuthService(mockRepo, new(MockSessionRepository), new(MockLoginAttemptRepository), new(MockRecoveryCodeRepository), validate, testAuthOptions)
 
-	user, err := service.Register("Test User", "test@example.com", "12345", "CUSTOMER")
+	user, err := service.Register("Test User", "test@example.com", "12345")
 
 	assert.Error(t, err)
 	assert.Nil(t, user)
@@ -97,8 +261,9 @@ func TestAuthService_Register_ShortPassword(t *testing.T) {
 
 func TestAuthService_Login_Success(t *testing.T) {
 	mockRepo := new(MockUserRepository)
+	mockAttempts := new(MockLoginAttemptRepository)
 	validate := validator.New()
-	service := NewAuthService(mockRepo, validate)
+	service := NewAuthService(mockRepo, new(MockSessionRepository), mockAttempts, new(MockRecoveryCodeRepository), validate, testAuthOptions)
 
 	hashedPassword := "$2a$14$..." // Mock hash
 	existingUser := &domain.User{
@@ -107,28 +272,271 @@ func TestAuthService_Login_Success(t *testing.T) {
 		Role:     "CUSTOMER",
 	}
 
+	mockAttempts.On("Find", "account:test@example.com", "ip:10.0.0.1").Return(nil, nil)
 	mockRepo.On("FindByEmail", "test@example.com").Return(existingUser, nil)
+	mockAttempts.On("RecordFailure", mock.AnythingOfType("string"), loginFailureWindow).
+		Return(&domain.LoginAttempt{Failures: 1}, nil)
 
-	token, user, err := service.Login("test@example.com", "wrongpassword")
+	result, err := service.Login("test@example.com", "wrongpassword", "10.0.0.1")
 
-	assert.Error(t, err) // Will error due to password mismatch
-	assert.Empty(t, token)
-	assert.Nil(t, user)
+	assert.ErrorIs(t, err, ErrInvalidCredentials) // Will error due to password mismatch
+	assert.Nil(t, result)
 	mockRepo.AssertExpectations(t)
 }
 
 func TestAuthService_Login_UserNotFound(t *testing.T) {
 	mockRepo := new(MockUserRepository)
+	mockAttempts := new(MockLoginAttemptRepository)
 	validate := validator.New()
-	service := NewAuthService(mockRepo, validate)
+	service := NewAuthService(mockRepo, new(MockSessionRepository), mockAttempts, new(MockRecoveryCodeRepository), validate, testAuthOptions)
 
+	mockAttempts.On("Find", "account:notfound@example.com", "ip:10.0.0.1").Return(nil, nil)
 	mockRepo.On("FindByEmail", "notfound@example.com").Return(nil, errors.New("not found"))
+	mockAttempts.On("RecordFailure", "account:notfound@example.com", loginFailureWindow).
+		Return(&domain.LoginAttempt{Failures: 1}, nil)
+	mockAttempts.On("RecordFailure", "ip:10.0.0.1", loginFailureWindow).
+		Return(&domain.LoginAttempt{Failures: 1}, nil)
 
-	token, user, err := service.Login("notfound@example.com", "password123")
+	result, err := service.Login("notfound@example.com", "password123", "10.0.0.1")
 
 	assert.Error(t, err)
-	assert.Equal(t, "user not found", err.Error())
-	assert.Empty(t, token)
-	assert.Nil(t, user)
+	assert.Equal(t, "invalid email or password", err.Error())
+	assert.Nil(t, result)
 	mockRepo.AssertExpectations(t)
+	mockAttempts.AssertExpectations(t)
+}
+
+func TestAuthService_Login_Deactivated(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockAttempts := new(MockLoginAttemptRepository)
+	service := NewAuthService(mockRepo, new(MockSessionRepository), mockAttempts, new(MockRecoveryCodeRepository), validator.New(), testAuthOptions)
+
+	hash, _ := util.HashPassword("password123")
+	deactivatedAt := time.Now()
+	existingUser := &domain.User{
+		Email:         "test@example.com",
+		Password:      hash,
+		Role:          "CUSTOMER",
+		DeactivatedAt: &deactivatedAt,
+	}
+
+	mockAttempts.On("Find", "account:test@example.com", "ip:10.0.0.1").Return(nil, nil)
+	mockRepo.On("FindByEmail", "test@example.com").Return(existingUser, nil)
+
+	result, err := service.Login("test@example.com", "password123", "10.0.0.1")
+
+	assert.ErrorIs(t, err, ErrAccountDeactivated)
+	assert.Nil(t, result)
+	mockAttempts.AssertNotCalled(t, "Reset", mock.Anything, mock.Anything)
+}
+
+func TestAuthService_Login_LocksAccountAtThreshold(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockAttempts := new(MockLoginAttemptRepository)
+	service := NewAuthService(mockRepo, new(MockSessionRepository), mockAttempts, new(MockRecoveryCodeRepository), validator.New(), testAuthOptions)
+
+	mockAttempts.On("Find", "account:test@example.com", "ip:10.0.0.1").Return(nil, nil)
+	mockRepo.On("FindByEmail", "Test@Example.com ").Return(nil, errors.New("not found"))
+	mockAttempts.On("RecordFailure", "account:test@example.com", loginFailureWindow).
+		Return(&domain.LoginAttempt{Failures: accountLockThreshold}, nil)
+	mockAttempts.On("RecordFailure", "ip:10.0.0.1", loginFailureWindow).
+		Return(&domain.LoginAttempt{Failures: accountLockThreshold}, nil)
+	mockAttempts.On("Lock", "account:test@example.com", mock.AnythingOfType("time.Time"), mock.AnythingOfType("*domain.AuditLog")).Return(nil)
+
+	_, err := service.Login("Test@Example.com ", "password123", "10.0.0.1")
+
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	mockAttempts.AssertExpectations(t)
+	mockAttempts.AssertNotCalled(t, "Lock", "ip:10.0.0.1", mock.Anything, mock.Anything)
+
+	var audit *domain.AuditLog
+	for _, call := range mockAttempts.Calls {
+		if call.Method == "Lock" {
+			audit = call.Arguments.Get(2).(*domain.AuditLog)
+		}
+	}
+	assert.Equal(t, domain.AuditLoginLock, audit.Action)
+	assert.Equal(t, domain.AuditEntityLogin, audit.EntityType)
+	assert.Equal(t, "account:"+util.KeyedHash(testAuthOptions.AuditHashKey, "test@example.com"), audit.EntityID)
+	assert.NotContains(t, audit.EntityID, "example.com")
+	assert.Equal(t, "10.0.0.1", audit.IP)
+	assert.Nil(t, audit.ActorID)
+	assert.Equal(t, float64(accountLockThreshold), audit.Changes["failures"].To)
+}
+
+func TestAuthService_Login_LockAuditKeyedByUserID(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockAttempts := new(MockLoginAttemptRepository)
+	service := NewAuthService(mockRepo, new(MockSessionRepository), mockAttempts, new(MockRecoveryCodeRepository), validator.New(), testAuthOptions)
+
+	hash, _ := util.HashPassword("password123")
+	user := &domain.User{ID: uuid.New(), Email: "test@example.com", Password: hash}
+
+	mockAttempts.On("Find", "account:test@example.com", "ip:10.0.0.1").Return(nil, nil)
+	mockRepo.On("FindByEmail", "test@example.com").Return(user, nil)
+	mockAttempts.On("RecordFailure", "account:test@example.com", loginFailureWindow).
+		Return(&domain.LoginAttempt{Failures: accountLockThreshold}, nil)
+	mockAttempts.On("RecordFailure", "ip:10.0.0.1", loginFailureWindow).
+		Return(&domain.LoginAttempt{Failures: 1}, nil)
+	mockAttempts.On("Lock", "account:test@example.com", mock.AnythingOfType("time.Time"), mock.AnythingOfType("*domain.AuditLog")).Return(nil)
+
+	_, err := service.Login("test@example.com", "wrong-password", "10.0.0.1")
+
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	mockAttempts.AssertExpectations(t)
+
+	var audit *domain.AuditLog
+	for _, call := range mockAttempts.Calls {
+		if call.Method == "Lock" {
+			audit = call.Arguments.Get(2).(*domain.AuditLog)
+		}
+	}
+	assert.Equal(t, domain.AuditEntityUser, audit.EntityType)
+	assert.Equal(t, user.ID.String(), audit.EntityID)
+}
+
+func TestAuthService_Login_LockAuditFitsLongSubjects(t *testing.T) {
+	subject := "ip:" + strings.Repeat("é", 60)
+
+	id := auditSubject(subject)
+
+	assert.LessOrEqual(t, len(id), maxAuditEntityID)
+	assert.True(t, utf8.ValidString(id))
+	assert.True(t, strings.HasPrefix(subject, id))
+	assert.Equal(t, "ip:10.0.0.1", auditSubject("ip:10.0.0.1"))
+}
+
+func TestAuthService_UnlockAccount_Audited(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockAttempts := new(MockLoginAttemptRepository)
+	service := NewAuthService(mockRepo, new(MockSessionRepository), mockAttempts, new(MockRecoveryCodeRepository), validator.New(), testAuthOptions)
+
+	user := &domain.User{ID: uuid.New(), Email: "test@example.com"}
+	lockedUntil := time.Now().Add(10 * time.Minute)
+	admin := Actor{UserID: uuid.NewString(), Role: domain.RoleAdmin, RequestID: "req-1", IP: "10.0.0.2"}
+
+	mockRepo.On("FindByID", user.ID.String()).Return(user, nil)
+	mockAttempts.On("Find", "account:test@example.com").Return([]domain.LoginAttempt{
+		{Subject: "account:test@example.com", Failures: accountLockThreshold, LockedUntil: &lockedUntil},
+	}, nil)
+	mockAttempts.On("Reset", "account:test@example.com", mock.AnythingOfType("*domain.AuditLog")).Return(nil)
+
+	err := service.UnlockAccount(admin, user.ID.String())
+
+	assert.NoError(t, err)
+	mockAttempts.AssertExpectations(t)
+
+	audit := mockAttempts.Calls[1].Arguments.Get(1).(*domain.AuditLog)
+	assert.Equal(t, domain.AuditUserUnlock, audit.Action)
+	assert.Equal(t, domain.AuditEntityUser, audit.EntityType)
+	assert.Equal(t, user.ID.String(), audit.EntityID)
+	assert.Equal(t, admin.UserID, audit.ActorID.String())
+	assert.Equal(t, domain.RoleAdmin, audit.ActorRole)
+	assert.Equal(t, "req-1", audit.RequestID)
+	assert.Equal(t, domain.AuditChange{From: float64(accountLockThreshold), To: float64(0)}, audit.Changes["failures"])
+	assert.Contains(t, audit.Changes, "locked_until")
+}
+
+func TestAuthService_Login_Throttled(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockAttempts := new(MockLoginAttemptRepository)
+	service := NewAuthService(mockRepo, new(MockSessionRepository), mockAttempts, new(MockRecoveryCodeRepository), validator.New(), testAuthOptions)
+
+	lockedUntil := time.Now().Add(10 * time.Minute)
+	mockAttempts.On("Find", "account:test@example.com", "ip:10.0.0.1").Return([]domain.LoginAttempt{
+		{Subject: "account:test@example.com", Failures: accountLockThreshold, LastFailureAt: time.Now(), LockedUntil: &lockedUntil},
+		{Subject: "ip:10.0.0.1", Failures: 5, LastFailureAt: time.Now()},
+	}, nil)
+
+	_, err := service.Login("test@example.com", "password123", "10.0.0.1")
+
+	var throttled *LoginThrottledError
+	assert.ErrorAs(t, err, &throttled)
+	assert.ErrorIs(t, err, ErrTooManyLoginAttempts)
+	assert.InDelta(t, (10 * time.Minute).Seconds(), throttled.RetryAfter.Seconds(), 1)
+	mockRepo.AssertNotCalled(t, "FindByEmail", mock.Anything)
+}
+
+func TestLoginDelay(t *testing.T) {
+	assert.Equal(t, time.Duration(0), loginDelay(loginFreeAttempts-1))
+	assert.Equal(t, time.Second, loginDelay(loginFreeAttempts))
+	assert.Equal(t, 4*time.Second, loginDelay(loginFreeAttempts+2))
+	assert.Equal(t, loginMaxDelay, loginDelay(loginFreeAttempts+5))
+	assert.Equal(t, loginMaxDelay, loginDelay(100))
+}
+
+func TestAuthService_Refresh_RotatesToken(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	mockRepo := new(MockUserRepository)
+	mockSessions := new(MockSessionRepository)
+	service := NewAuthService(mockRepo, mockSessions, new(MockLoginAttemptRepository), new(MockRecoveryCodeRepository), validator.New(), testAuthOptions)
+
+	user := &domain.User{ID: uuid.New(), Role: "CUSTOMER"}
+	current := &domain.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		FamilyID:  uuid.New(),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	mockSessions.On("FindByHash", util.HashToken("refresh-token")).Return(current, nil)
+	mockRepo.On("FindByID", user.ID.String()).Return(user, nil)
+	mockSessions.On("Rotate", current, mock.MatchedBy(func(next *domain.RefreshToken) bool {
+		return next.FamilyID == current.FamilyID && next.TokenHash != util.HashToken("refresh-token")
+	})).Return(nil)
+
+	tokens, refreshed, err := service.Refresh("refresh-token")
+
+	assert.NoError(t, err)
+	assert.Equal(t, user, refreshed)
+	assert.NotEmpty(t, tokens.AccessToken)
+	assert.NotEqual(t, "refresh-token", tokens.RefreshToken)
+	mockRepo.AssertExpectations(t)
+	mockSessions.AssertExpectations(t)
+}
+
+func TestAuthService_Refresh_ReuseRevokesFamily(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockSessions := new(MockSessionRepository)
+	service := NewAuthService(mockRepo, mockSessions, new(MockLoginAttemptRepository), new(MockRecoveryCodeRepository), validator.New(), testAuthOptions)
+
+	rotatedAt := time.Now().Add(-time.Minute)
+	current := &domain.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    uuid.New(),
+		FamilyID:  uuid.New(),
+		ExpiresAt: time.Now().Add(time.Hour),
+		RotatedAt: &rotatedAt,
+	}
+
+	mockSessions.On("FindByHash", util.HashToken("refresh-token")).Return(current, nil)
+	mockSessions.On("RevokeFamily", current.FamilyID).Return(nil)
+
+	tokens, user, err := service.Refresh("refresh-token")
+
+	assert.ErrorIs(t, err, ErrRefreshTokenReused)
+	assert.Nil(t, tokens)
+	assert.Nil(t, user)
+	mockSessions.AssertExpectations(t)
+}
+
+func TestAuthService_Refresh_Expired(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockSessions := new(MockSessionRepository)
+	service := NewAuthService(mockRepo, mockSessions, new(MockLoginAttemptRepository), new(MockRecoveryCodeRepository), validator.New(), testAuthOptions)
+
+	current := &domain.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    uuid.New(),
+		FamilyID:  uuid.New(),
+		ExpiresAt: time.Now().Add(-time.Minute),
+	}
+
+	mockSessions.On("FindByHash", util.HashToken("refresh-token")).Return(current, nil)
+
+	_, _, err := service.Refresh("refresh-token")
+
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+	mockSessions.AssertExpectations(t)
 }
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"errors"
+	"hotel-booking-api/internal/domain"
+	"hotel-booking-api/pkg/logger"
+	"hotel-booking-api/pkg/totp"
+	"hotel-booking-api/pkg/util"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidChallenge     = errors.New("invalid or expired two-factor challenge")
+	ErrInvalidTwoFactorCode = errors.New("invalid two-factor code")
+	ErrTwoFactorEnabled     = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnabled  = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorNotSetUp    = errors.New("two-factor authentication has not been set up")
+	ErrTwoFactorRequired    = errors.New("two-factor authentication is required for admin accounts")
+	ErrIncorrectPassword    = errors.New("incorrect password")
+)
+
+const (
+	twoFactorChallengeTTL = 5 * time.Minute
+	// totpSkew accepts codes from one step either side of the current one
+	// to allow for clock drift.
+	totpSkew          = 1
+	recoveryCodeCount = 10
+)
+
+// TwoFactorChallenge is handed out after a correct password on an account
+// with two-factor authentication.
+type TwoFactorChallenge struct {
+	Token     string
+	ExpiresAt time.Time
+}
+
+// TwoFactorSetup is a pending TOTP secret and the URI to enrol it in an
+// authenticator app.
+type TwoFactorSetup struct {
+	Secret          string
+	ProvisioningURI string
+}
+
+// VerifyTwoFactor completes a login that returned a challenge. code is either
+// the current TOTP code or an unused recovery code.
+func (s *authService) VerifyTwoFactor(challengeToken, code, clientIP string) (*LoginResult, error) {
+	userID, err := util.ParseTwoFactorChallenge(challengeToken)
+	if err != nil {
+		return nil, ErrInvalidChallenge
+	}
+
+	subject := "2fa:" + userID
+	ipKey := "ip:" + clientIP
+
+	if err := s.checkLoginThrottle(subject, ipKey); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil || user.TOTPEnabledAt == nil || user.DeactivatedAt != nil {
+		return nil, ErrInvalidChallenge
+	}
+
+	ok, err := s.checkSecondFactor(user, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, s.loginFailed(ErrInvalidTwoFactorCode, clientIP, userLock(subject, userID), ipLock(ipKey))
+	}
+
+	if err := s.attemptRepo.Reset(subject, nil); err != nil {
+		return nil, err
+	}
+
+	return s.startSession(user, true)
+}
+
+// SetupTwoFactor generates a new secret for the user. It only takes effect
+// once confirmed with EnableTwoFactor.
+func (s *authService) SetupTwoFactor(userID string) (*TwoFactorSetup, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	if user.TOTPEnabledAt != nil {
+		return nil, ErrTwoFactorEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, errors.New("failed to generate secret")
+	}
+
+	if err := s.userRepo.SetTOTPSecret(userID, secret); err != nil {
+		return nil, err
+	}
+
+	return &TwoFactorSetup{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(secret, s.opts.TOTPIssuer, user.Email),
+	}, nil
+}
+
+// EnableTwoFactor turns on two-factor authentication once the user proves
+// their authenticator produces codes for the pending secret. It returns the
+// recovery codes, which are not shown again.
+func (s *authService) EnableTwoFactor(userID, code string) ([]string, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	if user.TOTPEnabledAt != nil {
+		return nil, ErrTwoFactorEnabled
+	}
+	if user.TOTPSecret == "" {
+		return nil, ErrTwoFactorNotSetUp
+	}
+
+	step, ok := totp.Validate(user.TOTPSecret, normaliseCode(code), s.now(), totpSkew)
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	if err := s.userRepo.EnableTOTP(userID, step); err != nil {
+		return nil, err
+	}
+
+	return s.replaceRecoveryCodes(user)
+}
+
+// DisableTwoFactor turns two-factor authentication off. It asks for the
+// password and a second factor so a stolen session alone cannot do it.
+func (s *authService) DisableTwoFactor(userID, password, code string) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+
+	if user.TOTPEnabledAt == nil {
+		return ErrTwoFactorNotEnabled
+	}
+	if user.Role == domain.RoleAdmin && s.opts.RequireAdminTwoFactor {
+		return ErrTwoFactorRequired
+	}
+	if !util.CheckPassword(password, user.Password) {
+		return ErrIncorrectPassword
+	}
+
+	ok, err := s.checkSecondFactor(user, code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidTwoFactorCode
+	}
+
+	return s.clearTwoFactor(userID)
+}
+
+// RegenerateRecoveryCodes replaces the user's recovery codes after checking a
+// second factor.
+func (s *authService) RegenerateRecoveryCodes(userID, code string) ([]string, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	if user.TOTPEnabledAt == nil {
+		return nil, ErrTwoFactorNotEnabled
+	}
+
+	ok, err := s.checkSecondFactor(user, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	return s.replaceRecoveryCodes(user)
+}
+
+// ResetTwoFactor lets an administrator remove two-factor authentication from
+// an account whose owner lost both their authenticator and recovery codes.
+// The user is signed out everywhere.
+func (s *authService) ResetTwoFactor(userID string) error {
+	if _, err := s.userRepo.FindByID(userID); err != nil {
+		return errors.New("user not found")
+	}
+
+	if err := s.clearTwoFactor(userID); err != nil {
+		return err
+	}
+
+	if err := s.sessionRepo.RevokeUser(userID); err != nil {
+		return err
+	}
+
+	logger.Info("Two-factor authentication reset", "user_id", userID)
+
+	return nil
+}
+
+func (s *authService) newTwoFactorChallenge(user *domain.User) (*TwoFactorChallenge, error) {
+	expiresAt := time.Now().Add(twoFactorChallengeTTL)
+
+	token, err := util.GenerateTwoFactorChallenge(user.ID.String(), expiresAt)
+	if err != nil {
+		return nil, errors.New("failed to generate token")
+	}
+
+	return &TwoFactorChallenge{Token: token, ExpiresAt: expiresAt}, nil
+}
+
+// checkSecondFactor accepts a TOTP code that has not been used before or an
+// unused recovery code, consuming it.
+func (s *authService) checkSecondFactor(user *domain.User, code string) (bool, error) {
+	code = normaliseCode(code)
+
+	if len(code) == totp.Digits {
+		step, ok := totp.Validate(user.TOTPSecret, code, s.now(), totpSkew)
+		if !ok {
+			return false, nil
+		}
+		return s.userRepo.AdvanceTOTPStep(user.ID.String(), step)
+	}
+
+	return s.recoveryRepo.Consume(user.ID.String(), util.HashToken(code))
+}
+
+func (s *authService) replaceRecoveryCodes(user *domain.User) ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		code, err := util.GenerateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = code
+		hashes[i] = util.HashToken(normaliseCode(codes[i]))
+	}
+
+	if err := s.recoveryRepo.Replace(user.ID, hashes); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+func (s *authService) clearTwoFactor(userID string) error {
+	if err := s.userRepo.DisableTOTP(userID); err != nil {
+		return err
+	}
+
+	return s.recoveryRepo.DeleteAll(userID)
+}
+
+// normaliseCode strips the spaces and dashes users type into codes and
+// upper-cases recovery codes.
+func normaliseCode(code string) string {
+	return strings.ToUpper(strings.NewReplacer(" ", "", "-", "").Replace(code))
+}
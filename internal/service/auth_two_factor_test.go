@@ -0,0 +1,257 @@
+package service
+
+import (
+	"hotel-booking-api/internal/domain"
+	"hotel-booking-api/pkg/totp"
+	"hotel-booking-api/pkg/util"
+	"hotel-booking-api/pkg/validator"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type MockRecoveryCodeRepository struct {
+	mock.Mock
+}
+
+func (m *MockRecoveryCodeRepository) Replace(userID uuid.UUID, hashes []string) error {
+	args := m.Called(userID, hashes)
+	return args.Error(0)
+}
+
+func (m *MockRecoveryCodeRepository) Consume(userID, hash string) (bool, error) {
+	args := m.Called(userID, hash)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRecoveryCodeRepository) DeleteAll(userID string) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+// fixedNow is the clock used by the two-factor tests.
+var fixedNow = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+const testTOTPSecret = "JBSWY3DPEHPK3PXP"
+
+type twoFactorMocks struct {
+	users     *MockUserRepository
+	sessions  *MockSessionRepository
+	attempts  *MockLoginAttemptRepository
+	recovery  *MockRecoveryCodeRepository
+	service   *authService
+	enabledAt time.Time
+}
+
+func newTwoFactorTest(t *testing.T, opts AuthOptions) *twoFactorMocks {
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	m := &twoFactorMocks{
+		users:     new(MockUserRepository),
+		sessions:  new(MockSessionRepository),
+		attempts:  new(MockLoginAttemptRepository),
+		recovery:  new(MockRecoveryCodeRepository),
+		enabledAt: fixedNow.Add(-24 * time.Hour),
+	}
+	m.service = NewAuthService(m.users, m.sessions, m.attempts, m.recovery, validator.New(), opts).(*authService)
+	m.service.now = func() time.Time { return fixedNow }
+
+	return m
+}
+
+func (m *twoFactorMocks) user(t *testing.T, role string) *domain.User {
+	hash, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	assert.NoError(t, err)
+
+	return &domain.User{
+		ID:            uuid.New(),
+		Email:         "admin@example.com",
+		Password:      string(hash),
+		Role:          role,
+		TOTPSecret:    testTOTPSecret,
+		TOTPEnabledAt: &m.enabledAt,
+	}
+}
+
+func TestAuthService_Login_TwoFactorChallenge(t *testing.T) {
+	m := newTwoFactorTest(t, testAuthOptions)
+	user := m.user(t, domain.RoleAdmin)
+
+	m.attempts.On("Find", "account:admin@example.com", "ip:10.0.0.1").Return(nil, nil)
+	m.users.On("FindByEmail", "admin@example.com").Return(user, nil)
+	m.attempts.On("Reset", "account:admin@example.com", (*domain.AuditLog)(nil)).Return(nil)
+
+	result, err := m.service.Login("admin@example.com", "password123", "10.0.0.1")
+
+	assert.NoError(t, err)
+	assert.Nil(t, result.Tokens)
+	assert.WithinDuration(t, time.Now().Add(twoFactorChallengeTTL), result.Challenge.ExpiresAt, time.Second)
+
+	userID, err := util.ParseTwoFactorChallenge(result.Challenge.Token)
+	assert.NoError(t, err)
+	assert.Equal(t, user.ID.String(), userID)
+
+	_, err = util.ParseJWT(result.Challenge.Token)
+	assert.Error(t, err, "a challenge must not work as an access token")
+	m.sessions.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestAuthService_VerifyTwoFactor_TOTP(t *testing.T) {
+	m := newTwoFactorTest(t, testAuthOptions)
+	user := m.user(t, domain.RoleAdmin)
+	challenge, _ := util.GenerateTwoFactorChallenge(user.ID.String(), time.Now().Add(time.Minute))
+	code, _ := totp.Code(testTOTPSecret, fixedNow)
+
+	m.attempts.On("Find", "2fa:"+user.ID.String(), "ip:10.0.0.1").Return(nil, nil)
+	m.users.On("FindByID", user.ID.String()).Return(user, nil)
+	m.users.On("AdvanceTOTPStep", user.ID.String(), totp.Step(fixedNow)).Return(true, nil)
+	m.attempts.On("Reset", "2fa:"+user.ID.String(), (*domain.AuditLog)(nil)).Return(nil)
+	m.sessions.On("Create", mock.MatchedBy(func(s *domain.RefreshToken) bool { return s.MFA })).Return(nil)
+
+	result, err := m.service.VerifyTwoFactor(challenge, code[:3]+" "+code[3:], "10.0.0.1")
+
+	assert.NoError(t, err)
+	assert.Nil(t, result.Challenge)
+
+	claims, err := util.ParseJWT(result.Tokens.AccessToken)
+	assert.NoError(t, err)
+	assert.True(t, claims.MFA)
+	m.users.AssertExpectations(t)
+	m.sessions.AssertExpectations(t)
+}
+
+func TestAuthService_VerifyTwoFactor_ReplayedCode(t *testing.T) {
+	m := newTwoFactorTest(t, testAuthOptions)
+	user := m.user(t, domain.RoleAdmin)
+	challenge, _ := util.GenerateTwoFactorChallenge(user.ID.String(), time.Now().Add(time.Minute))
+	code, _ := totp.Code(testTOTPSecret, fixedNow)
+
+	m.attempts.On("Find", "2fa:"+user.ID.String(), "ip:10.0.0.1").Return(nil, nil)
+	m.users.On("FindByID", user.ID.String()).Return(user, nil)
+	m.users.On("AdvanceTOTPStep", user.ID.String(), totp.Step(fixedNow)).Return(false, nil)
+	m.attempts.On("RecordFailure", mock.AnythingOfType("string"), loginFailureWindow).
+		Return(&domain.LoginAttempt{Failures: 1}, nil)
+
+	result, err := m.service.VerifyTwoFactor(challenge, code, "10.0.0.1")
+
+	assert.ErrorIs(t, err, ErrInvalidTwoFactorCode)
+	assert.Nil(t, result)
+	m.attempts.AssertNumberOfCalls(t, "RecordFailure", 2)
+	m.sessions.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestAuthService_VerifyTwoFactor_RecoveryCode(t *testing.T) {
+	m := newTwoFactorTest(t, testAuthOptions)
+	user := m.user(t, domain.RoleStaff)
+	challenge, _ := util.GenerateTwoFactorChallenge(user.ID.String(), time.Now().Add(time.Minute))
+
+	m.attempts.On("Find", "2fa:"+user.ID.String(), "ip:10.0.0.1").Return(nil, nil)
+	m.users.On("FindByID", user.ID.String()).Return(user, nil)
+	m.recovery.On("Consume", user.ID.String(), util.HashToken("7QX2M9PAKD")).Return(true, nil)
+	m.attempts.On("Reset", "2fa:"+user.ID.String(), (*domain.AuditLog)(nil)).Return(nil)
+	m.sessions.On("Create", mock.AnythingOfType("*domain.RefreshToken")).Return(nil)
+
+	result, err := m.service.VerifyTwoFactor(challenge, "7qx2m-9pakd", "10.0.0.1")
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, result.Tokens.AccessToken)
+	m.recovery.AssertExpectations(t)
+}
+
+func TestAuthService_VerifyTwoFactor_InvalidChallenge(t *testing.T) {
+	m := newTwoFactorTest(t, testAuthOptions)
+	accessToken, _, _ := util.GenerateJWT(uuid.NewString(), domain.RoleAdmin, false, time.Minute)
+
+	_, err := m.service.VerifyTwoFactor(accessToken, "123456", "10.0.0.1")
+
+	assert.ErrorIs(t, err, ErrInvalidChallenge)
+}
+
+func TestAuthService_EnableTwoFactor(t *testing.T) {
+	m := newTwoFactorTest(t, testAuthOptions)
+	user := m.user(t, domain.RoleHotelManager)
+	user.TOTPEnabledAt = nil
+	code, _ := totp.Code(testTOTPSecret, fixedNow.Add(-totp.Period))
+
+	m.users.On("FindByID", user.ID.String()).Return(user, nil)
+	m.users.On("EnableTOTP", user.ID.String(), totp.Step(fixedNow)-1).Return(nil)
+	m.recovery.On("Replace", user.ID, mock.MatchedBy(func(hashes []string) bool {
+		return len(hashes) == recoveryCodeCount
+	})).Return(nil)
+
+	codes, err := m.service.EnableTwoFactor(user.ID.String(), code)
+
+	assert.NoError(t, err)
+	assert.Len(t, codes, recoveryCodeCount)
+	assert.Regexp(t, `^[A-Z2-9]{5}-[A-Z2-9]{5}$`, codes[0])
+
+	hashes := m.recovery.Calls[0].Arguments.Get(1).([]string)
+	assert.Equal(t, util.HashToken(normaliseCode(codes[0])), hashes[0])
+	m.users.AssertExpectations(t)
+}
+
+func TestAuthService_EnableTwoFactor_StaleCode(t *testing.T) {
+	m := newTwoFactorTest(t, testAuthOptions)
+	user := m.user(t, domain.RoleHotelManager)
+	user.TOTPEnabledAt = nil
+	code, _ := totp.Code(testTOTPSecret, fixedNow.Add(-5*time.Minute))
+
+	m.users.On("FindByID", user.ID.String()).Return(user, nil)
+
+	codes, err := m.service.EnableTwoFactor(user.ID.String(), code)
+
+	assert.ErrorIs(t, err, ErrInvalidTwoFactorCode)
+	assert.Nil(t, codes)
+	m.users.AssertNotCalled(t, "EnableTOTP", mock.Anything, mock.Anything)
+}
+
+func TestAuthService_SetupTwoFactor(t *testing.T) {
+	m := newTwoFactorTest(t, testAuthOptions)
+	user := m.user(t, domain.RoleAdmin)
+	user.TOTPEnabledAt = nil
+
+	m.users.On("FindByID", user.ID.String()).Return(user, nil)
+	m.users.On("SetTOTPSecret", user.ID.String(), mock.AnythingOfType("string")).Return(nil)
+
+	setup, err := m.service.SetupTwoFactor(user.ID.String())
+
+	assert.NoError(t, err)
+	assert.Contains(t, setup.ProvisioningURI, "secret="+setup.Secret)
+	assert.Contains(t, setup.ProvisioningURI, "admin@example.com")
+	m.users.AssertExpectations(t)
+}
+
+func TestAuthService_DisableTwoFactor_EnforcedForAdmin(t *testing.T) {
+	opts := testAuthOptions
+	opts.RequireAdminTwoFactor = true
+	m := newTwoFactorTest(t, opts)
+	user := m.user(t, domain.RoleAdmin)
+	code, _ := totp.Code(testTOTPSecret, fixedNow)
+
+	m.users.On("FindByID", user.ID.String()).Return(user, nil)
+
+	err := m.service.DisableTwoFactor(user.ID.String(), "password123", code)
+
+	assert.ErrorIs(t, err, ErrTwoFactorRequired)
+	m.users.AssertNotCalled(t, "DisableTOTP", mock.Anything)
+}
+
+func TestAuthService_DisableTwoFactor(t *testing.T) {
+	m := newTwoFactorTest(t, testAuthOptions)
+	user := m.user(t, domain.RoleStaff)
+	code, _ := totp.Code(testTOTPSecret, fixedNow)
+
+	m.users.On("FindByID", user.ID.String()).Return(user, nil)
+	m.users.On("AdvanceTOTPStep", user.ID.String(), totp.Step(fixedNow)).Return(true, nil)
+	m.users.On("DisableTOTP", user.ID.String()).Return(nil)
+	m.recovery.On("DeleteAll", user.ID.String()).Return(nil)
+
+	assert.ErrorIs(t, m.service.DisableTwoFactor(user.ID.String(), "wrong-password", code), ErrIncorrectPassword)
+	assert.NoError(t, m.service.DisableTwoFactor(user.ID.String(), "password123", code))
+	m.users.AssertExpectations(t)
+	m.recovery.AssertExpectations(t)
+}
@@ -4,16 +4,57 @@ import (
 	"errors"
 	"hotel-booking-api/internal/domain"
 	"hotel-booking-api/internal/repository"
+	"hotel-booking-api/pkg/pagination"
 	"hotel-booking-api/pkg/util"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
 )
 
+var (
+	ErrBookingNotFound  = errors.New("booking not found")
+	ErrBookingForbidden = errors.New("you do not have access to this booking")
+)
+
+// manageLinkGrace keeps a guest's manage-booking link usable for a while
+// after check-out so the stay can still be looked up.
+const manageLinkGrace = 7 * 24 * time.Hour
+
+// maxReferenceAttempts bounds how often a booking is retried with a fresh
+// reference after colliding with an existing one.
+const maxReferenceAttempts = 3
+
 type BookingService interface {
 	CreateBooking(userID, roomID string, checkIn, checkOut time.Time) (*domain.Booking, error)
-	CancelBooking(userID, bookingID string) error
-	GetUserBookings(userID string) ([]domain.Booking, error)
+	CreateGuestBooking(name, email, phone, roomID string, checkIn, checkOut time.Time) (*domain.Booking, *ManageLink, error)
+	CancelBooking(actor Actor, bookingID string, precondition Precondition) error
+	CompleteBooking(actor Actor, bookingID string, precondition Precondition) (*domain.Booking, error)
+	GetUserBookings(userID string, query pagination.Query) ([]domain.Booking, int64, error)
+	GetBookingDetail(actor Actor, bookingID string) (*domain.Booking, error)
+	SearchBookings(actor Actor, filter repository.BookingFilter) ([]domain.Booking, int64, error)
+	GetBookingByManageToken(token string) (*domain.Booking, error)
+	CancelBookingByManageToken(actor Actor, token string, precondition Precondition) error
+}
+
+// Precondition checks the version of a booking as the service read it,
+// before the service changes it. Handlers use it to hold a change to the
+// version named in If-Match. A nil Precondition accepts any version.
+type Precondition func(version int) error
+
+func (p Precondition) check(version int) error {
+	if p == nil {
+		return nil
+	}
+
+	return p(version)
+}
+
+// ManageLink is the signed credential handed to guests so they can view,
+// cancel and pay for their booking without an account.
+type ManageLink struct {
+	Token     string
+	ExpiresAt time.Time
 }
 
 type bookingService struct {
@@ -21,42 +62,101 @@ type bookingService struct {
 	bookingRepo repository.BookingRepository
 	roomRepo    repository.RoomRepository
 	paymentRepo repository.PaymentRepository
+	guestRepo   repository.GuestRepository
+	access      hotelAccess
 }
 
-func NewBookingService(db *gorm.DB, bookingRepo repository.BookingRepository, roomRepo repository.RoomRepository, paymentRepo repository.PaymentRepository) BookingService {
+func NewBookingService(db *gorm.DB, bookingRepo repository.BookingRepository, roomRepo repository.RoomRepository, paymentRepo repository.PaymentRepository, guestRepo repository.GuestRepository, staffRepo repository.HotelStaffRepository) BookingService {
 	return &bookingService{
 		DB:          db,
 		bookingRepo: bookingRepo,
 		roomRepo:    roomRepo,
 		paymentRepo: paymentRepo,
+		guestRepo:   guestRepo,
+		access:      hotelAccess{staffRepo: staffRepo},
 	}
 }
 
 func (s *bookingService) CreateBooking(userID, roomID string, checkIn, checkOut time.Time) (*domain.Booking, error) {
+	uid := util.ParseUUID(userID)
+	booking := &domain.Booking{UserID: &uid}
+
+	if err := s.createBooking(booking, roomID, checkIn, checkOut); err != nil {
+		return nil, err
+	}
+
+	return booking, nil
+}
+
+// CreateGuestBooking books a room for a caller without an account. The
+// contact details are stored on the booking; the guest profile found by email
+// only groups bookings and is never updated, since the caller has not proved
+// they own the address.
+func (s *bookingService) CreateGuestBooking(name, email, phone, roomID string, checkIn, checkOut time.Time) (*domain.Booking, *ManageLink, error) {
+	guest, err := s.findOrCreateGuest(name, strings.ToLower(strings.TrimSpace(email)), phone)
+	if err != nil {
+		return nil, nil, errors.New("failed to save guest details")
+	}
+
+	booking := &domain.Booking{GuestID: &guest.ID, GuestName: name, GuestPhone: phone}
+	if err := s.createBooking(booking, roomID, checkIn, checkOut); err != nil {
+		return nil, nil, err
+	}
+
+	link, err := s.issueManageLink(booking)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return booking, link, nil
+}
+
+func (s *bookingService) findOrCreateGuest(name, email, phone string) (*domain.Guest, error) {
+	guest, err := s.guestRepo.FindByEmail(email)
+	if err == nil {
+		return guest, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	guest = &domain.Guest{Name: name, Email: email, Phone: phone}
+	if err := s.guestRepo.Create(guest); err != nil {
+		// Another booking may have created the guest in the meantime.
+		if existing, findErr := s.guestRepo.FindByEmail(email); findErr == nil {
+			return existing, nil
+		}
+		return nil, err
+	}
+
+	return guest, nil
+}
+
+func (s *bookingService) createBooking(booking *domain.Booking, roomID string, checkIn, checkOut time.Time) error {
 	now := time.Now()
 	if checkIn.Before(now) {
-		return nil, errors.New("check-in date cannot be in the past")
+		return errors.New("check-in date cannot be in the past")
 	}
 	if checkOut.Before(checkIn) || checkOut.Equal(checkIn) {
-		return nil, errors.New("check-out date must be after check-in date")
+		return errors.New("check-out date must be after check-in date")
 	}
 
 	maxDuration := 30 * 24 * time.Hour
 	if checkOut.Sub(checkIn) > maxDuration {
-		return nil, errors.New("maximum booking duration is 30 days")
+		return errors.New("maximum booking duration is 30 days")
 	}
 
 	activeBookings, _ := s.bookingRepo.FindActiveByRoom(roomID, checkIn.Format(time.RFC3339), checkOut.Format(time.RFC3339))
 	if len(activeBookings) > 0 {
-		return nil, errors.New("room is already booked for selected dates")
+		return errors.New("room is already booked for selected dates")
 	}
 
 	room, err := s.roomRepo.FindByID(roomID)
 	if err != nil {
-		return nil, errors.New("room not found")
+		return errors.New("room not found")
 	}
 	if room.Availability <= 0 {
-		return nil, errors.New("room not available")
+		return errors.New("room not available")
 	}
 
 	nights := int(checkOut.Sub(checkIn).Hours() / 24)
@@ -65,55 +165,82 @@ func (s *bookingService) CreateBooking(userID, roomID string, checkIn, checkOut
 	}
 	totalPrice := float64(nights) * room.PricePerNight
 
-	booking := &domain.Booking{
-		UserID:     util.ParseUUID(userID),
-		RoomID:     util.ParseUUID(roomID),
-		CheckIn:    checkIn,
-		CheckOut:   checkOut,
-		TotalPrice: totalPrice,
-		Status:     domain.BookingStatusPending,
-	}
+	booking.RoomID = util.ParseUUID(roomID)
+	booking.CheckIn = checkIn
+	booking.CheckOut = checkOut
+	booking.TotalPrice = totalPrice
+	booking.Status = domain.BookingStatusPending
 
-	txErr := s.DB.Transaction(func(tx *gorm.DB) error {
-		if err := s.bookingRepo.Create(booking); err != nil {
+	// References are random, so a collision with an existing booking is
+	// possible if unlikely; draw a new one and try again.
+	var txErr error
+	for attempt := 1; ; attempt++ {
+		reference, err := util.GenerateReference()
+		if err != nil {
 			return err
 		}
+		booking.Reference = reference
 
-		if err := s.roomRepo.UpdateAvailability(roomID, room.Availability-1); err != nil {
-			return err
-		}
+		txErr = s.DB.Transaction(func(tx *gorm.DB) error {
+			if err := s.bookingRepo.WithTx(tx).Create(booking); err != nil {
+				return err
+			}
 
-		payment := &domain.Payment{
-			BookingID: booking.ID,
-			Amount:    totalPrice,
-			Status:    domain.PaymentStatusPending,
-		}
-		if err := s.paymentRepo.Create(payment); err != nil {
-			return err
-		}
+			if err := s.roomRepo.WithTx(tx).UpdateAvailability(roomID, room.Availability-1); err != nil {
+				return err
+			}
 
-		return nil
-	})
+			payment := &domain.Payment{
+				BookingID: booking.ID,
+				Amount:    totalPrice,
+				Status:    domain.PaymentStatusPending,
+			}
+			if err := s.paymentRepo.WithTx(tx).Create(payment); err != nil {
+				return err
+			}
+
+			return nil
+		})
+		if !errors.Is(txErr, repository.ErrDuplicateReference) || attempt == maxReferenceAttempts {
+			break
+		}
+	}
 
 	if txErr != nil {
-		return nil, txErr
+		return txErr
 	}
 
-	booking, _ = s.bookingRepo.FindByID(booking.ID.String())
+	if created, err := s.bookingRepo.FindByID(booking.ID.String()); err == nil {
+		*booking = *created
+	}
 
-	return booking, nil
+	return nil
 }
 
-func (s *bookingService) CancelBooking(userID, bookingID string) error {
+// CancelBooking lets guests cancel their own bookings, hotel managers cancel
+// bookings at their hotels and holders of booking:cancel:any cancel any.
+func (s *bookingService) CancelBooking(actor Actor, bookingID string, precondition Precondition) error {
 	booking, err := s.bookingRepo.FindByID(bookingID)
 	if err != nil {
-		return errors.New("booking not found")
+		return ErrBookingNotFound
 	}
 
-	if booking.UserID.String() != userID {
-		return errors.New("unauthorized to cancel this booking")
+	if !isBookingOwner(actor, booking) && !actor.Can(domain.PermBookingCancelAny) &&
+		s.access.authorize(actor, booking.Room.HotelID.String(), domain.RoleHotelManager) != nil {
+		return ErrBookingForbidden
 	}
+	if err := precondition.check(booking.Version); err != nil {
+		return err
+	}
+
+	return s.cancelBooking(actor, booking)
+}
 
+func isBookingOwner(actor Actor, booking *domain.Booking) bool {
+	return booking.UserID != nil && booking.UserID.String() == actor.UserID
+}
+
+func (s *bookingService) cancelBooking(actor Actor, booking *domain.Booking) error {
 	if booking.Status == domain.BookingStatusCancelled {
 		return errors.New("booking already cancelled")
 	}
@@ -127,10 +254,115 @@ func (s *bookingService) CancelBooking(userID, bookingID string) error {
 		_ = s.roomRepo.UpdateAvailability(booking.RoomID.String(), room.Availability+1)
 	}
 
+	audit := auditEntry(actor, domain.AuditBookingCancel, domain.AuditEntityBooking, booking.ID.String(),
+		map[string]any{"status": booking.Status}, map[string]any{"status": domain.BookingStatusCancelled})
+
 	booking.Status = domain.BookingStatusCancelled
-	return s.bookingRepo.Update(booking)
+	return s.bookingRepo.Update(booking, audit)
+}
+
+// CompleteBooking closes a confirmed stay once check-out has passed, releasing
+// the room and allowing the guest to review it.
+func (s *bookingService) CompleteBooking(actor Actor, bookingID string, precondition Precondition) (*domain.Booking, error) {
+	booking, err := s.bookingRepo.FindByID(bookingID)
+	if err != nil {
+		return nil, ErrBookingNotFound
+	}
+
+	if err := s.access.authorize(actor, booking.Room.HotelID.String(), domain.RoleHotelManager, domain.RoleStaff); err != nil {
+		return nil, ErrBookingForbidden
+	}
+	if err := precondition.check(booking.Version); err != nil {
+		return nil, err
+	}
+
+	if booking.Status != domain.BookingStatusConfirmed {
+		return nil, errors.New("only confirmed bookings can be completed")
+	}
+
+	if time.Now().Before(booking.CheckOut) {
+		return nil, errors.New("booking cannot be completed before check-out")
+	}
+
+	room, err := s.roomRepo.FindByID(booking.RoomID.String())
+	if err == nil {
+		_ = s.roomRepo.UpdateAvailability(booking.RoomID.String(), room.Availability+1)
+	}
+
+	audit := auditEntry(actor, domain.AuditBookingComplete, domain.AuditEntityBooking, booking.ID.String(),
+		map[string]any{"status": booking.Status}, map[string]any{"status": domain.BookingStatusCompleted})
+
+	booking.Status = domain.BookingStatusCompleted
+	if err := s.bookingRepo.Update(booking, audit); err != nil {
+		return nil, err
+	}
+
+	return booking, nil
+}
+
+func (s *bookingService) GetUserBookings(userID string, query pagination.Query) ([]domain.Booking, int64, error) {
+	return s.bookingRepo.FindByUser(userID, query)
+}
+
+func (s *bookingService) GetBookingDetail(actor Actor, bookingID string) (*domain.Booking, error) {
+	booking, err := s.bookingRepo.FindByID(bookingID)
+	if err != nil {
+		return nil, ErrBookingNotFound
+	}
+
+	if !isBookingOwner(actor, booking) && !actor.Can(domain.PermBookingReadAny) &&
+		s.access.authorize(actor, booking.Room.HotelID.String(), domain.RoleHotelManager, domain.RoleStaff) != nil {
+		return nil, ErrBookingForbidden
+	}
+
+	return booking, nil
+}
+
+// SearchBookings searches every booking for holders of booking:read:any;
+// managers and staff must scope the search to one of their hotels.
+func (s *bookingService) SearchBookings(actor Actor, filter repository.BookingFilter) ([]domain.Booking, int64, error) {
+	if !actor.Can(domain.PermBookingReadAny) {
+		if err := s.access.authorize(actor, filter.HotelID, domain.RoleHotelManager, domain.RoleStaff); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return s.bookingRepo.Search(filter)
+}
+
+func (s *bookingService) GetBookingByManageToken(token string) (*domain.Booking, error) {
+	claims, err := util.ParseBookingManageToken(token)
+	if err != nil {
+		return nil, errors.New("invalid or expired booking link")
+	}
+
+	booking, err := s.bookingRepo.FindByID(claims.BookingID)
+	if err != nil {
+		return nil, errors.New("booking not found")
+	}
+
+	return booking, nil
+}
+
+func (s *bookingService) CancelBookingByManageToken(actor Actor, token string, precondition Precondition) error {
+	booking, err := s.GetBookingByManageToken(token)
+	if err != nil {
+		return err
+	}
+	if err := precondition.check(booking.Version); err != nil {
+		return err
+	}
+
+	return s.cancelBooking(actor, booking)
 }
 
-func (s *bookingService) GetUserBookings(userID string) ([]domain.Booking, error) {
-	return s.bookingRepo.FindByUser(userID)
+func (s *bookingService) issueManageLink(booking *domain.Booking) (*ManageLink, error) {
+	expiresAt := booking.CheckOut.Add(manageLinkGrace)
+
+	token, err := util.GenerateBookingManageToken(booking.ID.String(), expiresAt)
+	if err != nil {
+		return nil, errors.New("failed to generate booking link")
+	}
+
+	return &ManageLink{Token: token, ExpiresAt: expiresAt}, nil
 }
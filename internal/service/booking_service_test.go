@@ -0,0 +1,458 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"hotel-booking-api/internal/domain"
+	"hotel-booking-api/internal/repository"
+	"hotel-booking-api/pkg/pagination"
+	"hotel-booking-api/pkg/util"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type MockBookingRepository struct {
+	mock.Mock
+}
+
+func (m *MockBookingRepository) WithTx(tx *gorm.DB) repository.BookingRepository {
+	return m
+}
+
+func (m *MockBookingRepository) Create(booking *domain.Booking) error {
+	args := m.Called(booking)
+	return args.Error(0)
+}
+
+func (m *MockBookingRepository) Update(booking *domain.Booking, audit *domain.AuditLog) error {
+	args := m.Called(booking, audit)
+	return args.Error(0)
+}
+
+func (m *MockBookingRepository) FindByUser(userID string, query pagination.Query) ([]domain.Booking, int64, error) {
+	args := m.Called(userID, query)
+	return args.Get(0).([]domain.Booking), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockBookingRepository) FindByID(id string) (*domain.Booking, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.Booking), args.Error(1)
+}
+
+func (m *MockBookingRepository) FindActiveByRoom(roomID string, checkIn, checkOut string) ([]domain.Booking, error) {
+	args := m.Called(roomID, checkIn, checkOut)
+	return args.Get(0).([]domain.Booking), args.Error(1)
+}
+
+func (m *MockBookingRepository) Search(filter repository.BookingFilter) ([]domain.Booking, int64, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]domain.Booking), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockBookingRepository) CountUpcomingByUser(userID string) (int64, error) {
+	args := m.Called(userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockBookingRepository) CountUpcomingByRoom(roomID string) (int64, error) {
+	args := m.Called(roomID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockBookingRepository) CountUpcomingByHotel(hotelID string) (int64, error) {
+	args := m.Called(hotelID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockBookingRepository) CountByRoom(roomID string) (int64, error) {
+	args := m.Called(roomID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockBookingRepository) CountByHotel(hotelID string) (int64, error) {
+	args := m.Called(hotelID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type MockRoomRepository struct {
+	mock.Mock
+}
+
+func (m *MockRoomRepository) WithTx(tx *gorm.DB) repository.RoomRepository {
+	return m
+}
+
+func (m *MockRoomRepository) Create(room *domain.Room, audit *domain.AuditLog) error {
+	args := m.Called(room, audit)
+	return args.Error(0)
+}
+
+func (m *MockRoomRepository) Update(room *domain.Room, audit *domain.AuditLog) error {
+	args := m.Called(room, audit)
+	return args.Error(0)
+}
+
+func (m *MockRoomRepository) FindByHotel(hotelID string) ([]domain.Room, error) {
+	args := m.Called(hotelID)
+	return args.Get(0).([]domain.Room), args.Error(1)
+}
+
+func (m *MockRoomRepository) ListByHotel(hotelID string, amenities []string, query pagination.Query) ([]domain.Room, int64, error) {
+	args := m.Called(hotelID, amenities, query)
+	return args.Get(0).([]domain.Room), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockRoomRepository) FindByID(id string) (*domain.Room, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.Room), args.Error(1)
+}
+
+func (m *MockRoomRepository) FindByIDWithDeleted(id string) (*domain.Room, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.Room), args.Error(1)
+}
+
+func (m *MockRoomRepository) FindAvailableByHotel(hotelID string, checkIn, checkOut string) ([]domain.Room, error) {
+	args := m.Called(hotelID, checkIn, checkOut)
+	return args.Get(0).([]domain.Room), args.Error(1)
+}
+
+func (m *MockRoomRepository) UpdateAvailability(id string, availability int) error {
+	args := m.Called(id, availability)
+	return args.Error(0)
+}
+
+func (m *MockRoomRepository) Delete(id string, audit *domain.AuditLog) error {
+	args := m.Called(id, audit)
+	return args.Error(0)
+}
+
+func (m *MockRoomRepository) Restore(id string, audit *domain.AuditLog) error {
+	args := m.Called(id, audit)
+	return args.Error(0)
+}
+
+func (m *MockRoomRepository) Purge(id string, audit *domain.AuditLog) error {
+	args := m.Called(id, audit)
+	return args.Error(0)
+}
+
+type MockPaymentRepository struct {
+	mock.Mock
+}
+
+func (m *MockPaymentRepository) WithTx(tx *gorm.DB) repository.PaymentRepository {
+	return m
+}
+
+func (m *MockPaymentRepository) Create(payment *domain.Payment) error {
+	args := m.Called(payment)
+	return args.Error(0)
+}
+
+func (m *MockPaymentRepository) Update(payment *domain.Payment) error {
+	args := m.Called(payment)
+	return args.Error(0)
+}
+
+func (m *MockPaymentRepository) Settle(payment *domain.Payment, booking *domain.Booking, audit *domain.AuditLog) error {
+	args := m.Called(payment, booking, audit)
+	return args.Error(0)
+}
+
+func (m *MockPaymentRepository) FindByBookingID(bookingID string) (*domain.Payment, error) {
+	args := m.Called(bookingID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.Payment), args.Error(1)
+}
+
+type MockGuestRepository struct {
+	mock.Mock
+}
+
+func (m *MockGuestRepository) Create(guest *domain.Guest) error {
+	args := m.Called(guest)
+	return args.Error(0)
+}
+
+func (m *MockGuestRepository) FindByEmail(email string) (*domain.Guest, error) {
+	args := m.Called(email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.Guest), args.Error(1)
+}
+
+// txPool stands in for the database so services can open their
+// transactions; the mocked repositories do the actual work.
+type txPool struct{}
+
+// txConn is the transaction txPool begins.
+type txConn struct {
+	txPool
+}
+
+var errNoDatabase = errors.New("no database in unit tests")
+
+func (*txPool) PrepareContext(context.Context, string) (*sql.Stmt, error) {
+	return nil, errNoDatabase
+}
+
+func (*txPool) ExecContext(context.Context, string, ...any) (sql.Result, error) {
+	return nil, errNoDatabase
+}
+
+func (*txPool) QueryContext(context.Context, string, ...any) (*sql.Rows, error) {
+	return nil, errNoDatabase
+}
+
+func (*txPool) QueryRowContext(context.Context, string, ...any) *sql.Row {
+	return nil
+}
+
+func (*txPool) BeginTx(context.Context, *sql.TxOptions) (gorm.ConnPool, error) {
+	return &txConn{}, nil
+}
+
+func (*txConn) Commit() error   { return nil }
+func (*txConn) Rollback() error { return nil }
+
+type bookingMocks struct {
+	service  BookingService
+	bookings *MockBookingRepository
+	rooms    *MockRoomRepository
+	payments *MockPaymentRepository
+	guests   *MockGuestRepository
+}
+
+// newTxDB opens gorm on a txPool, for services that open transactions.
+func newTxDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: &txPool{}}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open gorm: %v", err)
+	}
+
+	return db
+}
+
+func newBookingTest(t *testing.T) *bookingMocks {
+	t.Helper()
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	db := newTxDB(t)
+
+	m := &bookingMocks{
+		bookings: new(MockBookingRepository),
+		rooms:    new(MockRoomRepository),
+		payments: new(MockPaymentRepository),
+		guests:   new(MockGuestRepository),
+	}
+	m.service = NewBookingService(db, m.bookings, m.rooms, m.payments, m.guests, nil)
+
+	return m
+}
+
+// expectBookable sets up a free room and the writes of a successful booking.
+func (m *bookingMocks) expectBookable(room *domain.Room) {
+	m.bookings.On("FindActiveByRoom", room.ID.String(), mock.Anything, mock.Anything).Return([]domain.Booking{}, nil)
+	m.rooms.On("FindByID", room.ID.String()).Return(room, nil)
+	m.bookings.On("Create", mock.AnythingOfType("*domain.Booking")).Return(nil)
+	m.rooms.On("UpdateAvailability", room.ID.String(), room.Availability-1).Return(nil)
+	m.payments.On("Create", mock.AnythingOfType("*domain.Payment")).Return(nil)
+	m.bookings.On("FindByID", mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+}
+
+func TestBookingService_CreateGuestBooking_NewGuest(t *testing.T) {
+	m := newBookingTest(t)
+	room := &domain.Room{ID: uuid.New(), PricePerNight: 100, Availability: 2}
+	m.expectBookable(room)
+	m.guests.On("FindByEmail", "ana@example.com").Return(nil, gorm.ErrRecordNotFound)
+	m.guests.On("Create", mock.AnythingOfType("*domain.Guest")).Return(nil)
+
+	checkIn := time.Now().Add(24 * time.Hour)
+	booking, link, err := m.service.CreateGuestBooking("Ana", " Ana@Example.com ", "+100", room.ID.String(), checkIn, checkIn.Add(48*time.Hour))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Ana", booking.GuestName)
+	assert.Equal(t, "+100", booking.GuestPhone)
+	assert.Equal(t, 200.0, booking.TotalPrice)
+	assert.NotEmpty(t, link.Token)
+
+	guest := m.guests.Calls[1].Arguments.Get(0).(*domain.Guest)
+	assert.Equal(t, "ana@example.com", guest.Email)
+}
+
+func TestBookingService_CreateGuestBooking_KeepsExistingGuestDetails(t *testing.T) {
+	m := newBookingTest(t)
+	room := &domain.Room{ID: uuid.New(), PricePerNight: 100, Availability: 2}
+	m.expectBookable(room)
+	existing := &domain.Guest{ID: uuid.New(), Name: "Ana", Email: "ana@example.com", Phone: "+100"}
+	m.guests.On("FindByEmail", "ana@example.com").Return(existing, nil)
+
+	checkIn := time.Now().Add(24 * time.Hour)
+	booking, _, err := m.service.CreateGuestBooking("Mallory", "ana@example.com", "+666", room.ID.String(), checkIn, checkIn.Add(24*time.Hour))
+
+	assert.NoError(t, err)
+	assert.Equal(t, existing.ID, *booking.GuestID)
+	assert.Equal(t, "Mallory", booking.GuestName)
+	assert.Equal(t, "+666", booking.GuestPhone)
+	assert.Equal(t, "Ana", existing.Name)
+	assert.Equal(t, "+100", existing.Phone)
+	m.guests.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestBookingService_CreateGuestBooking_LookupFailure(t *testing.T) {
+	m := newBookingTest(t)
+	m.guests.On("FindByEmail", "ana@example.com").Return(nil, errors.New("connection refused"))
+
+	checkIn := time.Now().Add(24 * time.Hour)
+	_, _, err := m.service.CreateGuestBooking("Ana", "ana@example.com", "+100", uuid.NewString(), checkIn, checkIn.Add(24*time.Hour))
+
+	assert.EqualError(t, err, "failed to save guest details")
+	m.guests.AssertNotCalled(t, "Create", mock.Anything)
+	m.bookings.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestBookingService_CreateBooking_RetriesDuplicateReference(t *testing.T) {
+	m := newBookingTest(t)
+	room := &domain.Room{ID: uuid.New(), PricePerNight: 100, Availability: 2}
+	m.bookings.On("Create", mock.AnythingOfType("*domain.Booking")).Return(repository.ErrDuplicateReference).Once()
+	m.expectBookable(room)
+
+	checkIn := time.Now().Add(24 * time.Hour)
+	booking, err := m.service.CreateBooking(uuid.NewString(), room.ID.String(), checkIn, checkIn.Add(24*time.Hour))
+
+	assert.NoError(t, err)
+	m.bookings.AssertNumberOfCalls(t, "Create", 2)
+	m.rooms.AssertNumberOfCalls(t, "UpdateAvailability", 1)
+	assert.Regexp(t, `^BK[A-Z2-9]{8}$`, booking.Reference)
+}
+
+func TestBookingService_CreateBooking_GivesUpOnDuplicateReferences(t *testing.T) {
+	m := newBookingTest(t)
+	room := &domain.Room{ID: uuid.New(), PricePerNight: 100, Availability: 2}
+	m.bookings.On("FindActiveByRoom", room.ID.String(), mock.Anything, mock.Anything).Return([]domain.Booking{}, nil)
+	m.rooms.On("FindByID", room.ID.String()).Return(room, nil)
+	m.bookings.On("Create", mock.AnythingOfType("*domain.Booking")).Return(repository.ErrDuplicateReference)
+
+	checkIn := time.Now().Add(24 * time.Hour)
+	_, err := m.service.CreateBooking(uuid.NewString(), room.ID.String(), checkIn, checkIn.Add(24*time.Hour))
+
+	assert.ErrorIs(t, err, repository.ErrDuplicateReference)
+	m.bookings.AssertNumberOfCalls(t, "Create", maxReferenceAttempts)
+	m.rooms.AssertNotCalled(t, "UpdateAvailability", mock.Anything, mock.Anything)
+}
+
+func TestBookingService_GetBookingByManageToken(t *testing.T) {
+	m := newBookingTest(t)
+	booking := &domain.Booking{ID: uuid.New(), Status: domain.BookingStatusPending}
+	m.bookings.On("FindByID", booking.ID.String()).Return(booking, nil)
+
+	token, err := util.GenerateBookingManageToken(booking.ID.String(), time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+
+	found, err := m.service.GetBookingByManageToken(token)
+
+	assert.NoError(t, err)
+	assert.Equal(t, booking, found)
+}
+
+func TestBookingService_GetBookingByManageToken_RejectsOtherTokens(t *testing.T) {
+	m := newBookingTest(t)
+
+	accessToken, _, err := util.GenerateJWT(uuid.NewString(), domain.RoleCustomer, false, time.Hour)
+	assert.NoError(t, err)
+	challenge, err := util.GenerateTwoFactorChallenge(uuid.NewString(), time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+	expired, err := util.GenerateBookingManageToken(uuid.NewString(), time.Now().Add(-time.Hour))
+	assert.NoError(t, err)
+
+	for name, token := range map[string]string{
+		"access token":        accessToken,
+		"two-factor token":    challenge,
+		"expired manage link": expired,
+		"garbage":             "not-a-token",
+	} {
+		_, err := m.service.GetBookingByManageToken(token)
+		assert.EqualError(t, err, "invalid or expired booking link", name)
+	}
+	m.bookings.AssertNotCalled(t, "FindByID", mock.Anything)
+}
+
+func TestManageToken_IsNotAnAccessToken(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	token, err := util.GenerateBookingManageToken(uuid.NewString(), time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+
+	// Without a configured audience ParseJWT cannot tell the tokens apart by
+	// audience; the missing user ID must reject it.
+	_, err = util.ParseJWT(token)
+	assert.Error(t, err)
+}
+
+func TestBookingService_CancelBookingByManageToken(t *testing.T) {
+	m := newBookingTest(t)
+	guestID := uuid.New()
+	booking := &domain.Booking{ID: uuid.New(), GuestID: &guestID, RoomID: uuid.New(), Status: domain.BookingStatusPending}
+	room := &domain.Room{ID: booking.RoomID, Availability: 1}
+	m.bookings.On("FindByID", booking.ID.String()).Return(booking, nil)
+	m.rooms.On("FindByID", room.ID.String()).Return(room, nil)
+	m.rooms.On("UpdateAvailability", room.ID.String(), 2).Return(nil)
+	m.bookings.On("Update", booking, mock.AnythingOfType("*domain.AuditLog")).Return(nil)
+
+	token, err := util.GenerateBookingManageToken(booking.ID.String(), time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+
+	err = m.service.CancelBookingByManageToken(Actor{}, token, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.BookingStatusCancelled, booking.Status)
+}
+
+func TestBookingService_CancelBookingByManageToken_PreconditionFailed(t *testing.T) {
+	m := newBookingTest(t)
+	guestID := uuid.New()
+	booking := &domain.Booking{ID: uuid.New(), GuestID: &guestID, Status: domain.BookingStatusPending, Version: 2}
+	m.bookings.On("FindByID", booking.ID.String()).Return(booking, nil)
+
+	token, err := util.GenerateBookingManageToken(booking.ID.String(), time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+
+	stale := errors.New("stale")
+	var checked int
+	err = m.service.CancelBookingByManageToken(Actor{}, token, func(version int) error {
+		checked = version
+		return stale
+	})
+
+	assert.ErrorIs(t, err, stale)
+	assert.Equal(t, 2, checked)
+	assert.Equal(t, domain.BookingStatusPending, booking.Status)
+	m.bookings.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,485 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hotel-booking-api/internal/domain"
+	"hotel-booking-api/internal/repository"
+	"hotel-booking-api/pkg/logger"
+	"hotel-booking-api/pkg/mailer"
+	"hotel-booking-api/pkg/storage"
+	"hotel-booking-api/pkg/util"
+	"io"
+	"strings"
+	"time"
+)
+
+var (
+	ErrExportNotFound   = errors.New("data export not found")
+	ErrExportInProgress = errors.New("a data export is already being prepared")
+	ErrExportNotReady   = errors.New("data export is not ready yet")
+	ErrExportExpired    = errors.New("data export has expired")
+)
+
+const (
+	// exportTTL is how long a finished archive can be downloaded.
+	exportTTL = 7 * 24 * time.Hour
+	// exportSweepInterval is how often the worker looks for queued exports
+	// and expired archives when nothing wakes it earlier.
+	exportSweepInterval = time.Minute
+	// exportStaleAfter is how long an export may sit in PROCESSING before
+	// another worker assumes the first one died and takes it over.
+	exportStaleAfter = 15 * time.Minute
+)
+
+// DataExportService builds personal data archives in the background. Run
+// must be started once per process for requested exports to be built.
+type DataExportService interface {
+	RequestExport(requestedBy, userID string) (*domain.DataExport, error)
+	ListExports(userID string) ([]domain.DataExport, error)
+	GetExport(userID, id string) (*domain.DataExport, error)
+	OpenExport(userID, id string) (*domain.DataExport, io.ReadCloser, error)
+	Run(ctx context.Context)
+}
+
+type dataExportService struct {
+	exportRepo repository.DataExportRepository
+	userRepo   repository.UserRepository
+	storage    storage.Storage
+	mail       mailer.Mailer
+	publicURL  string
+	wake       chan struct{}
+}
+
+func NewDataExportService(exportRepo repository.DataExportRepository, userRepo repository.UserRepository, store storage.Storage, mail mailer.Mailer, publicURL string) DataExportService {
+	return &dataExportService{
+		exportRepo: exportRepo,
+		userRepo:   userRepo,
+		storage:    store,
+		mail:       mail,
+		publicURL:  strings.TrimRight(publicURL, "/"),
+		wake:       make(chan struct{}, 1),
+	}
+}
+
+// RequestExport queues an export of the user's data. A user has at most one
+// export being prepared at a time.
+func (s *dataExportService) RequestExport(requestedBy, userID string) (*domain.DataExport, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil || user.AnonymizedAt != nil {
+		return nil, ErrUserNotFound
+	}
+
+	active, err := s.exportRepo.CountActiveByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if active > 0 {
+		return nil, ErrExportInProgress
+	}
+
+	export := &domain.DataExport{
+		UserID:        user.ID,
+		RequestedByID: util.ParseUUID(requestedBy),
+		Status:        domain.ExportStatusPending,
+	}
+	if err := s.exportRepo.Create(export); err != nil {
+		return nil, err
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+
+	return export, nil
+}
+
+func (s *dataExportService) ListExports(userID string) ([]domain.DataExport, error) {
+	return s.exportRepo.ListByUser(userID)
+}
+
+func (s *dataExportService) GetExport(userID, id string) (*domain.DataExport, error) {
+	export, err := s.exportRepo.FindByID(id)
+	if err != nil || export.UserID.String() != userID {
+		return nil, ErrExportNotFound
+	}
+
+	return export, nil
+}
+
+// OpenExport returns a finished archive for download.
+func (s *dataExportService) OpenExport(userID, id string) (*domain.DataExport, io.ReadCloser, error) {
+	export, err := s.GetExport(userID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch {
+	case export.Status == domain.ExportStatusExpired,
+		export.Status == domain.ExportStatusReady && export.ExpiresAt != nil && time.Now().After(*export.ExpiresAt):
+		return nil, nil, ErrExportExpired
+	case export.Status != domain.ExportStatusReady:
+		return nil, nil, ErrExportNotReady
+	}
+
+	file, err := s.storage.Open(context.Background(), export.FileKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return export, file, nil
+}
+
+// Run builds queued exports and deletes expired archives until ctx is
+// cancelled. Several processes may run it against the same database; each
+// export is claimed by one of them.
+func (s *dataExportService) Run(ctx context.Context) {
+	ticker := time.NewTicker(exportSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		s.sweep(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+func (s *dataExportService) sweep(ctx context.Context) {
+	expired, err := s.exportRepo.ListExpired(time.Now())
+	if err != nil {
+		logger.Error("Failed to list expired data exports", "error", err)
+	}
+	for _, export := range expired {
+		if err := s.storage.Delete(ctx, export.FileKey); err != nil {
+			logger.Error("Failed to delete data export", "export_id", export.ID, "error", err)
+			continue
+		}
+		if err := s.exportRepo.MarkExpired(export.ID.String()); err != nil {
+			logger.Error("Failed to expire data export", "export_id", export.ID, "error", err)
+		}
+	}
+
+	staleBefore := time.Now().Add(-exportStaleAfter)
+	runnable, err := s.exportRepo.ListRunnable(staleBefore)
+	if err != nil {
+		logger.Error("Failed to list queued data exports", "error", err)
+		return
+	}
+	for i := range runnable {
+		if ctx.Err() != nil {
+			return
+		}
+
+		claimed, err := s.exportRepo.Claim(runnable[i].ID.String(), staleBefore)
+		if err != nil || !claimed {
+			continue
+		}
+		s.build(ctx, &runnable[i])
+	}
+}
+
+// build writes the archive of one claimed export and tells the requester it
+// is ready. Failures are recorded on the export rather than retried.
+func (s *dataExportService) build(ctx context.Context, export *domain.DataExport) {
+	id := export.ID.String()
+
+	data, err := s.exportRepo.LoadUserData(export.UserID.String())
+	if err != nil {
+		s.fail(id, "failed to read account data", err)
+		return
+	}
+
+	archive, err := writeExportArchive(export, data, time.Now())
+	if err != nil {
+		s.fail(id, "failed to write archive", err)
+		return
+	}
+
+	key := fmt.Sprintf("exports/%s/%s.zip", export.UserID, export.ID)
+	if err := s.storage.Put(ctx, key, bytes.NewReader(archive), "application/zip"); err != nil {
+		s.fail(id, "failed to store archive", err)
+		return
+	}
+
+	expiresAt := time.Now().Add(exportTTL)
+	if err := s.exportRepo.Complete(id, key, int64(len(archive)), expiresAt); err != nil {
+		// Either the account was deleted while the archive was being built
+		// or the export could not be recorded; the file must not linger.
+		logger.Warn("Discarding data export", "export_id", id, "error", err)
+		if err := s.storage.Delete(ctx, key); err != nil {
+			logger.Error("Failed to delete data export", "export_id", id, "error", err)
+		}
+		return
+	}
+
+	if err := s.notifyReady(export, &data.User, expiresAt); err != nil {
+		logger.Warn("Failed to send data export email", "export_id", id, "error", err)
+	}
+}
+
+func (s *dataExportService) fail(id, reason string, err error) {
+	logger.Error("Data export failed", "export_id", id, "reason", reason, "error", err)
+
+	if err := s.exportRepo.Fail(id, reason); err != nil {
+		logger.Error("Failed to record data export failure", "export_id", id, "error", err)
+	}
+}
+
+func (s *dataExportService) notifyReady(export *domain.DataExport, user *domain.User, expiresAt time.Time) error {
+	if export.RequestedByID == export.UserID {
+		return s.mail.Send(context.Background(), mailer.Message{
+			To:      user.Email,
+			Subject: "Your data export is ready",
+			Body: fmt.Sprintf(
+				"Hi %s,\n\nThe copy of your personal data you asked for is ready. Download it from your account:\n\n%s/account/data-exports\n\nThe archive is available until %s.\n",
+				user.Name, s.publicURL, expiresAt.Format(time.RFC1123),
+			),
+		})
+	}
+
+	requester, err := s.userRepo.FindByID(export.RequestedByID.String())
+	if err != nil {
+		return err
+	}
+
+	return s.mail.Send(context.Background(), mailer.Message{
+		To:      requester.Email,
+		Subject: "Data export ready",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nThe data export %s you requested for %s is ready to download from the admin API.\n\nThe archive is available until %s.\n",
+			requester.Name, export.ID, user.Email, expiresAt.Format(time.RFC1123),
+		),
+	})
+}
+
+// The archive is a ZIP of JSON files. Its layout is part of what users are
+// promised, so it is described by these types rather than by the domain
+// models, which may gain fields that are not personal data.
+type (
+	exportFile struct {
+		name    string
+		content any
+	}
+
+	exportManifest struct {
+		ExportID    string    `json:"export_id"`
+		UserID      string    `json:"user_id"`
+		GeneratedAt time.Time `json:"generated_at"`
+		Files       []string  `json:"files"`
+	}
+
+	exportProfile struct {
+		ID                string     `json:"id"`
+		Name              string     `json:"name"`
+		Email             string     `json:"email"`
+		PendingEmail      string     `json:"pending_email,omitempty"`
+		Role              string     `json:"role"`
+		Phone             string     `json:"phone"`
+		PreferredCurrency string     `json:"preferred_currency"`
+		PreferredLanguage string     `json:"preferred_language"`
+		EmailVerifiedAt   *time.Time `json:"email_verified_at"`
+		TwoFactorEnabled  bool       `json:"two_factor_enabled"`
+		DeactivatedAt     *time.Time `json:"deactivated_at"`
+		CreatedAt         time.Time  `json:"created_at"`
+		UpdatedAt         time.Time  `json:"updated_at"`
+	}
+
+	exportBooking struct {
+		ID         string         `json:"id"`
+		Reference  string         `json:"reference"`
+		Hotel      string         `json:"hotel"`
+		RoomType   string         `json:"room_type"`
+		CheckIn    time.Time      `json:"check_in"`
+		CheckOut   time.Time      `json:"check_out"`
+		TotalPrice float64        `json:"total_price"`
+		Status     string         `json:"status"`
+		CreatedAt  time.Time      `json:"created_at"`
+		Payment    *exportPayment `json:"payment"`
+	}
+
+	exportPayment struct {
+		ID            string    `json:"id"`
+		Amount        float64   `json:"amount"`
+		Status        string    `json:"status"`
+		PaymentMethod string    `json:"payment_method"`
+		TransactionID string    `json:"transaction_id"`
+		CreatedAt     time.Time `json:"created_at"`
+	}
+
+	exportReview struct {
+		ID          string    `json:"id"`
+		BookingID   string    `json:"booking_id"`
+		HotelID     string    `json:"hotel_id"`
+		Rating      int       `json:"rating"`
+		Cleanliness int       `json:"cleanliness"`
+		Location    int       `json:"location"`
+		Service     int       `json:"service"`
+		Comment     string    `json:"comment"`
+		Reply       string    `json:"reply,omitempty"`
+		Status      string    `json:"status"`
+		CreatedAt   time.Time `json:"created_at"`
+	}
+
+	exportSession struct {
+		ID        string     `json:"id"`
+		SignInID  string     `json:"sign_in_id"`
+		TwoFactor bool       `json:"two_factor"`
+		CreatedAt time.Time  `json:"created_at"`
+		ExpiresAt time.Time  `json:"expires_at"`
+		RotatedAt *time.Time `json:"rotated_at"`
+		RevokedAt *time.Time `json:"revoked_at"`
+	}
+
+	// exportAuditEntry leaves out who else acted and from where; the user
+	// learns what was done, and that it was not them.
+	exportAuditEntry struct {
+		Action     string                        `json:"action"`
+		EntityType string                        `json:"entity_type"`
+		EntityID   string                        `json:"entity_id"`
+		ByYou      bool                          `json:"by_you"`
+		ActorRole  string                        `json:"actor_role,omitempty"`
+		Changes    map[string]domain.AuditChange `json:"changes,omitempty"`
+		IP         string                        `json:"ip,omitempty"`
+		CreatedAt  time.Time                     `json:"created_at"`
+	}
+)
+
+// writeExportArchive renders data as a ZIP archive.
+func writeExportArchive(export *domain.DataExport, data *repository.UserData, now time.Time) ([]byte, error) {
+	user := data.User
+	profile := exportProfile{
+		ID:                user.ID.String(),
+		Name:              user.Name,
+		Email:             user.Email,
+		PendingEmail:      user.PendingEmail,
+		Role:              user.Role,
+		Phone:             user.Phone,
+		PreferredCurrency: user.PreferredCurrency,
+		PreferredLanguage: user.PreferredLanguage,
+		EmailVerifiedAt:   user.EmailVerifiedAt,
+		TwoFactorEnabled:  user.TOTPEnabledAt != nil,
+		DeactivatedAt:     user.DeactivatedAt,
+		CreatedAt:         user.CreatedAt,
+		UpdatedAt:         user.UpdatedAt,
+	}
+
+	bookings := make([]exportBooking, len(data.Bookings))
+	for i, b := range data.Bookings {
+		bookings[i] = exportBooking{
+			ID:         b.ID.String(),
+			Reference:  b.Reference,
+			Hotel:      b.Room.Hotel.Name,
+			RoomType:   b.Room.RoomType,
+			CheckIn:    b.CheckIn,
+			CheckOut:   b.CheckOut,
+			TotalPrice: b.TotalPrice,
+			Status:     b.Status,
+			CreatedAt:  b.CreatedAt,
+		}
+		if p := b.Payment; p != nil {
+			bookings[i].Payment = &exportPayment{
+				ID:            p.ID.String(),
+				Amount:        p.Amount,
+				Status:        p.Status,
+				PaymentMethod: p.PaymentMethod,
+				TransactionID: p.TransactionID,
+				CreatedAt:     p.CreatedAt,
+			}
+		}
+	}
+
+	reviews := make([]exportReview, len(data.Reviews))
+	for i, r := range data.Reviews {
+		reviews[i] = exportReview{
+			ID:          r.ID.String(),
+			BookingID:   r.BookingID.String(),
+			HotelID:     r.HotelID.String(),
+			Rating:      r.Rating,
+			Cleanliness: r.Cleanliness,
+			Location:    r.Location,
+			Service:     r.Service,
+			Comment:     r.Comment,
+			Reply:       r.Reply,
+			Status:      r.Status,
+			CreatedAt:   r.CreatedAt,
+		}
+	}
+
+	sessions := make([]exportSession, len(data.Sessions))
+	for i, t := range data.Sessions {
+		sessions[i] = exportSession{
+			ID:        t.ID.String(),
+			SignInID:  t.FamilyID.String(),
+			TwoFactor: t.MFA,
+			CreatedAt: t.CreatedAt,
+			ExpiresAt: t.ExpiresAt,
+			RotatedAt: t.RotatedAt,
+			RevokedAt: t.RevokedAt,
+		}
+	}
+
+	activity := make([]exportAuditEntry, len(data.AuditLog))
+	for i, e := range data.AuditLog {
+		activity[i] = exportAuditEntry{
+			Action:     e.Action,
+			EntityType: e.EntityType,
+			EntityID:   e.EntityID,
+			ByYou:      e.ActorID != nil && *e.ActorID == user.ID,
+			ActorRole:  e.ActorRole,
+			Changes:    e.Changes,
+			CreatedAt:  e.CreatedAt,
+		}
+		if activity[i].ByYou {
+			activity[i].IP = e.IP
+		}
+	}
+
+	files := []exportFile{
+		{"profile.json", profile},
+		{"bookings.json", bookings},
+		{"reviews.json", reviews},
+		{"sessions.json", sessions},
+		{"audit_log.json", activity},
+	}
+
+	manifest := exportManifest{
+		ExportID:    export.ID.String(),
+		UserID:      user.ID.String(),
+		GeneratedAt: now,
+	}
+	for _, f := range files {
+		manifest.Files = append(manifest.Files, f.name)
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	for _, f := range append([]exportFile{{"export.json", manifest}}, files...) {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: f.name, Method: zip.Deflate, Modified: now})
+		if err != nil {
+			return nil, err
+		}
+
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(f.content); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
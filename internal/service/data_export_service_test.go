@@ -0,0 +1,176 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"hotel-booking-api/internal/domain"
+	"hotel-booking-api/internal/repository"
+	"io"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockDataExportRepository struct {
+	mock.Mock
+}
+
+func (m *MockDataExportRepository) Create(export *domain.DataExport) error {
+	args := m.Called(export)
+	return args.Error(0)
+}
+
+func (m *MockDataExportRepository) FindByID(id string) (*domain.DataExport, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.DataExport), args.Error(1)
+}
+
+func (m *MockDataExportRepository) ListByUser(userID string) ([]domain.DataExport, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]domain.DataExport), args.Error(1)
+}
+
+func (m *MockDataExportRepository) CountActiveByUser(userID string) (int64, error) {
+	args := m.Called(userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockDataExportRepository) ListRunnable(staleBefore time.Time) ([]domain.DataExport, error) {
+	args := m.Called(staleBefore)
+	return args.Get(0).([]domain.DataExport), args.Error(1)
+}
+
+func (m *MockDataExportRepository) Claim(id string, staleBefore time.Time) (bool, error) {
+	args := m.Called(id, staleBefore)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockDataExportRepository) Complete(id, fileKey string, size int64, expiresAt time.Time) error {
+	args := m.Called(id, fileKey, size, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockDataExportRepository) Fail(id, reason string) error {
+	args := m.Called(id, reason)
+	return args.Error(0)
+}
+
+func (m *MockDataExportRepository) ListExpired(now time.Time) ([]domain.DataExport, error) {
+	args := m.Called(now)
+	return args.Get(0).([]domain.DataExport), args.Error(1)
+}
+
+func (m *MockDataExportRepository) MarkExpired(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockDataExportRepository) LoadUserData(userID string) (*repository.UserData, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*repository.UserData), args.Error(1)
+}
+
+func TestDataExportService_RequestExport_InProgress(t *testing.T) {
+	mockUsers := new(MockUserRepository)
+	mockExports := new(MockDataExportRepository)
+	service := NewDataExportService(mockExports, mockUsers, nil, &recordingMailer{}, "http://localhost:3000")
+
+	user := &domain.User{ID: uuid.New()}
+	mockUsers.On("FindByID", user.ID.String()).Return(user, nil)
+	mockExports.On("CountActiveByUser", user.ID.String()).Return(int64(1), nil)
+
+	_, err := service.RequestExport(user.ID.String(), user.ID.String())
+
+	assert.ErrorIs(t, err, ErrExportInProgress)
+	mockExports.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestDataExportService_OpenExport_OtherUser(t *testing.T) {
+	mockExports := new(MockDataExportRepository)
+	service := NewDataExportService(mockExports, new(MockUserRepository), nil, &recordingMailer{}, "http://localhost:3000")
+
+	export := &domain.DataExport{ID: uuid.New(), UserID: uuid.New(), Status: domain.ExportStatusReady}
+	mockExports.On("FindByID", export.ID.String()).Return(export, nil)
+
+	_, _, err := service.OpenExport(uuid.NewString(), export.ID.String())
+
+	assert.ErrorIs(t, err, ErrExportNotFound)
+}
+
+func TestDataExportService_OpenExport_Expired(t *testing.T) {
+	mockExports := new(MockDataExportRepository)
+	service := NewDataExportService(mockExports, new(MockUserRepository), nil, &recordingMailer{}, "http://localhost:3000")
+
+	expiredAt := time.Now().Add(-time.Minute)
+	export := &domain.DataExport{ID: uuid.New(), UserID: uuid.New(), Status: domain.ExportStatusReady, ExpiresAt: &expiredAt}
+	mockExports.On("FindByID", export.ID.String()).Return(export, nil)
+
+	_, _, err := service.OpenExport(export.UserID.String(), export.ID.String())
+
+	assert.ErrorIs(t, err, ErrExportExpired)
+}
+
+func TestWriteExportArchive(t *testing.T) {
+	user := domain.User{ID: uuid.New(), Name: "Test User", Email: "test@example.com", Password: "secret-hash"}
+	adminID := uuid.New()
+	payment := &domain.Payment{ID: uuid.New(), Amount: 200, Status: domain.PaymentStatusSuccess}
+	data := &repository.UserData{
+		User: user,
+		Bookings: []domain.Booking{{
+			ID:        uuid.New(),
+			Reference: "HB-TEST",
+			Room:      domain.Room{RoomType: "Deluxe", Hotel: domain.Hotel{Name: "Test Hotel"}},
+			Payment:   payment,
+		}},
+		AuditLog: []domain.AuditLog{
+			{ActorID: &user.ID, Action: domain.AuditBookingCancel, EntityType: domain.AuditEntityBooking, IP: "192.0.2.1"},
+			{ActorID: &adminID, Action: domain.AuditUserDeactivate, EntityType: domain.AuditEntityUser, IP: "198.51.100.7"},
+		},
+	}
+	export := &domain.DataExport{ID: uuid.New(), UserID: user.ID}
+
+	archive, err := writeExportArchive(export, data, time.Now())
+	assert.NoError(t, err)
+
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	files := map[string][]byte{}
+	for _, f := range reader.File {
+		rc, err := f.Open()
+		if assert.NoError(t, err) {
+			files[f.Name], _ = io.ReadAll(rc)
+			rc.Close()
+		}
+	}
+
+	assert.ElementsMatch(t, []string{"export.json", "profile.json", "bookings.json", "reviews.json", "sessions.json", "audit_log.json"}, slices.Collect(maps.Keys(files)))
+	assert.Contains(t, string(files["profile.json"]), "test@example.com")
+	assert.NotContains(t, string(files["profile.json"]), "secret-hash")
+
+	var bookings []exportBooking
+	if assert.NoError(t, json.Unmarshal(files["bookings.json"], &bookings)) && assert.Len(t, bookings, 1) {
+		assert.Equal(t, "Test Hotel", bookings[0].Hotel)
+		assert.Equal(t, payment.ID.String(), bookings[0].Payment.ID)
+	}
+
+	assert.Contains(t, string(files["audit_log.json"]), "192.0.2.1")
+	assert.NotContains(t, string(files["audit_log.json"]), "198.51.100.7")
+	assert.NotContains(t, string(files["audit_log.json"]), adminID.String())
+}
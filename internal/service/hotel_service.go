@@ -1,42 +1,212 @@
 package service
 
 import (
+	"errors"
 	"hotel-booking-api/internal/domain"
 	"hotel-booking-api/internal/repository"
+	"hotel-booking-api/pkg/pagination"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrHotelNotFound       = errors.New("hotel not found")
+	ErrHotelDeleted        = errors.New("the hotel is deleted; restore it first")
+	ErrNotDeleted          = errors.New("only deleted hotels and rooms can be restored or purged")
+	ErrHasUpcomingBookings = errors.New("cannot delete while upcoming bookings exist")
+	ErrHasBookingHistory   = errors.New("cannot purge with booking history; delete it instead")
+)
+
+const (
+	maxStayNights         = 30
+	defaultSearchRadiusKm = 10
+	minAutocompleteLength = 2
+	defaultAutocomplete   = 10
+	maxAutocomplete       = 20
 )
 
 type HotelService interface {
-	CreateHotel(hotel *domain.Hotel) error
-	UpdateHotel(hotel *domain.Hotel) error
-	ListHotel() ([]domain.Hotel, error)
+	CreateHotel(actor Actor, hotel *domain.Hotel) error
+	UpdateHotel(actor Actor, hotel *domain.Hotel) error
+	ListHotel(query pagination.Query, amenities []string) ([]domain.Hotel, int64, error)
 	GetHotelDetail(id string) (*domain.Hotel, error)
-	DeleteHotel(id string) error
+	SearchHotels(filter repository.HotelSearchFilter) ([]domain.HotelSearchResult, int64, error)
+	Autocomplete(text string, limit int) ([]domain.SearchSuggestion, error)
+	DeleteHotel(actor Actor, id string) error
+	ListDeletedHotels(query pagination.Query) ([]domain.Hotel, int64, error)
+	RestoreHotel(actor Actor, id string) (*domain.Hotel, error)
+	PurgeHotel(actor Actor, id string) error
 }
 
 type hotelService struct {
-	hotelRepo repository.HotelRepository
+	hotelRepo   repository.HotelRepository
+	bookingRepo repository.BookingRepository
+	access      hotelAccess
 }
 
-func NewHotelService(repo repository.HotelRepository) HotelService {
-	return &hotelService{hotelRepo: repo}
+func NewHotelService(repo repository.HotelRepository, bookingRepo repository.BookingRepository, staffRepo repository.HotelStaffRepository) HotelService {
+	return &hotelService{
+		hotelRepo:   repo,
+		bookingRepo: bookingRepo,
+		access:      hotelAccess{staffRepo: staffRepo},
+	}
 }
 
-func (s *hotelService) CreateHotel(hotel *domain.Hotel) error {
-	return s.hotelRepo.Create(hotel)
+func (s *hotelService) CreateHotel(actor Actor, hotel *domain.Hotel) error {
+	hotel.ID = uuid.New()
+
+	return s.hotelRepo.Create(hotel, auditEntry(actor, domain.AuditHotelCreate, domain.AuditEntityHotel, hotel.ID.String(), nil, hotel))
 }
 
-func (s *hotelService) UpdateHotel(hotel *domain.Hotel) error {
-	return s.hotelRepo.Update(hotel)
+func (s *hotelService) UpdateHotel(actor Actor, hotel *domain.Hotel) error {
+	if err := s.access.authorize(actor, hotel.ID.String(), domain.RoleHotelManager); err != nil {
+		return err
+	}
+
+	before, err := s.hotelRepo.FindByID(hotel.ID.String())
+	if err != nil {
+		return ErrHotelNotFound
+	}
+
+	return s.hotelRepo.Update(hotel, auditEntry(actor, domain.AuditHotelUpdate, domain.AuditEntityHotel, hotel.ID.String(), before, hotel))
 }
 
-func (s *hotelService) ListHotel() ([]domain.Hotel, error) {
-	return s.hotelRepo.FindAll()
+func (s *hotelService) ListHotel(query pagination.Query, amenities []string) ([]domain.Hotel, int64, error) {
+	return s.hotelRepo.FindAll(query, amenities)
 }
 
 func (s *hotelService) GetHotelDetail(id string) (*domain.Hotel, error) {
 	return s.hotelRepo.FindByID(id)
 }
 
-func (s *hotelService) DeleteHotel(id string) error {
-	return s.hotelRepo.Delete(id)
+// DeleteHotel soft-deletes a hotel and its rooms, which keeps their bookings
+// intact. It is refused while guests are still due to stay.
+func (s *hotelService) DeleteHotel(actor Actor, id string) error {
+	hotel, err := s.hotelRepo.FindByID(id)
+	if err != nil {
+		return ErrHotelNotFound
+	}
+
+	if err := s.access.authorize(actor, id); err != nil {
+		return err
+	}
+
+	upcoming, err := s.bookingRepo.CountUpcomingByHotel(id)
+	if err != nil {
+		return err
+	}
+	if upcoming > 0 {
+		return ErrHasUpcomingBookings
+	}
+
+	return s.hotelRepo.Delete(id, auditEntry(actor, domain.AuditHotelDelete, domain.AuditEntityHotel, id, hotel, nil))
+}
+
+func (s *hotelService) ListDeletedHotels(query pagination.Query) ([]domain.Hotel, int64, error) {
+	return s.hotelRepo.FindDeleted(query)
+}
+
+// RestoreHotel undeletes a hotel along with the rooms deleted with it. Rooms
+// deleted on their own beforehand stay deleted.
+func (s *hotelService) RestoreHotel(actor Actor, id string) (*domain.Hotel, error) {
+	hotel, err := s.hotelRepo.FindByIDWithDeleted(id)
+	if err != nil {
+		return nil, ErrHotelNotFound
+	}
+
+	if err := s.access.authorize(actor, id); err != nil {
+		return nil, err
+	}
+
+	if !hotel.DeletedAt.Valid {
+		return nil, ErrNotDeleted
+	}
+
+	audit := auditEntry(actor, domain.AuditHotelRestore, domain.AuditEntityHotel, id,
+		map[string]any{"deleted_at": hotel.DeletedAt.Time}, nil)
+	if err := s.hotelRepo.Restore(hotel, audit); err != nil {
+		return nil, err
+	}
+
+	return s.hotelRepo.FindByID(id)
+}
+
+// PurgeHotel permanently deletes a hotel that is already deleted. Hotels with
+// any booking history are kept, so bookings and payments are never lost.
+func (s *hotelService) PurgeHotel(actor Actor, id string) error {
+	hotel, err := s.hotelRepo.FindByIDWithDeleted(id)
+	if err != nil {
+		return ErrHotelNotFound
+	}
+
+	if err := s.access.authorize(actor, id); err != nil {
+		return err
+	}
+
+	if !hotel.DeletedAt.Valid {
+		return ErrNotDeleted
+	}
+
+	bookings, err := s.bookingRepo.CountByHotel(id)
+	if err != nil {
+		return err
+	}
+	if bookings > 0 {
+		return ErrHasBookingHistory
+	}
+
+	return s.hotelRepo.Purge(id, auditEntry(actor, domain.AuditHotelPurge, domain.AuditEntityHotel, id, hotel, nil))
+}
+
+func (s *hotelService) SearchHotels(filter repository.HotelSearchFilter) ([]domain.HotelSearchResult, int64, error) {
+	if (filter.CheckIn == nil) != (filter.CheckOut == nil) {
+		return nil, 0, errors.New("check-in and check-out must be provided together")
+	}
+
+	if filter.CheckIn != nil {
+		today := time.Now().Truncate(24 * time.Hour)
+		if filter.CheckIn.Before(today) {
+			return nil, 0, errors.New("check-in date cannot be in the past")
+		}
+		if !filter.CheckOut.After(*filter.CheckIn) {
+			return nil, 0, errors.New("check-out date must be after check-in date")
+		}
+		if filter.CheckOut.Sub(*filter.CheckIn) > maxStayNights*24*time.Hour {
+			return nil, 0, errors.New("maximum booking duration is 30 days")
+		}
+	}
+
+	if filter.MaxPrice > 0 && filter.MinPrice > filter.MaxPrice {
+		return nil, 0, errors.New("min_price cannot be greater than max_price")
+	}
+
+	if filter.Near != nil && filter.RadiusKm <= 0 {
+		filter.RadiusKm = defaultSearchRadiusKm
+	}
+
+	if filter.Query.Sort == "distance" && filter.Near == nil {
+		return nil, 0, errors.New("sorting by distance requires lat and lng")
+	}
+
+	filter.Text = strings.TrimSpace(filter.Text)
+	if filter.Query.Sort == "relevance" && filter.Text == "" {
+		return nil, 0, errors.New("sorting by relevance requires q")
+	}
+
+	return s.hotelRepo.Search(filter)
+}
+
+func (s *hotelService) Autocomplete(text string, limit int) ([]domain.SearchSuggestion, error) {
+	text = strings.TrimSpace(text)
+	if len([]rune(text)) < minAutocompleteLength {
+		return []domain.SearchSuggestion{}, nil
+	}
+
+	if limit < 1 || limit > maxAutocomplete {
+		limit = defaultAutocomplete
+	}
+
+	return s.hotelRepo.Autocomplete(text, limit)
 }
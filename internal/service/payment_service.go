@@ -7,7 +7,8 @@ import (
 )
 
 type PaymentService interface {
-	HandlePaymentCallback(bookingID, transactionID, status string) error
+	HandlePaymentCallback(actor Actor, bookingID, transactionID, status string) error
+	InitiatePayment(bookingID, method string) (*domain.Payment, error)
 }
 
 type paymentService struct {
@@ -24,7 +25,9 @@ func NewPaymentService(bookingRepo repository.BookingRepository, paymentRepo rep
 	}
 }
 
-func (s *paymentService) HandlePaymentCallback(bookingID, transactionID, status string) error {
+// HandlePaymentCallback records the provider's verdict on a payment. The
+// actor is the caller of the webhook, identified only by its request.
+func (s *paymentService) HandlePaymentCallback(actor Actor, bookingID, transactionID, status string) error {
 	payment, err := s.paymentRepo.FindByBookingID(bookingID)
 	if err != nil {
 		return errors.New("payment record not found")
@@ -35,6 +38,9 @@ func (s *paymentService) HandlePaymentCallback(bookingID, transactionID, status
 		return errors.New("booking not found")
 	}
 
+	before := paymentState(payment, booking)
+	action := domain.AuditPaymentSucceed
+
 	if status == "SUCCESS" {
 		payment.Status = domain.PaymentStatusSuccess
 		payment.TransactionID = transactionID
@@ -42,6 +48,7 @@ func (s *paymentService) HandlePaymentCallback(bookingID, transactionID, status
 	} else {
 		payment.Status = domain.PaymentStatusFailed
 		booking.Status = domain.BookingStatusCancelled
+		action = domain.AuditPaymentFail
 
 		room, err := s.roomRepo.FindByID(booking.RoomID.String())
 		if err == nil {
@@ -51,9 +58,43 @@ func (s *paymentService) HandlePaymentCallback(bookingID, transactionID, status
 
 	payment.TransactionID = transactionID
 
+	audit := auditEntry(actor, action, domain.AuditEntityPayment, payment.ID.String(), before, paymentState(payment, booking))
+
+	return s.paymentRepo.Settle(payment, booking, audit)
+}
+
+// paymentState is what the audit log records of a payment.
+func paymentState(payment *domain.Payment, booking *domain.Booking) map[string]any {
+	return map[string]any{
+		"status":         payment.Status,
+		"transaction_id": payment.TransactionID,
+		"booking_status": booking.Status,
+	}
+}
+
+func (s *paymentService) InitiatePayment(bookingID, method string) (*domain.Payment, error) {
+	booking, err := s.bookingRepo.FindByID(bookingID)
+	if err != nil {
+		return nil, errors.New("booking not found")
+	}
+
+	if booking.Status != domain.BookingStatusPending {
+		return nil, errors.New("booking is not awaiting payment")
+	}
+
+	payment, err := s.paymentRepo.FindByBookingID(bookingID)
+	if err != nil {
+		return nil, errors.New("payment record not found")
+	}
+
+	if payment.Status != domain.PaymentStatusPending {
+		return nil, errors.New("payment already processed")
+	}
+
+	payment.PaymentMethod = method
 	if err := s.paymentRepo.Update(payment); err != nil {
-		return err
+		return nil, err
 	}
 
-	return s.bookingRepo.Update(booking)
+	return payment, nil
 }
@@ -0,0 +1,270 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"hotel-booking-api/internal/domain"
+	"hotel-booking-api/internal/repository"
+	"hotel-booking-api/pkg/imaging"
+	"hotel-booking-api/pkg/logger"
+	"hotel-booking-api/pkg/storage"
+	"image"
+	"io"
+	"net/http"
+	"path"
+
+	"github.com/google/uuid"
+)
+
+const (
+	thumbnailSize    = 400
+	thumbnailQuality = 80
+	maxImagePixels   = 40_000_000
+)
+
+var (
+	ErrUnsupportedImage = errors.New("only JPEG and PNG images are supported")
+	ErrImageTooLarge    = errors.New("image exceeds the maximum upload size")
+)
+
+var imageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+}
+
+type PhotoService interface {
+	UploadHotelPhoto(hotelID string, file io.Reader) (*domain.Photo, error)
+	UploadRoomPhoto(roomID string, file io.Reader) (*domain.Photo, error)
+	ReorderHotelPhotos(hotelID string, photoIDs []string) ([]domain.Photo, error)
+	ReorderRoomPhotos(roomID string, photoIDs []string) ([]domain.Photo, error)
+	SetCoverPhoto(photoID string) (*domain.Photo, error)
+	DeletePhoto(photoID string) error
+}
+
+type photoService struct {
+	photoRepo      repository.PhotoRepository
+	hotelRepo      repository.HotelRepository
+	roomRepo       repository.RoomRepository
+	storage        storage.Storage
+	maxUploadBytes int64
+}
+
+func NewPhotoService(photoRepo repository.PhotoRepository, hotelRepo repository.HotelRepository, roomRepo repository.RoomRepository, store storage.Storage, maxUploadBytes int64) PhotoService {
+	return &photoService{
+		photoRepo:      photoRepo,
+		hotelRepo:      hotelRepo,
+		roomRepo:       roomRepo,
+		storage:        store,
+		maxUploadBytes: maxUploadBytes,
+	}
+}
+
+func (s *photoService) UploadHotelPhoto(hotelID string, file io.Reader) (*domain.Photo, error) {
+	hotel, err := s.hotelRepo.FindByID(hotelID)
+	if err != nil {
+		return nil, errors.New("hotel not found")
+	}
+
+	existing, err := s.photoRepo.FindByHotel(hotelID)
+	if err != nil {
+		return nil, err
+	}
+
+	photo := &domain.Photo{HotelID: hotel.ID}
+	prefix := path.Join("hotels", hotel.ID.String())
+
+	return photo, s.store(photo, prefix, file, existing)
+}
+
+func (s *photoService) UploadRoomPhoto(roomID string, file io.Reader) (*domain.Photo, error) {
+	room, err := s.roomRepo.FindByID(roomID)
+	if err != nil {
+		return nil, errors.New("room not found")
+	}
+
+	existing, err := s.photoRepo.FindByRoom(roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	photo := &domain.Photo{HotelID: room.HotelID, RoomID: &room.ID}
+	prefix := path.Join("hotels", room.HotelID.String(), "rooms", room.ID.String())
+
+	return photo, s.store(photo, prefix, file, existing)
+}
+
+// store validates the upload, writes the original and a JPEG thumbnail and
+// records the photo after any existing ones. The first photo becomes the
+// cover.
+func (s *photoService) store(photo *domain.Photo, prefix string, file io.Reader, existing []domain.Photo) error {
+	// Read one byte past the limit to tell an exact fit from an oversized file.
+	data, err := io.ReadAll(io.LimitReader(file, s.maxUploadBytes+1))
+	if err != nil {
+		return fmt.Errorf("failed to read upload: %w", err)
+	}
+	if int64(len(data)) > s.maxUploadBytes {
+		return ErrImageTooLarge
+	}
+
+	contentType := http.DetectContentType(data)
+	ext, ok := imageExtensions[contentType]
+	if !ok {
+		return ErrUnsupportedImage
+	}
+
+	// Check dimensions before decoding so oversized images cannot exhaust
+	// memory.
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return ErrUnsupportedImage
+	}
+	if config.Width*config.Height > maxImagePixels {
+		return ErrImageTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return ErrUnsupportedImage
+	}
+
+	thumbnail, err := imaging.EncodeJPEG(imaging.Thumbnail(img, thumbnailSize, thumbnailSize), thumbnailQuality)
+	if err != nil {
+		return fmt.Errorf("failed to create thumbnail: %w", err)
+	}
+
+	name := uuid.New().String()
+	photo.Key = path.Join(prefix, name+ext)
+	photo.ThumbnailKey = path.Join(prefix, name+"_thumb.jpg")
+
+	ctx := context.Background()
+	if err := s.storage.Put(ctx, photo.Key, bytes.NewReader(data), contentType); err != nil {
+		return fmt.Errorf("failed to store image: %w", err)
+	}
+	if err := s.storage.Put(ctx, photo.ThumbnailKey, bytes.NewReader(thumbnail), "image/jpeg"); err != nil {
+		s.removeFiles(photo)
+		return fmt.Errorf("failed to store thumbnail: %w", err)
+	}
+
+	photo.URL = s.storage.URL(photo.Key)
+	photo.ThumbnailURL = s.storage.URL(photo.ThumbnailKey)
+	photo.ContentType = contentType
+	photo.Size = int64(len(data))
+	photo.Width = config.Width
+	photo.Height = config.Height
+	photo.IsCover = len(existing) == 0
+	for _, p := range existing {
+		photo.Position = max(photo.Position, p.Position+1)
+	}
+
+	if err := s.photoRepo.Create(photo); err != nil {
+		s.removeFiles(photo)
+		return err
+	}
+
+	return nil
+}
+
+func (s *photoService) ReorderHotelPhotos(hotelID string, photoIDs []string) ([]domain.Photo, error) {
+	photos, err := s.photoRepo.FindByHotel(hotelID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.reorder(photos, photoIDs); err != nil {
+		return nil, err
+	}
+
+	return s.photoRepo.FindByHotel(hotelID)
+}
+
+func (s *photoService) ReorderRoomPhotos(roomID string, photoIDs []string) ([]domain.Photo, error) {
+	photos, err := s.photoRepo.FindByRoom(roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.reorder(photos, photoIDs); err != nil {
+		return nil, err
+	}
+
+	return s.photoRepo.FindByRoom(roomID)
+}
+
+// reorder requires photoIDs to be exactly the current photos in their new
+// order.
+func (s *photoService) reorder(photos []domain.Photo, photoIDs []string) error {
+	if len(photoIDs) != len(photos) {
+		return errors.New("photo_ids must list every photo exactly once")
+	}
+
+	current := make(map[string]bool, len(photos))
+	for _, photo := range photos {
+		current[photo.ID.String()] = true
+	}
+
+	positions := make(map[string]int, len(photoIDs))
+	for i, id := range photoIDs {
+		if !current[id] {
+			return errors.New("photo_ids must list every photo exactly once")
+		}
+		if _, dup := positions[id]; dup {
+			return errors.New("photo_ids must list every photo exactly once")
+		}
+		positions[id] = i
+	}
+
+	return s.photoRepo.UpdatePositions(positions)
+}
+
+func (s *photoService) SetCoverPhoto(photoID string) (*domain.Photo, error) {
+	photo, err := s.photoRepo.FindByID(photoID)
+	if err != nil {
+		return nil, errors.New("photo not found")
+	}
+
+	if err := s.photoRepo.SetCover(photo); err != nil {
+		return nil, err
+	}
+
+	return photo, nil
+}
+
+func (s *photoService) DeletePhoto(photoID string) error {
+	photo, err := s.photoRepo.FindByID(photoID)
+	if err != nil {
+		return errors.New("photo not found")
+	}
+
+	if err := s.photoRepo.Delete(photoID); err != nil {
+		return err
+	}
+	s.removeFiles(photo)
+
+	if !photo.IsCover {
+		return nil
+	}
+
+	// Promote the next photo so the hotel or room keeps a cover.
+	var remaining []domain.Photo
+	if photo.RoomID != nil {
+		remaining, err = s.photoRepo.FindByRoom(photo.RoomID.String())
+	} else {
+		remaining, err = s.photoRepo.FindByHotel(photo.HotelID.String())
+	}
+	if err != nil || len(remaining) == 0 {
+		return err
+	}
+
+	return s.photoRepo.SetCover(&remaining[0])
+}
+
+func (s *photoService) removeFiles(photo *domain.Photo) {
+	ctx := context.Background()
+	for _, key := range []string{photo.Key, photo.ThumbnailKey} {
+		if err := s.storage.Delete(ctx, key); err != nil {
+			logger.Warn("Failed to delete stored image", "key", key, "error", err)
+		}
+	}
+}
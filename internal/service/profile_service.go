@@ -0,0 +1,103 @@
+package service
+
+import (
+	"errors"
+	"hotel-booking-api/internal/domain"
+	"hotel-booking-api/internal/repository"
+	"hotel-booking-api/pkg/util"
+	"strings"
+	"time"
+)
+
+var ErrUpcomingBookings = errors.New("cancel your upcoming bookings before deleting your account")
+
+// ProfileUpdate is the full set of profile fields a user may edit. Empty
+// optional fields clear the stored value.
+type ProfileUpdate struct {
+	Name              string
+	Phone             string
+	PreferredCurrency string
+	PreferredLanguage string
+}
+
+// ProfileService lets signed-in users manage their own account.
+type ProfileService interface {
+	GetProfile(userID string) (*domain.User, error)
+	UpdateProfile(userID string, update ProfileUpdate) (*domain.User, error)
+	DeleteAccount(userID, password string) error
+}
+
+type profileService struct {
+	userRepo    repository.UserRepository
+	bookingRepo repository.BookingRepository
+}
+
+func NewProfileService(userRepo repository.UserRepository, bookingRepo repository.BookingRepository) ProfileService {
+	return &profileService{
+		userRepo:    userRepo,
+		bookingRepo: bookingRepo,
+	}
+}
+
+func (s *profileService) GetProfile(userID string) (*domain.User, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	return user, nil
+}
+
+func (s *profileService) UpdateProfile(userID string, update ProfileUpdate) (*domain.User, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	user.Name = strings.TrimSpace(update.Name)
+	user.Phone = strings.TrimSpace(update.Phone)
+	user.PreferredCurrency = update.PreferredCurrency
+	user.PreferredLanguage = update.PreferredLanguage
+
+	if err := s.userRepo.UpdateProfile(user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// DeleteAccount closes the account once the password is confirmed. Personal
+// data is anonymised rather than the row deleted, so past bookings and their
+// payments remain for the hotels' accounting without identifying the user.
+// Accounts with upcoming stays must cancel them first, and the last admin
+// cannot delete themselves.
+func (s *profileService) DeleteAccount(userID, password string) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+
+	if !util.CheckPassword(password, user.Password) {
+		return ErrWrongPassword
+	}
+
+	if user.Role == domain.RoleAdmin {
+		count, err := s.userRepo.CountActiveAdmins()
+		if err != nil {
+			return err
+		}
+		if count <= 1 {
+			return ErrLastAdmin
+		}
+	}
+
+	upcoming, err := s.bookingRepo.CountUpcomingByUser(userID)
+	if err != nil {
+		return err
+	}
+	if upcoming > 0 {
+		return ErrUpcomingBookings
+	}
+
+	return s.userRepo.Anonymize(userID, time.Now())
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"hotel-booking-api/internal/domain"
+	"hotel-booking-api/pkg/util"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestProfileService_DeleteAccount_WrongPassword(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewProfileService(mockRepo, nil)
+
+	hash, _ := util.HashPassword("password123")
+	user := &domain.User{ID: uuid.New(), Role: domain.RoleCustomer, Password: hash}
+	mockRepo.On("FindByID", user.ID.String()).Return(user, nil)
+
+	err := service.DeleteAccount(user.ID.String(), "wrong-password")
+
+	assert.ErrorIs(t, err, ErrWrongPassword)
+	mockRepo.AssertNotCalled(t, "Anonymize", mock.Anything, mock.Anything)
+}
+
+func TestProfileService_DeleteAccount_LastAdmin(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewProfileService(mockRepo, nil)
+
+	hash, _ := util.HashPassword("password123")
+	admin := &domain.User{ID: uuid.New(), Role: domain.RoleAdmin, Password: hash}
+	mockRepo.On("FindByID", admin.ID.String()).Return(admin, nil)
+	mockRepo.On("CountActiveAdmins").Return(int64(1), nil)
+
+	err := service.DeleteAccount(admin.ID.String(), "password123")
+
+	assert.ErrorIs(t, err, ErrLastAdmin)
+	mockRepo.AssertNotCalled(t, "Anonymize", mock.Anything, mock.Anything)
+}
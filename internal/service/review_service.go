@@ -0,0 +1,131 @@
+package service
+
+import (
+	"errors"
+	"hotel-booking-api/internal/domain"
+	"hotel-booking-api/internal/repository"
+	"hotel-booking-api/pkg/pagination"
+	"time"
+)
+
+var (
+	ErrReviewNotFound = errors.New("review not found")
+	ErrReviewExists   = errors.New("this booking has already been reviewed")
+)
+
+// ReviewInput carries the guest's scores, each from 1 to 5, and comment.
+type ReviewInput struct {
+	Rating      int
+	Cleanliness int
+	Location    int
+	Service     int
+	Comment     string
+}
+
+type ReviewService interface {
+	CreateReview(userID, bookingID string, input ReviewInput) (*domain.Review, error)
+	ListHotelReviews(hotelID string, query pagination.Query) ([]domain.Review, int64, error)
+	ListReviews(query pagination.Query) ([]domain.Review, int64, error)
+	ReplyToReview(actor Actor, reviewID, reply string) (*domain.Review, error)
+	ModerateReview(reviewID, status, note string) (*domain.Review, error)
+}
+
+type reviewService struct {
+	reviewRepo  repository.ReviewRepository
+	bookingRepo repository.BookingRepository
+	access      hotelAccess
+}
+
+func NewReviewService(reviewRepo repository.ReviewRepository, bookingRepo repository.BookingRepository, staffRepo repository.HotelStaffRepository) ReviewService {
+	return &reviewService{
+		reviewRepo:  reviewRepo,
+		bookingRepo: bookingRepo,
+		access:      hotelAccess{staffRepo: staffRepo},
+	}
+}
+
+func (s *reviewService) CreateReview(userID, bookingID string, input ReviewInput) (*domain.Review, error) {
+	booking, err := s.bookingRepo.FindByID(bookingID)
+	if err != nil {
+		return nil, ErrBookingNotFound
+	}
+
+	if booking.UserID == nil || booking.UserID.String() != userID {
+		return nil, ErrBookingForbidden
+	}
+
+	if booking.Status != domain.BookingStatusCompleted {
+		return nil, errors.New("only completed stays can be reviewed")
+	}
+
+	exists, err := s.reviewRepo.ExistsForBooking(bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrReviewExists
+	}
+
+	review := &domain.Review{
+		BookingID:   booking.ID,
+		HotelID:     booking.Room.HotelID,
+		UserID:      *booking.UserID,
+		Rating:      input.Rating,
+		Cleanliness: input.Cleanliness,
+		Location:    input.Location,
+		Service:     input.Service,
+		Comment:     input.Comment,
+		Status:      domain.ReviewStatusVisible,
+	}
+
+	if err := s.reviewRepo.Create(review); err != nil {
+		return nil, errors.New("failed to save review")
+	}
+
+	return s.reviewRepo.FindByID(review.ID.String())
+}
+
+func (s *reviewService) ListHotelReviews(hotelID string, query pagination.Query) ([]domain.Review, int64, error) {
+	return s.reviewRepo.ListVisibleByHotel(hotelID, query)
+}
+
+func (s *reviewService) ListReviews(query pagination.Query) ([]domain.Review, int64, error) {
+	return s.reviewRepo.List(query)
+}
+
+func (s *reviewService) ReplyToReview(actor Actor, reviewID, reply string) (*domain.Review, error) {
+	review, err := s.reviewRepo.FindByID(reviewID)
+	if err != nil {
+		return nil, ErrReviewNotFound
+	}
+
+	if err := s.access.authorize(actor, review.HotelID.String(), domain.RoleHotelManager); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	review.Reply = reply
+	review.RepliedAt = &now
+
+	if err := s.reviewRepo.Update(review); err != nil {
+		return nil, errors.New("failed to save reply")
+	}
+
+	return review, nil
+}
+
+func (s *reviewService) ModerateReview(reviewID, status, note string) (*domain.Review, error) {
+	review, err := s.reviewRepo.FindByID(reviewID)
+	if err != nil {
+		return nil, ErrReviewNotFound
+	}
+
+	review.Status = status
+	review.ModerationNote = note
+
+	if err := s.reviewRepo.Update(review); err != nil {
+		return nil, errors.New("failed to update review")
+	}
+
+	return review, nil
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"errors"
+	"hotel-booking-api/internal/domain"
+	"hotel-booking-api/pkg/pagination"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockReviewRepository struct {
+	mock.Mock
+}
+
+func (m *MockReviewRepository) Create(review *domain.Review) error {
+	args := m.Called(review)
+	return args.Error(0)
+}
+
+func (m *MockReviewRepository) Update(review *domain.Review) error {
+	args := m.Called(review)
+	return args.Error(0)
+}
+
+func (m *MockReviewRepository) FindByID(id string) (*domain.Review, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.Review), args.Error(1)
+}
+
+func (m *MockReviewRepository) ExistsForBooking(bookingID string) (bool, error) {
+	args := m.Called(bookingID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockReviewRepository) ListVisibleByHotel(hotelID string, query pagination.Query) ([]domain.Review, int64, error) {
+	args := m.Called(hotelID, query)
+	return args.Get(0).([]domain.Review), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockReviewRepository) List(query pagination.Query) ([]domain.Review, int64, error) {
+	args := m.Called(query)
+	return args.Get(0).([]domain.Review), args.Get(1).(int64), args.Error(2)
+}
+
+var fiveStars = ReviewInput{Rating: 5, Cleanliness: 5, Location: 5, Service: 5, Comment: "Lovely stay"}
+
+func completedStay(userID uuid.UUID) *domain.Booking {
+	return &domain.Booking{
+		ID:     uuid.New(),
+		UserID: &userID,
+		Status: domain.BookingStatusCompleted,
+		Room:   domain.Room{HotelID: uuid.New()},
+	}
+}
+
+func TestReviewService_CreateReview(t *testing.T) {
+	reviewRepo := new(MockReviewRepository)
+	bookingRepo := new(MockBookingRepository)
+	userID := uuid.New()
+	booking := completedStay(userID)
+	bookingRepo.On("FindByID", booking.ID.String()).Return(booking, nil)
+	reviewRepo.On("ExistsForBooking", booking.ID.String()).Return(false, nil)
+	reviewRepo.On("Create", mock.AnythingOfType("*domain.Review")).Return(nil)
+	reviewRepo.On("FindByID", mock.Anything).Return(&domain.Review{BookingID: booking.ID}, nil)
+	service := NewReviewService(reviewRepo, bookingRepo, nil)
+
+	_, err := service.CreateReview(userID.String(), booking.ID.String(), fiveStars)
+
+	assert.NoError(t, err)
+	review := reviewRepo.Calls[1].Arguments.Get(0).(*domain.Review)
+	assert.Equal(t, booking.Room.HotelID, review.HotelID)
+	assert.Equal(t, userID, review.UserID)
+	assert.Equal(t, domain.ReviewStatusVisible, review.Status)
+}
+
+func TestReviewService_CreateReview_OncePerBooking(t *testing.T) {
+	reviewRepo := new(MockReviewRepository)
+	bookingRepo := new(MockBookingRepository)
+	userID := uuid.New()
+	booking := completedStay(userID)
+	bookingRepo.On("FindByID", booking.ID.String()).Return(booking, nil)
+	reviewRepo.On("ExistsForBooking", booking.ID.String()).Return(true, nil)
+	service := NewReviewService(reviewRepo, bookingRepo, nil)
+
+	_, err := service.CreateReview(userID.String(), booking.ID.String(), fiveStars)
+
+	assert.ErrorIs(t, err, ErrReviewExists)
+	reviewRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestReviewService_CreateReview_OnlyCompletedStays(t *testing.T) {
+	for _, status := range []string{domain.BookingStatusPending, domain.BookingStatusConfirmed, domain.BookingStatusCancelled} {
+		reviewRepo := new(MockReviewRepository)
+		bookingRepo := new(MockBookingRepository)
+		userID := uuid.New()
+		booking := completedStay(userID)
+		booking.Status = status
+		bookingRepo.On("FindByID", booking.ID.String()).Return(booking, nil)
+		service := NewReviewService(reviewRepo, bookingRepo, nil)
+
+		_, err := service.CreateReview(userID.String(), booking.ID.String(), fiveStars)
+
+		assert.EqualError(t, err, "only completed stays can be reviewed", status)
+		reviewRepo.AssertNotCalled(t, "Create", mock.Anything)
+	}
+}
+
+func TestReviewService_CreateReview_OtherGuestsBooking(t *testing.T) {
+	reviewRepo := new(MockReviewRepository)
+	bookingRepo := new(MockBookingRepository)
+	booking := completedStay(uuid.New())
+	bookingRepo.On("FindByID", booking.ID.String()).Return(booking, nil)
+	service := NewReviewService(reviewRepo, bookingRepo, nil)
+
+	_, err := service.CreateReview(uuid.NewString(), booking.ID.String(), fiveStars)
+
+	assert.ErrorIs(t, err, ErrBookingForbidden)
+	reviewRepo.AssertNotCalled(t, "ExistsForBooking", mock.Anything)
+}
+
+func TestReviewService_CreateReview_GuestBooking(t *testing.T) {
+	reviewRepo := new(MockReviewRepository)
+	bookingRepo := new(MockBookingRepository)
+	booking := completedStay(uuid.New())
+	booking.UserID = nil
+	bookingRepo.On("FindByID", booking.ID.String()).Return(booking, nil)
+	service := NewReviewService(reviewRepo, bookingRepo, nil)
+
+	_, err := service.CreateReview(uuid.NewString(), booking.ID.String(), fiveStars)
+
+	assert.ErrorIs(t, err, ErrBookingForbidden)
+}
+
+func TestReviewService_ModerateReview(t *testing.T) {
+	reviewRepo := new(MockReviewRepository)
+	review := &domain.Review{ID: uuid.New(), Status: domain.ReviewStatusVisible}
+	reviewRepo.On("FindByID", review.ID.String()).Return(review, nil)
+	reviewRepo.On("Update", review).Return(nil)
+	service := NewReviewService(reviewRepo, new(MockBookingRepository), nil)
+
+	moderated, err := service.ModerateReview(review.ID.String(), domain.ReviewStatusHidden, "Spam")
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.ReviewStatusHidden, moderated.Status)
+	assert.Equal(t, "Spam", moderated.ModerationNote)
+	reviewRepo.AssertCalled(t, "Update", review)
+}
+
+func TestReviewService_ModerateReview_NotFound(t *testing.T) {
+	reviewRepo := new(MockReviewRepository)
+	reviewRepo.On("FindByID", "missing").Return(nil, gorm.ErrRecordNotFound)
+	service := NewReviewService(reviewRepo, new(MockBookingRepository), nil)
+
+	_, err := service.ModerateReview("missing", domain.ReviewStatusHidden, "")
+
+	assert.ErrorIs(t, err, ErrReviewNotFound)
+}
+
+func TestReviewService_ModerateReview_UpdateFailure(t *testing.T) {
+	reviewRepo := new(MockReviewRepository)
+	review := &domain.Review{ID: uuid.New(), Status: domain.ReviewStatusVisible}
+	reviewRepo.On("FindByID", review.ID.String()).Return(review, nil)
+	reviewRepo.On("Update", review).Return(errors.New("connection refused"))
+	service := NewReviewService(reviewRepo, new(MockBookingRepository), nil)
+
+	_, err := service.ModerateReview(review.ID.String(), domain.ReviewStatusFlagged, "")
+
+	assert.EqualError(t, err, "failed to update review")
+}
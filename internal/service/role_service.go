@@ -0,0 +1,254 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"hotel-booking-api/internal/domain"
+	"hotel-booking-api/internal/repository"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+var ErrRoleNotFound = errors.New("role not found")
+
+type RoleService interface {
+	ListPermissions() ([]domain.Permission, error)
+	ListRoles() ([]domain.Role, error)
+	CreateRole(actor Actor, name, description string, permissions []string) (*domain.Role, error)
+	UpdateRole(actor Actor, id, name, description string, permissions []string) (*domain.Role, error)
+	DeleteRole(actor Actor, id string) error
+	GetUserAccess(userID string) (*UserAccess, error)
+	SetUserRoles(actor Actor, userID string, roleIDs []string) (*UserAccess, error)
+	ResolvePermissions(userID, role string) ([]string, error)
+}
+
+// UserAccess is a user's custom roles and the effective permissions they hold
+// together with their system role.
+type UserAccess struct {
+	User        *domain.User
+	Roles       []domain.Role
+	Permissions []string
+}
+
+type roleService struct {
+	roleRepo repository.RoleRepository
+	userRepo repository.UserRepository
+}
+
+func NewRoleService(roleRepo repository.RoleRepository, userRepo repository.UserRepository) RoleService {
+	return &roleService{
+		roleRepo: roleRepo,
+		userRepo: userRepo,
+	}
+}
+
+func (s *roleService) ListPermissions() ([]domain.Permission, error) {
+	return s.roleRepo.ListPermissions()
+}
+
+func (s *roleService) ListRoles() ([]domain.Role, error) {
+	return s.roleRepo.ListRoles()
+}
+
+func (s *roleService) CreateRole(actor Actor, name, description string, codes []string) (*domain.Role, error) {
+	if err := requireHeld(actor, codes); err != nil {
+		return nil, err
+	}
+
+	permissions, err := findPermissions(s.roleRepo, codes)
+	if err != nil {
+		return nil, err
+	}
+
+	role := &domain.Role{
+		ID:          uuid.New(),
+		Name:        strings.TrimSpace(name),
+		Description: description,
+		Permissions: permissions,
+	}
+
+	if _, system := domain.SystemRolePermissions[role.Name]; system || role.Name == domain.RoleAdmin {
+		return nil, errors.New("role name is reserved for a system role")
+	}
+
+	audit := auditEntry(actor, domain.AuditRoleCreate, domain.AuditEntityRole, role.ID.String(), nil, roleState(role))
+	if err := s.roleRepo.CreateRole(role, audit); err != nil {
+		return nil, errors.New("role name already exists")
+	}
+
+	return role, nil
+}
+
+func (s *roleService) UpdateRole(actor Actor, id, name, description string, codes []string) (*domain.Role, error) {
+	role, err := s.roleRepo.FindRoleByID(id)
+	if err != nil {
+		return nil, ErrRoleNotFound
+	}
+
+	if role.IsSystem {
+		return nil, errors.New("system roles cannot be modified")
+	}
+	if err := requireHeld(actor, append(role.PermissionCodes(), codes...)); err != nil {
+		return nil, err
+	}
+
+	permissions, err := findPermissions(s.roleRepo, codes)
+	if err != nil {
+		return nil, err
+	}
+
+	before := roleState(role)
+	role.Name = strings.TrimSpace(name)
+	role.Description = description
+	after := roleState(&domain.Role{Name: role.Name, Description: role.Description, Permissions: permissions})
+
+	audit := auditEntry(actor, domain.AuditRoleUpdate, domain.AuditEntityRole, id, before, after)
+	if err := s.roleRepo.UpdateRole(role, permissions, audit); err != nil {
+		return nil, errors.New("failed to update role")
+	}
+
+	return role, nil
+}
+
+func (s *roleService) DeleteRole(actor Actor, id string) error {
+	role, err := s.roleRepo.FindRoleByID(id)
+	if err != nil {
+		return ErrRoleNotFound
+	}
+
+	if role.IsSystem {
+		return errors.New("system roles cannot be deleted")
+	}
+
+	return s.roleRepo.DeleteRole(id, auditEntry(actor, domain.AuditRoleDelete, domain.AuditEntityRole, id, roleState(role), nil))
+}
+
+func (s *roleService) GetUserAccess(userID string) (*UserAccess, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	roles, err := s.roleRepo.FindUserRoles(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions, err := s.ResolvePermissions(userID, user.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UserAccess{User: user, Roles: roles, Permissions: permissions}, nil
+}
+
+// SetUserRoles replaces the user's custom roles. System roles follow the
+// user's account role and cannot be granted here.
+func (s *roleService) SetUserRoles(actor Actor, userID string, roleIDs []string) (*UserAccess, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	roles, err := s.roleRepo.FindRolesByIDs(roleIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(roles) != len(slices.Compact(slices.Sorted(slices.Values(roleIDs)))) {
+		return nil, ErrRoleNotFound
+	}
+	for _, role := range roles {
+		if role.IsSystem {
+			return nil, fmt.Errorf("system role %s cannot be assigned directly", role.Name)
+		}
+		if err := requireHeld(actor, role.PermissionCodes()); err != nil {
+			return nil, err
+		}
+	}
+
+	current, err := s.roleRepo.FindUserRoles(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	audit := auditEntry(actor, domain.AuditUserRolesSet, domain.AuditEntityUser, userID,
+		map[string]any{"roles": roleNames(current)}, map[string]any{"roles": roleNames(roles)})
+	if err := s.roleRepo.ReplaceUserRoles(user, roles, audit); err != nil {
+		return nil, errors.New("failed to assign roles")
+	}
+
+	return s.GetUserAccess(userID)
+}
+
+func (s *roleService) ResolvePermissions(userID, role string) ([]string, error) {
+	permissions, err := s.roleRepo.ResolvePermissions(userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.Sort(permissions)
+	return permissions, nil
+}
+
+// requireHeld rejects permissions the actor does not hold, so managing roles
+// cannot be used to grant anyone, the actor included, more than the actor has.
+func requireHeld(actor Actor, codes []string) error {
+	for _, code := range codes {
+		if !actor.Can(code) {
+			return ErrPermissionDenied
+		}
+	}
+
+	return nil
+}
+
+// roleState is what the audit log records of a role.
+func roleState(role *domain.Role) map[string]any {
+	permissions := role.PermissionCodes()
+	slices.Sort(permissions)
+
+	return map[string]any{
+		"name":        role.Name,
+		"description": role.Description,
+		"permissions": permissions,
+	}
+}
+
+func roleNames(roles []domain.Role) []string {
+	names := make([]string, len(roles))
+	for i, role := range roles {
+		names[i] = role.Name
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// findPermissions loads the permissions with the given codes, failing if any
+// code is unknown.
+func findPermissions(roleRepo repository.RoleRepository, codes []string) ([]domain.Permission, error) {
+	permissions, err := roleRepo.FindPermissionsByCodes(codes)
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]bool, len(permissions))
+	for _, permission := range permissions {
+		known[permission.Code] = true
+	}
+
+	var unknown []string
+	for _, code := range codes {
+		if !known[code] {
+			unknown = append(unknown, code)
+		}
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("unknown permissions: %s", strings.Join(unknown, ", "))
+	}
+
+	return permissions, nil
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"hotel-booking-api/internal/domain"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRoleRepository struct {
+	mock.Mock
+}
+
+func (m *MockRoleRepository) ListPermissions() ([]domain.Permission, error) {
+	args := m.Called()
+	return args.Get(0).([]domain.Permission), args.Error(1)
+}
+
+func (m *MockRoleRepository) FindPermissionsByCodes(codes []string) ([]domain.Permission, error) {
+	args := m.Called(codes)
+	return args.Get(0).([]domain.Permission), args.Error(1)
+}
+
+func (m *MockRoleRepository) ListRoles() ([]domain.Role, error) {
+	args := m.Called()
+	return args.Get(0).([]domain.Role), args.Error(1)
+}
+
+func (m *MockRoleRepository) FindRoleByID(id string) (*domain.Role, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.Role), args.Error(1)
+}
+
+func (m *MockRoleRepository) FindRolesByIDs(ids []string) ([]domain.Role, error) {
+	args := m.Called(ids)
+	return args.Get(0).([]domain.Role), args.Error(1)
+}
+
+func (m *MockRoleRepository) CreateRole(role *domain.Role, audit *domain.AuditLog) error {
+	args := m.Called(role, audit)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) UpdateRole(role *domain.Role, permissions []domain.Permission, audit *domain.AuditLog) error {
+	args := m.Called(role, permissions, audit)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) DeleteRole(id string, audit *domain.AuditLog) error {
+	args := m.Called(id, audit)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) FindUserRoles(userID string) ([]domain.Role, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]domain.Role), args.Error(1)
+}
+
+func (m *MockRoleRepository) ReplaceUserRoles(user *domain.User, roles []domain.Role, audit *domain.AuditLog) error {
+	args := m.Called(user, roles, audit)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) ResolvePermissions(userID, systemRole string) ([]string, error) {
+	args := m.Called(userID, systemRole)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+var auditReader = domain.Permission{ID: uuid.New(), Code: domain.PermAuditRead}
+
+func TestRoleService_CreateRole_ReservedNames(t *testing.T) {
+	roleRepo := new(MockRoleRepository)
+	roleRepo.On("FindPermissionsByCodes", []string{domain.PermAuditRead}).Return([]domain.Permission{auditReader}, nil)
+	service := NewRoleService(roleRepo, new(MockUserRepository))
+
+	for _, name := range []string{domain.RoleAdmin, domain.RoleCustomer, domain.RoleStaff, " " + domain.RoleHotelManager + " "} {
+		_, err := service.CreateRole(Actor{Role: domain.RoleAdmin}, name, "", []string{domain.PermAuditRead})
+		assert.EqualError(t, err, "role name is reserved for a system role", name)
+	}
+	roleRepo.AssertNotCalled(t, "CreateRole", mock.Anything, mock.Anything)
+}
+
+func TestRoleService_CreateRole_UnknownPermission(t *testing.T) {
+	roleRepo := new(MockRoleRepository)
+	codes := []string{domain.PermAuditRead, "hotel:teleport"}
+	roleRepo.On("FindPermissionsByCodes", codes).Return([]domain.Permission{auditReader}, nil)
+	service := NewRoleService(roleRepo, new(MockUserRepository))
+
+	_, err := service.CreateRole(Actor{Role: domain.RoleAdmin}, "Auditor", "", codes)
+
+	assert.EqualError(t, err, "unknown permissions: hotel:teleport")
+	roleRepo.AssertNotCalled(t, "CreateRole", mock.Anything, mock.Anything)
+}
+
+func TestRoleService_CreateRole_Audited(t *testing.T) {
+	roleRepo := new(MockRoleRepository)
+	roleRepo.On("FindPermissionsByCodes", []string{domain.PermAuditRead}).Return([]domain.Permission{auditReader}, nil)
+	roleRepo.On("CreateRole", mock.AnythingOfType("*domain.Role"), mock.AnythingOfType("*domain.AuditLog")).Return(nil)
+	service := NewRoleService(roleRepo, new(MockUserRepository))
+
+	role, err := service.CreateRole(Actor{Role: domain.RoleAdmin}, " Auditor ", "Reads the audit log", []string{domain.PermAuditRead})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Auditor", role.Name)
+	assert.False(t, role.IsSystem)
+
+	audit := roleRepo.Calls[1].Arguments.Get(1).(*domain.AuditLog)
+	assert.Equal(t, domain.AuditRoleCreate, audit.Action)
+	assert.Equal(t, role.ID.String(), audit.EntityID)
+	assert.Equal(t, []any{domain.PermAuditRead}, audit.Changes["permissions"].To)
+}
+
+func TestRoleService_UpdateRole_SystemRole(t *testing.T) {
+	roleRepo := new(MockRoleRepository)
+	system := &domain.Role{ID: uuid.New(), Name: domain.RoleCustomer, IsSystem: true}
+	roleRepo.On("FindRoleByID", system.ID.String()).Return(system, nil)
+	service := NewRoleService(roleRepo, new(MockUserRepository))
+
+	_, err := service.UpdateRole(Actor{Role: domain.RoleAdmin}, system.ID.String(), "Customer", "", []string{domain.PermAuditRead})
+	assert.EqualError(t, err, "system roles cannot be modified")
+
+	err = service.DeleteRole(Actor{Role: domain.RoleAdmin}, system.ID.String())
+	assert.EqualError(t, err, "system roles cannot be deleted")
+}
+
+func TestRoleService_SetUserRoles_RejectsSystemRoles(t *testing.T) {
+	roleRepo := new(MockRoleRepository)
+	userRepo := new(MockUserRepository)
+	user := &domain.User{ID: uuid.New(), Role: domain.RoleCustomer}
+	system := domain.Role{ID: uuid.New(), Name: domain.RoleHotelManager, IsSystem: true}
+	userRepo.On("FindByID", user.ID.String()).Return(user, nil)
+	roleRepo.On("FindRolesByIDs", []string{system.ID.String()}).Return([]domain.Role{system}, nil)
+	service := NewRoleService(roleRepo, userRepo)
+
+	_, err := service.SetUserRoles(Actor{Role: domain.RoleAdmin}, user.ID.String(), []string{system.ID.String()})
+
+	assert.EqualError(t, err, "system role HOTEL_MANAGER cannot be assigned directly")
+	roleRepo.AssertNotCalled(t, "ReplaceUserRoles", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRoleService_SetUserRoles_UnknownRole(t *testing.T) {
+	roleRepo := new(MockRoleRepository)
+	userRepo := new(MockUserRepository)
+	user := &domain.User{ID: uuid.New(), Role: domain.RoleCustomer}
+	ids := []string{uuid.NewString(), uuid.NewString()}
+	userRepo.On("FindByID", user.ID.String()).Return(user, nil)
+	roleRepo.On("FindRolesByIDs", ids).Return([]domain.Role{{ID: uuid.MustParse(ids[0]), Name: "Auditor"}}, nil)
+	service := NewRoleService(roleRepo, userRepo)
+
+	_, err := service.SetUserRoles(Actor{Role: domain.RoleAdmin}, user.ID.String(), ids)
+
+	assert.ErrorIs(t, err, ErrRoleNotFound)
+}
+
+func TestRoleService_ResolvePermissions_CustomRoleGrants(t *testing.T) {
+	roleRepo := new(MockRoleRepository)
+	userID := uuid.NewString()
+	roleRepo.On("ResolvePermissions", userID, domain.RoleCustomer).
+		Return([]string{domain.PermReviewWrite, domain.PermAuditRead, domain.PermBookingCreate}, nil)
+	service := NewRoleService(roleRepo, new(MockUserRepository))
+
+	permissions, err := service.ResolvePermissions(userID, domain.RoleCustomer)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{domain.PermAuditRead, domain.PermBookingCreate, domain.PermReviewWrite}, permissions)
+
+	actor := Actor{UserID: userID, Role: domain.RoleCustomer, Permissions: permissions}
+	assert.True(t, actor.Can(domain.PermAuditRead))
+	assert.False(t, actor.Can(domain.PermUserManage))
+}
+
+func TestActor_Can_AdminHoldsEveryPermission(t *testing.T) {
+	admin := Actor{Role: domain.RoleAdmin}
+
+	for code := range domain.PermissionCatalogue {
+		assert.True(t, admin.Can(code), code)
+	}
+	assert.False(t, Actor{Role: domain.RoleHotelManager}.Can(domain.PermRoleManage))
+}
+
+func TestRoleService_CreateRole_CannotGrantUnheldPermissions(t *testing.T) {
+	roleRepo := new(MockRoleRepository)
+	service := NewRoleService(roleRepo, new(MockUserRepository))
+	manager := Actor{Role: domain.RoleCustomer, Permissions: []string{domain.PermRoleManage, domain.PermAuditRead}}
+
+	_, err := service.CreateRole(manager, "Superuser", "", []string{domain.PermAuditRead, domain.PermUserManage})
+
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+	roleRepo.AssertNotCalled(t, "CreateRole", mock.Anything, mock.Anything)
+}
+
+func TestRoleService_UpdateRole_CannotGrantUnheldPermissions(t *testing.T) {
+	roleRepo := new(MockRoleRepository)
+	role := &domain.Role{ID: uuid.New(), Name: "Auditor", Permissions: []domain.Permission{auditReader}}
+	roleRepo.On("FindRoleByID", role.ID.String()).Return(role, nil)
+	service := NewRoleService(roleRepo, new(MockUserRepository))
+	manager := Actor{Role: domain.RoleCustomer, Permissions: []string{domain.PermRoleManage, domain.PermAuditRead}}
+
+	_, err := service.UpdateRole(manager, role.ID.String(), "Auditor", "", []string{domain.PermAPIKeyManage})
+
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+	roleRepo.AssertNotCalled(t, "UpdateRole", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRoleService_SetUserRoles_CannotAssignUnheldPermissions(t *testing.T) {
+	roleRepo := new(MockRoleRepository)
+	userRepo := new(MockUserRepository)
+	user := &domain.User{ID: uuid.New(), Role: domain.RoleCustomer}
+	powerful := domain.Role{ID: uuid.New(), Name: "Support", Permissions: []domain.Permission{{Code: domain.PermUserManage}}}
+	userRepo.On("FindByID", user.ID.String()).Return(user, nil)
+	roleRepo.On("FindRolesByIDs", []string{powerful.ID.String()}).Return([]domain.Role{powerful}, nil)
+	service := NewRoleService(roleRepo, userRepo)
+	manager := Actor{UserID: user.ID.String(), Role: domain.RoleCustomer, Permissions: []string{domain.PermRoleManage}}
+
+	_, err := service.SetUserRoles(manager, user.ID.String(), []string{powerful.ID.String()})
+
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+	roleRepo.AssertNotCalled(t, "ReplaceUserRoles", mock.Anything, mock.Anything, mock.Anything)
+}
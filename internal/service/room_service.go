@@ -4,35 +4,56 @@ import (
 	"errors"
 	"hotel-booking-api/internal/domain"
 	"hotel-booking-api/internal/repository"
+	"hotel-booking-api/pkg/pagination"
+
+	"github.com/google/uuid"
 )
 
+const defaultRoomCapacity = 2
+
+var ErrRoomNotFound = errors.New("room not found")
+
 type RoomService interface {
-	CreateRoom(room *domain.Room) error
-	UpdateRoom(room *domain.Room) error
-	GetRoomsByHotel(hotelID string) ([]domain.Room, error)
+	CreateRoom(actor Actor, room *domain.Room) error
+	UpdateRoom(actor Actor, room *domain.Room) error
+	GetRoomsByHotel(hotelID string, amenities []string, query pagination.Query) ([]domain.Room, int64, error)
 	GetRoomByID(id string) (*domain.Room, error)
 	SearchAvailableRooms(hotelID string, checkIn, checkOut string) ([]domain.Room, error)
 	CheckAvailability(roomID string, checkIn, checkOut string) (bool, error)
+	DeleteRoom(actor Actor, id string) error
+	RestoreRoom(actor Actor, id string) (*domain.Room, error)
+	PurgeRoom(actor Actor, id string) error
 }
 
 type roomService struct {
 	roomRepo    repository.RoomRepository
 	hotelRepo   repository.HotelRepository
 	bookingRepo repository.BookingRepository
+	access      hotelAccess
 }
 
-func NewRoomService(roomRepo repository.RoomRepository, hotelRepo repository.HotelRepository) RoomService {
+func NewRoomService(roomRepo repository.RoomRepository, hotelRepo repository.HotelRepository, bookingRepo repository.BookingRepository, staffRepo repository.HotelStaffRepository) RoomService {
 	return &roomService{
-		roomRepo:  roomRepo,
-		hotelRepo: hotelRepo,
+		roomRepo:    roomRepo,
+		hotelRepo:   hotelRepo,
+		bookingRepo: bookingRepo,
+		access:      hotelAccess{staffRepo: staffRepo},
 	}
 }
 
-func (s *roomService) CreateRoom(room *domain.Room) error {
+func (s *roomService) CreateRoom(actor Actor, room *domain.Room) error {
 	if _, err := s.hotelRepo.FindByID(room.HotelID.String()); err != nil {
 		return errors.New("hotel not found")
 	}
 
+	if err := s.access.authorize(actor, room.HotelID.String(), domain.RoleHotelManager); err != nil {
+		return err
+	}
+
+	if !actor.Can(domain.PermRateWrite) {
+		return ErrPermissionDenied
+	}
+
 	if room.PricePerNight <= 0 {
 		return errors.New("price per night must be greater than 0")
 	}
@@ -45,13 +66,27 @@ func (s *roomService) CreateRoom(room *domain.Room) error {
 		return errors.New("room type is required")
 	}
 
-	return s.roomRepo.Create(room)
+	if room.Capacity == 0 {
+		room.Capacity = defaultRoomCapacity
+	}
+
+	room.ID = uuid.New()
+
+	return s.roomRepo.Create(room, auditEntry(actor, domain.AuditRoomCreate, domain.AuditEntityRoom, room.ID.String(), nil, room))
 }
 
-func (s *roomService) UpdateRoom(room *domain.Room) error {
+func (s *roomService) UpdateRoom(actor Actor, room *domain.Room) error {
 	existingRoom, err := s.roomRepo.FindByID(room.ID.String())
 	if err != nil {
-		return errors.New("room not found")
+		return ErrRoomNotFound
+	}
+
+	if err := s.access.authorize(actor, existingRoom.HotelID.String(), domain.RoleHotelManager); err != nil {
+		return err
+	}
+
+	if room.PricePerNight != existingRoom.PricePerNight && !actor.Can(domain.PermRateWrite) {
+		return ErrPermissionDenied
 	}
 
 	if room.PricePerNight <= 0 {
@@ -68,51 +103,118 @@ func (s *roomService) UpdateRoom(room *domain.Room) error {
 
 	room.HotelID = existingRoom.HotelID
 
-	return s.roomRepo.Update(room)
+	return s.roomRepo.Update(room, auditEntry(actor, domain.AuditRoomUpdate, domain.AuditEntityRoom, room.ID.String(), existingRoom, room))
 }
 
-func (s *roomService) GetRoomsByHotel(hotelID string) ([]domain.Room, error) {
+func (s *roomService) GetRoomsByHotel(hotelID string, amenities []string, query pagination.Query) ([]domain.Room, int64, error) {
 	if _, err := s.hotelRepo.FindByID(hotelID); err != nil {
-		return nil, errors.New("hotel not found")
+		return nil, 0, errors.New("hotel not found")
 	}
 
-	return s.roomRepo.FindByHotel(hotelID)
+	return s.roomRepo.ListByHotel(hotelID, amenities, query)
 }
 
 func (s *roomService) GetRoomByID(id string) (*domain.Room, error) {
 	room, err := s.roomRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("room not found")
+		return nil, ErrRoomNotFound
 	}
 
 	return room, nil
 }
 
 func (s *roomService) SearchAvailableRooms(hotelID string, checkIn, checkOut string) ([]domain.Room, error) {
-	rooms, err := s.roomRepo.FindByHotel(hotelID)
+	return s.roomRepo.FindAvailableByHotel(hotelID, checkIn, checkOut)
+}
+
+func (s *roomService) CheckAvailability(roomID string, checkIn, checkOut string) (bool, error) {
+	room, err := s.roomRepo.FindByID(roomID)
 	if err != nil {
+		return false, ErrRoomNotFound
+	}
+
+	if room.Availability <= 0 {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// DeleteRoom soft-deletes a room, taking it out of search and booking while
+// keeping its bookings. It is refused while guests are still due to stay.
+func (s *roomService) DeleteRoom(actor Actor, id string) error {
+	room, err := s.roomRepo.FindByID(id)
+	if err != nil {
+		return ErrRoomNotFound
+	}
+
+	if err := s.access.authorize(actor, room.HotelID.String(), domain.RoleHotelManager); err != nil {
+		return err
+	}
+
+	upcoming, err := s.bookingRepo.CountUpcomingByRoom(id)
+	if err != nil {
+		return err
+	}
+	if upcoming > 0 {
+		return ErrHasUpcomingBookings
+	}
+
+	return s.roomRepo.Delete(id, auditEntry(actor, domain.AuditRoomDelete, domain.AuditEntityRoom, id, room, nil))
+}
+
+// RestoreRoom undeletes a room. Rooms of a deleted hotel come back with the
+// hotel instead.
+func (s *roomService) RestoreRoom(actor Actor, id string) (*domain.Room, error) {
+	room, err := s.roomRepo.FindByIDWithDeleted(id)
+	if err != nil {
+		return nil, ErrRoomNotFound
+	}
+
+	if err := s.access.authorize(actor, room.HotelID.String()); err != nil {
 		return nil, err
 	}
 
-	var availableRooms []domain.Room
-	for _, room := range rooms {
-		if room.Availability > 0 {
-			availableRooms = append(availableRooms, room)
-		}
+	if !room.DeletedAt.Valid {
+		return nil, ErrNotDeleted
+	}
+
+	if _, err := s.hotelRepo.FindByID(room.HotelID.String()); err != nil {
+		return nil, ErrHotelDeleted
 	}
 
-	return availableRooms, nil
+	audit := auditEntry(actor, domain.AuditRoomRestore, domain.AuditEntityRoom, id,
+		map[string]any{"deleted_at": room.DeletedAt.Time}, nil)
+	if err := s.roomRepo.Restore(id, audit); err != nil {
+		return nil, err
+	}
+
+	return s.roomRepo.FindByID(id)
 }
 
-func (s *roomService) CheckAvailability(roomID string, checkIn, checkOut string) (bool, error) {
-	room, err := s.roomRepo.FindByID(roomID)
+// PurgeRoom permanently deletes a room that is already deleted and has never
+// been booked.
+func (s *roomService) PurgeRoom(actor Actor, id string) error {
+	room, err := s.roomRepo.FindByIDWithDeleted(id)
 	if err != nil {
-		return false, errors.New("room not found")
+		return ErrRoomNotFound
 	}
 
-	if room.Availability <= 0 {
-		return false, nil
+	if err := s.access.authorize(actor, room.HotelID.String()); err != nil {
+		return err
 	}
 
-	return true, nil
+	if !room.DeletedAt.Valid {
+		return ErrNotDeleted
+	}
+
+	bookings, err := s.bookingRepo.CountByRoom(id)
+	if err != nil {
+		return err
+	}
+	if bookings > 0 {
+		return ErrHasBookingHistory
+	}
+
+	return s.roomRepo.Purge(id, auditEntry(actor, domain.AuditRoomPurge, domain.AuditEntityRoom, id, room, nil))
 }
@@ -0,0 +1,287 @@
+package service
+
+import (
+	"errors"
+	"hotel-booking-api/internal/domain"
+	"hotel-booking-api/internal/repository"
+	"hotel-booking-api/pkg/util"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUserNotFound       = errors.New("user not found")
+	ErrEmailTaken         = errors.New("email already registered")
+	ErrCannotModifySelf   = errors.New("you cannot change your own role or status")
+	ErrLastAdmin          = errors.New("at least one active admin account is required")
+	ErrAccountDeactivated = errors.New("this account has been deactivated")
+)
+
+// NewUserInput describes an account provisioned by an administrator.
+// HotelID is required for HOTEL_MANAGER and STAFF, who are assigned to that
+// hotel.
+type NewUserInput struct {
+	Name     string
+	Email    string
+	Password string
+	Role     string
+	HotelID  string
+}
+
+type UserService interface {
+	ListUsers(filter repository.UserFilter) ([]domain.User, int64, error)
+	GetUser(id string) (*domain.User, error)
+	CreateUser(actor Actor, input NewUserInput) (*domain.User, error)
+	ChangeRole(actor Actor, id, role string) (*domain.User, error)
+	DeactivateUser(actor Actor, id string) (*domain.User, error)
+	ReactivateUser(actor Actor, id string) (*domain.User, error)
+}
+
+type userService struct {
+	DB          *gorm.DB
+	userRepo    repository.UserRepository
+	staffRepo   repository.HotelStaffRepository
+	hotelRepo   repository.HotelRepository
+	sessionRepo repository.SessionRepository
+}
+
+func NewUserService(db *gorm.DB, userRepo repository.UserRepository, staffRepo repository.HotelStaffRepository, hotelRepo repository.HotelRepository, sessionRepo repository.SessionRepository) UserService {
+	return &userService{
+		DB:          db,
+		userRepo:    userRepo,
+		staffRepo:   staffRepo,
+		hotelRepo:   hotelRepo,
+		sessionRepo: sessionRepo,
+	}
+}
+
+func (s *userService) ListUsers(filter repository.UserFilter) ([]domain.User, int64, error) {
+	return s.userRepo.List(filter)
+}
+
+func (s *userService) GetUser(id string) (*domain.User, error) {
+	user, err := s.userRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	return user, nil
+}
+
+// CreateUser provisions an account of any role. Only admins may create other
+// admins. Hotel managers and staff are created as customers and then assigned
+// to their hotel, which gives them their role; the account and assignment are
+// created together or not at all. The address is treated as verified since an
+// administrator vouched for it.
+func (s *userService) CreateUser(actor Actor, input NewUserInput) (*domain.User, error) {
+	if input.Role == domain.RoleAdmin && !actor.IsAdmin() {
+		return nil, ErrPermissionDenied
+	}
+
+	var hotel *domain.Hotel
+	if input.Role == domain.RoleHotelManager || input.Role == domain.RoleStaff {
+		if input.HotelID == "" {
+			return nil, errors.New("hotel_id is required for hotel managers and staff")
+		}
+
+		var err error
+		if hotel, err = s.hotelRepo.FindByID(input.HotelID); err != nil {
+			return nil, errors.New("hotel not found")
+		}
+	}
+
+	email := strings.TrimSpace(input.Email)
+	if _, err := s.userRepo.FindByEmail(email); err == nil {
+		return nil, ErrEmailTaken
+	}
+
+	hash, err := util.HashPassword(input.Password)
+	if err != nil {
+		return nil, errors.New("failed to hash password")
+	}
+
+	role := input.Role
+	if hotel != nil {
+		role = domain.RoleCustomer
+	}
+
+	now := time.Now()
+	user := &domain.User{
+		ID:              uuid.New(),
+		Name:            input.Name,
+		Email:           email,
+		Password:        hash,
+		Role:            role,
+		EmailVerifiedAt: &now,
+	}
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		audit := auditEntry(actor, domain.AuditUserCreate, domain.AuditEntityUser, user.ID.String(), nil, userState(user))
+		if err := s.userRepo.WithTx(tx).Create(user, audit); err != nil {
+			return err
+		}
+
+		if hotel == nil {
+			return nil
+		}
+
+		staff := &domain.HotelStaff{HotelID: hotel.ID, UserID: user.ID, Role: input.Role}
+		audit = auditEntry(actor, domain.AuditStaffAssign, domain.AuditEntityUser, user.ID.String(),
+			nil, staffState(hotel.ID.String(), input.Role))
+		if err := s.staffRepo.WithTx(tx).Assign(staff, audit); err != nil {
+			return errors.New("failed to assign staff")
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if hotel != nil {
+		user.Role = input.Role
+	}
+
+	return user, nil
+}
+
+// ChangeRole makes the user an admin or takes admin rights away. A demoted
+// user keeps any hotel role their assignments give them. The user's sessions
+// are revoked so the new role applies straight away.
+func (s *userService) ChangeRole(actor Actor, id, role string) (*domain.User, error) {
+	if role != domain.RoleAdmin && role != domain.RoleCustomer {
+		return nil, errors.New("role must be ADMIN or CUSTOMER")
+	}
+
+	user, err := s.modifiableUser(actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if role == domain.RoleAdmin && !actor.IsAdmin() {
+		return nil, ErrPermissionDenied
+	}
+	if user.Role == domain.RoleAdmin && role != domain.RoleAdmin {
+		if err := s.ensureOtherAdmin(user); err != nil {
+			return nil, err
+		}
+	}
+
+	audit := auditEntry(actor, domain.AuditUserRoleChange, domain.AuditEntityUser, id,
+		map[string]any{"role": user.Role}, map[string]any{"role": role})
+	if err := s.userRepo.UpdateRole(id, role, audit); err != nil {
+		return nil, err
+	}
+	if err := s.sessionRepo.RevokeUser(id); err != nil {
+		return nil, err
+	}
+
+	return s.userRepo.FindByID(id)
+}
+
+// DeactivateUser blocks the user from signing in and revokes their sessions.
+func (s *userService) DeactivateUser(actor Actor, id string) (*domain.User, error) {
+	user, err := s.modifiableUser(actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.DeactivatedAt != nil {
+		return user, nil
+	}
+	if err := s.ensureOtherAdmin(user); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	audit := auditEntry(actor, domain.AuditUserDeactivate, domain.AuditEntityUser, id,
+		nil, map[string]any{"deactivated_at": now})
+	if err := s.userRepo.SetDeactivated(id, &now, audit); err != nil {
+		return nil, err
+	}
+	if err := s.sessionRepo.RevokeUser(id); err != nil {
+		return nil, err
+	}
+
+	user.DeactivatedAt = &now
+	return user, nil
+}
+
+func (s *userService) ReactivateUser(actor Actor, id string) (*domain.User, error) {
+	user, err := s.userRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	if user.AnonymizedAt != nil {
+		return nil, errors.New("deleted accounts cannot be reactivated")
+	}
+
+	if user.DeactivatedAt == nil {
+		return user, nil
+	}
+
+	audit := auditEntry(actor, domain.AuditUserReactivate, domain.AuditEntityUser, id,
+		map[string]any{"deactivated_at": user.DeactivatedAt}, nil)
+	if err := s.userRepo.SetDeactivated(id, nil, audit); err != nil {
+		return nil, err
+	}
+
+	user.DeactivatedAt = nil
+	return user, nil
+}
+
+// modifiableUser loads a user whose role or status the actor may change.
+// Nobody may change their own, and only admins may change an admin's.
+func (s *userService) modifiableUser(actor Actor, id string) (*domain.User, error) {
+	user, err := s.userRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	if user.ID.String() == actor.UserID {
+		return nil, ErrCannotModifySelf
+	}
+	if user.Role == domain.RoleAdmin && !actor.IsAdmin() {
+		return nil, ErrPermissionDenied
+	}
+
+	return user, nil
+}
+
+// ensureOtherAdmin refuses to take away the last active admin account.
+func (s *userService) ensureOtherAdmin(user *domain.User) error {
+	if user.Role != domain.RoleAdmin || user.DeactivatedAt != nil {
+		return nil
+	}
+
+	count, err := s.userRepo.CountActiveAdmins()
+	if err != nil {
+		return err
+	}
+	if count <= 1 {
+		return ErrLastAdmin
+	}
+
+	return nil
+}
+
+// userPersonalFields are the user fields the audit log records without their
+// values: audit entries are never erased, so they must not outlive an
+// account's anonymisation.
+var userPersonalFields = []string{"name", "email", "phone"}
+
+// userState is the audited view of an account, with personal fields
+// redacted so the entry still shows they were set.
+func userState(user *domain.User) map[string]any {
+	state := auditFields(user)
+	for _, name := range userPersonalFields {
+		if _, ok := state[name]; ok {
+			state[name] = "[redacted]"
+		}
+	}
+
+	return state
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"errors"
+	"hotel-booking-api/internal/domain"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUserService_CreateUser_AdminRequiresAdmin(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(nil, mockRepo, nil, nil, new(MockSessionRepository))
+
+	actor := Actor{UserID: uuid.NewString(), Role: domain.RoleCustomer, Permissions: []string{domain.PermUserManage}}
+	user, err := service.CreateUser(actor, NewUserInput{
+		Name:     "New Admin",
+		Email:    "admin@example.com",
+		Password: "password123",
+		Role:     domain.RoleAdmin,
+	})
+
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+	assert.Nil(t, user)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestUserService_CreateUser_AuditRedactsPersonalFields(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockRepo.On("FindByEmail", "jane@example.com").Return(nil, errors.New("not found"))
+	mockRepo.On("Create", mock.AnythingOfType("*domain.User"), mock.AnythingOfType("*domain.AuditLog")).Return(nil)
+	service := NewUserService(newTxDB(t), mockRepo, nil, nil, new(MockSessionRepository))
+
+	user, err := service.CreateUser(Actor{UserID: uuid.NewString(), Role: domain.RoleAdmin}, NewUserInput{
+		Name:     "Jane Doe",
+		Email:    " jane@example.com ",
+		Password: "password123",
+		Role:     domain.RoleCustomer,
+	})
+
+	assert.NoError(t, err)
+	audit := mockRepo.Calls[1].Arguments.Get(1).(*domain.AuditLog)
+	assert.Equal(t, domain.AuditUserCreate, audit.Action)
+	assert.Equal(t, user.ID.String(), audit.EntityID)
+	assert.Equal(t, domain.RoleCustomer, audit.Changes["role"].To)
+	assert.Equal(t, "[redacted]", audit.Changes["name"].To)
+	assert.Equal(t, "[redacted]", audit.Changes["email"].To)
+	assert.NotContains(t, audit.Changes, "phone")
+}
+
+func TestUserService_ChangeRole_Self(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(nil, mockRepo, nil, nil, new(MockSessionRepository))
+
+	admin := &domain.User{ID: uuid.New(), Role: domain.RoleAdmin}
+	mockRepo.On("FindByID", admin.ID.String()).Return(admin, nil)
+
+	_, err := service.ChangeRole(Actor{UserID: admin.ID.String(), Role: domain.RoleAdmin}, admin.ID.String(), domain.RoleCustomer)
+
+	assert.ErrorIs(t, err, ErrCannotModifySelf)
+	mockRepo.AssertNotCalled(t, "UpdateRole", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUserService_DeactivateUser_LastAdmin(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(nil, mockRepo, nil, nil, new(MockSessionRepository))
+
+	admin := &domain.User{ID: uuid.New(), Role: domain.RoleAdmin}
+	mockRepo.On("FindByID", admin.ID.String()).Return(admin, nil)
+	mockRepo.On("CountActiveAdmins").Return(int64(1), nil)
+
+	// A custom role with user:manage cannot act on admins at all, so only
+	// another admin gets as far as the last-admin check.
+	_, err := service.DeactivateUser(Actor{UserID: uuid.NewString(), Role: domain.RoleAdmin}, admin.ID.String())
+
+	assert.ErrorIs(t, err, ErrLastAdmin)
+	mockRepo.AssertNotCalled(t, "SetDeactivated", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUserService_DeactivateUser_RevokesSessions(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockSessions := new(MockSessionRepository)
+	service := NewUserService(nil, mockRepo, nil, nil, mockSessions)
+
+	admin := Actor{UserID: uuid.NewString(), Role: domain.RoleAdmin, RequestID: "req-1"}
+	customer := &domain.User{ID: uuid.New(), Role: domain.RoleCustomer}
+	mockRepo.On("FindByID", customer.ID.String()).Return(customer, nil)
+	mockRepo.On("SetDeactivated", customer.ID.String(), mock.AnythingOfType("*time.Time"), mock.MatchedBy(func(audit *domain.AuditLog) bool {
+		return audit.Action == domain.AuditUserDeactivate && audit.EntityID == customer.ID.String() &&
+			audit.ActorID.String() == admin.UserID && audit.RequestID == "req-1"
+	})).Return(nil)
+	mockSessions.On("RevokeUser", customer.ID.String()).Return(nil)
+
+	user, err := service.DeactivateUser(admin, customer.ID.String())
+
+	assert.NoError(t, err)
+	assert.NotNil(t, user.DeactivatedAt)
+	mockRepo.AssertExpectations(t)
+	mockSessions.AssertExpectations(t)
+}
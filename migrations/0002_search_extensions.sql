@@ -0,0 +1,5 @@
+-- Trigram matching for typo-tolerant hotel search and autocomplete. Optional:
+-- with SEARCH_TRIGRAM=false the API neither needs nor creates the extension.
+-- The search indexes themselves are created by repository.MigrateSearchIndexes
+-- once the hotels table exists.
+CREATE EXTENSION IF NOT EXISTS "pg_trgm";
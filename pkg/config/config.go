@@ -2,26 +2,40 @@ package config
 
 import (
 	"errors"
+	"fmt"
+	"hotel-booking-api/pkg/ratelimit"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	App      AppConfig
-	Server   ServerConfig
-	Database DatabaseConfig
-	JWT      JWTConfig
+	App       AppConfig
+	Server    ServerConfig
+	Database  DatabaseConfig
+	JWT       JWTConfig
+	Storage   StorageConfig
+	Mail      MailConfig
+	Auth      AuthConfig
+	RateLimit RateLimitConfig
+	Search    SearchConfig
 }
 
 type AppConfig struct {
 	Name        string
 	Version     string
 	Environment string
+	PublicURL   string
 }
 
 type ServerConfig struct {
 	Port string
+	// TrustedProxies are CIDRs, besides private and loopback addresses, whose
+	// X-Forwarded-For header is believed when working out the client address.
+	TrustedProxies []string
 }
 
 type DatabaseConfig struct {
@@ -33,8 +47,71 @@ type DatabaseConfig struct {
 	SSLMode  string
 }
 
+// JWTConfig selects how tokens are signed. With PrivateKeyFile set, tokens
+// are signed with that RSA or Ed25519 key and SecretKey is ignored, unless
+// LegacySecretUntil is set: then it verifies tokens issued before the switch
+// until that time. PublicKeyFiles are retired keys still accepted for
+// verification.
 type JWTConfig struct {
-	SecretKey string
+	SecretKey         string
+	LegacySecretUntil time.Time
+	PrivateKeyFile    string
+	PublicKeyFiles    []string
+	Issuer            string
+	Audience          string
+	AccessTTL         time.Duration
+	RefreshTTL        time.Duration
+}
+
+// MailConfig selects how transactional email is delivered: "smtp", "file"
+// (written to Dir) or "log".
+type MailConfig struct {
+	Driver       string
+	From         string
+	Dir          string
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+}
+
+type AuthConfig struct {
+	RequireVerifiedEmailForBooking bool
+	RequireAdminTwoFactor          bool
+	// AuditHashKey keys the hash that stands in for unknown login emails in
+	// the audit log. Without it a random key is used, so entries for the
+	// same email cannot be linked across restarts.
+	AuditHashKey string
+}
+
+// RateLimitConfig sets per-client request limits. Store is "memory" or
+// "redis"; Redis shares the limits between instances.
+type RateLimitConfig struct {
+	Store    string
+	RedisURL string
+	// Default applies to every request, per client address.
+	Default ratelimit.Limit
+	// Auth applies to sign-in, registration and password reset.
+	Auth    ratelimit.Limit
+	Booking ratelimit.Limit
+	Webhook ratelimit.Limit
+}
+
+// SearchConfig tunes hotel search. Trigram turns on typo-tolerant matching
+// with the pg_trgm extension, which migrations then create; that needs a
+// database role allowed to create extensions. Without it search still ranks
+// full-text matches and matches partial words as substrings.
+type SearchConfig struct {
+	Trigram bool
+}
+
+type StorageConfig struct {
+	LocalDir    string
+	BaseURL     string
+	MaxUploadMB int64
+	// ExportDir holds personal data export archives. Unlike LocalDir it must
+	// not be served publicly.
+	ExportDir string
 }
 
 func Load() (*Config, error) {
@@ -47,9 +124,11 @@ func Load() (*Config, error) {
 			Name:        getEnv("APP_NAME", "Hotel Booking API"),
 			Version:     getEnv("APP_VERSION", "1.0.0"),
 			Environment: getEnv("APP_ENV", "development"),
+			PublicURL:   getEnv("APP_PUBLIC_URL", "http://localhost:3000"),
 		},
 		Server: ServerConfig{
-			Port: getEnv("SERVER_PORT", "8080"),
+			Port:           getEnv("SERVER_PORT", "8080"),
+			TrustedProxies: getEnvList("TRUSTED_PROXIES"),
 		},
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -60,12 +139,114 @@ func Load() (*Config, error) {
 			SSLMode:  getEnv("DB_SSL_MODE", "disable"),
 		},
 		JWT: JWTConfig{
-			SecretKey: os.Getenv("JWT_SECRET"),
+			SecretKey:      os.Getenv("JWT_SECRET"),
+			PrivateKeyFile: os.Getenv("JWT_PRIVATE_KEY_FILE"),
+			PublicKeyFiles: getEnvList("JWT_PUBLIC_KEY_FILES"),
+			Issuer:         getEnv("JWT_ISSUER", "hotel-booking-api"),
+			Audience:       getEnv("JWT_AUDIENCE", "hotel-booking-api"),
+			AccessTTL:      15 * time.Minute,
+			RefreshTTL:     30 * 24 * time.Hour,
+		},
+		Storage: StorageConfig{
+			LocalDir:    getEnv("MEDIA_DIR", "./uploads"),
+			BaseURL:     getEnv("MEDIA_BASE_URL", "http://localhost:8080/media"),
+			MaxUploadMB: 5,
+			ExportDir:   getEnv("EXPORT_DIR", "./exports"),
+		},
+		Mail: MailConfig{
+			Driver:       getEnv("MAIL_DRIVER", "log"),
+			From:         getEnv("MAIL_FROM", "Hotel Booking <no-reply@localhost>"),
+			Dir:          getEnv("MAIL_DIR", "./mail"),
+			SMTPHost:     os.Getenv("SMTP_HOST"),
+			SMTPPort:     getEnv("SMTP_PORT", "587"),
+			SMTPUsername: os.Getenv("SMTP_USERNAME"),
+			SMTPPassword: os.Getenv("SMTP_PASSWORD"),
+		},
+		Auth: AuthConfig{
+			RequireVerifiedEmailForBooking: os.Getenv("REQUIRE_VERIFIED_EMAIL_FOR_BOOKING") == "true",
+			RequireAdminTwoFactor:          os.Getenv("REQUIRE_ADMIN_2FA") == "true",
+			AuditHashKey:                   os.Getenv("AUDIT_HASH_KEY"),
 		},
+		RateLimit: RateLimitConfig{
+			Store:    getEnv("RATE_LIMIT_STORE", "memory"),
+			RedisURL: os.Getenv("RATE_LIMIT_REDIS_URL"),
+		},
+		Search: SearchConfig{
+			Trigram: getEnv("SEARCH_TRIGRAM", "true") == "true",
+		},
+	}
+
+	limits := []struct {
+		env, defaultVal string
+		limit           *ratelimit.Limit
+	}{
+		{"RATE_LIMIT_DEFAULT", "300/1m", &cfg.RateLimit.Default},
+		{"RATE_LIMIT_AUTH", "10/1m", &cfg.RateLimit.Auth},
+		{"RATE_LIMIT_BOOKING", "30/1m:10", &cfg.RateLimit.Booking},
+		{"RATE_LIMIT_WEBHOOK", "600/1m", &cfg.RateLimit.Webhook},
+	}
+	for _, l := range limits {
+		limit, err := ratelimit.ParseLimit(getEnv(l.env, l.defaultVal))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", l.env, err)
+		}
+		*l.limit = limit
+	}
+
+	switch cfg.RateLimit.Store {
+	case "memory":
+	case "redis":
+		if cfg.RateLimit.RedisURL == "" {
+			return nil, errors.New("missing RATE_LIMIT_REDIS_URL")
+		}
+	default:
+		return nil, errors.New("invalid RATE_LIMIT_STORE")
+	}
+
+	if raw := os.Getenv("MEDIA_MAX_UPLOAD_MB"); raw != "" {
+		maxUploadMB, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || maxUploadMB <= 0 {
+			return nil, errors.New("invalid MEDIA_MAX_UPLOAD_MB")
+		}
+		cfg.Storage.MaxUploadMB = maxUploadMB
+	}
+
+	if raw := os.Getenv("JWT_ACCESS_TTL"); raw != "" {
+		ttl, err := time.ParseDuration(raw)
+		if err != nil || ttl <= 0 {
+			return nil, errors.New("invalid JWT_ACCESS_TTL")
+		}
+		cfg.JWT.AccessTTL = ttl
+	}
+
+	if raw := os.Getenv("JWT_LEGACY_SECRET_UNTIL"); raw != "" {
+		until, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return nil, errors.New("invalid JWT_LEGACY_SECRET_UNTIL")
+		}
+		cfg.JWT.LegacySecretUntil = until
 	}
 
-	if cfg.JWT.SecretKey == "" {
-		return nil, errors.New("missing jwt secret")
+	if raw := os.Getenv("JWT_REFRESH_TTL"); raw != "" {
+		ttl, err := time.ParseDuration(raw)
+		if err != nil || ttl <= 0 {
+			return nil, errors.New("invalid JWT_REFRESH_TTL")
+		}
+		cfg.JWT.RefreshTTL = ttl
+	}
+
+	switch cfg.Mail.Driver {
+	case "log", "file":
+	case "smtp":
+		if cfg.Mail.SMTPHost == "" {
+			return nil, errors.New("missing SMTP_HOST")
+		}
+	default:
+		return nil, errors.New("invalid MAIL_DRIVER")
+	}
+
+	if cfg.JWT.SecretKey == "" && cfg.JWT.PrivateKeyFile == "" {
+		return nil, errors.New("missing jwt secret or private key")
 	}
 
 	if cfg.Database.Password == "" {
@@ -82,3 +263,15 @@ func getEnv(key, defaultVal string) string {
 
 	return defaultVal
 }
+
+// getEnvList splits a comma-separated variable, dropping empty entries.
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
@@ -4,10 +4,12 @@ import (
 	"fmt"
 	"hotel-booking-api/internal/domain"
 	"hotel-booking-api/pkg/config"
+	"hotel-booking-api/pkg/util"
 	"time"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"gorm.io/gorm/logger"
 )
 
@@ -45,11 +47,171 @@ func InitPostgres(cfg *config.Config) (*gorm.DB, error) {
 }
 
 func AutoMigrate(db *gorm.DB) error {
-	return db.AutoMigrate(
+	if err := keepBookingHistory(db); err != nil {
+		return err
+	}
+
+	err := db.AutoMigrate(
 		&domain.User{},
+		&domain.Guest{},
+		&domain.Amenity{},
 		&domain.Hotel{},
 		&domain.Room{},
 		&domain.Booking{},
 		&domain.Payment{},
+		&domain.Photo{},
+		&domain.Review{},
+		&domain.HotelStaff{},
+		&domain.Permission{},
+		&domain.Role{},
+		&domain.RefreshToken{},
+		&domain.RevokedToken{},
+		&domain.UserToken{},
+		&domain.LoginAttempt{},
+		&domain.RecoveryCode{},
+		&domain.APIKey{},
+		&domain.DataExport{},
+		&domain.AuditLog{},
 	)
+	if err != nil {
+		return err
+	}
+
+	if err := protectAuditLog(db); err != nil {
+		return err
+	}
+
+	if err := backfillBookingReferences(db); err != nil {
+		return err
+	}
+
+	return seedAccessControl(db)
+}
+
+// seedAccessControl syncs the permission catalogue and the built-in system
+// roles. Custom roles are left untouched.
+func seedAccessControl(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		for code, description := range domain.PermissionCatalogue {
+			permission := domain.Permission{Code: code, Description: description}
+			err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "code"}},
+				DoUpdates: clause.AssignmentColumns([]string{"description"}),
+			}).Create(&permission).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		var permissions []domain.Permission
+		if err := tx.Find(&permissions).Error; err != nil {
+			return err
+		}
+		byCode := make(map[string]domain.Permission, len(permissions))
+		for _, permission := range permissions {
+			byCode[permission.Code] = permission
+		}
+
+		systemRoles := map[string][]domain.Permission{domain.RoleAdmin: permissions}
+		for name, codes := range domain.SystemRolePermissions {
+			for _, code := range codes {
+				systemRoles[name] = append(systemRoles[name], byCode[code])
+			}
+		}
+
+		for name, granted := range systemRoles {
+			role := domain.Role{Name: name}
+			if err := tx.Where(domain.Role{Name: name}).
+				Attrs(domain.Role{Description: "Built-in " + name + " role"}).
+				FirstOrCreate(&role).Error; err != nil {
+				return err
+			}
+
+			if !role.IsSystem {
+				if err := tx.Model(&role).Update("is_system", true).Error; err != nil {
+					return err
+				}
+			}
+
+			if err := tx.Model(&role).Association("Permissions").Replace(granted); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// keepBookingHistory drops the foreign key that used to delete a room's
+// bookings along with the room. AutoMigrate then recreates it with the
+// RESTRICT rule from the domain tags, so booking and payment history can no
+// longer be wiped out by deleting a hotel or room.
+func keepBookingHistory(db *gorm.DB) error {
+	var cascades int64
+	err := db.Raw(`SELECT COUNT(*) FROM information_schema.referential_constraints
+		WHERE constraint_name = 'fk_rooms_bookings' AND delete_rule = 'CASCADE'`).Scan(&cascades).Error
+	if err != nil || cascades == 0 {
+		return err
+	}
+
+	if err := db.Exec("ALTER TABLE bookings DROP CONSTRAINT fk_rooms_bookings").Error; err != nil {
+		return fmt.Errorf("failed to drop fk_rooms_bookings: %w", err)
+	}
+
+	return nil
+}
+
+// backfillBookingReferences gives bookings made before references existed one
+// of their own, so every booking can be looked up by reference.
+func backfillBookingReferences(db *gorm.DB) error {
+	var ids []string
+	err := db.Model(&domain.Booking{}).
+		Where("reference IS NULL OR reference = ''").
+		Pluck("id", &ids).Error
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		for attempt := 1; ; attempt++ {
+			reference, err := util.GenerateReference()
+			if err != nil {
+				return err
+			}
+
+			err = db.Model(&domain.Booking{}).Where("id = ?", id).
+				UpdateColumn("reference", reference).Error
+			if err == nil {
+				break
+			}
+			if !IsUniqueViolation(err, "idx_bookings_reference") || attempt == 3 {
+				return fmt.Errorf("failed to backfill booking reference: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// protectAuditLog makes the audit log append-only: updating or deleting an
+// entry fails, whoever tries.
+func protectAuditLog(db *gorm.DB) error {
+	statements := []string{
+		`CREATE OR REPLACE FUNCTION audit_logs_append_only() RETURNS trigger AS $$
+		BEGIN
+			RAISE EXCEPTION 'audit_logs is append-only';
+		END
+		$$ LANGUAGE plpgsql`,
+		"DROP TRIGGER IF EXISTS audit_logs_append_only ON audit_logs",
+		"CREATE TRIGGER audit_logs_append_only BEFORE UPDATE OR DELETE ON audit_logs " +
+			"FOR EACH ROW EXECUTE FUNCTION audit_logs_append_only()",
+	}
+
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			return fmt.Errorf("failed to protect audit log: %w", err)
+		}
+	}
+
+	return nil
 }
@@ -0,0 +1,66 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+
+	// Register decoders for the formats we accept.
+	_ "image/png"
+)
+
+// Thumbnail scales img down so it fits within maxWidth x maxHeight while
+// keeping its aspect ratio. Images that already fit are returned unchanged.
+// Each output pixel averages the source pixels it covers (a box filter),
+// which is good enough for photo previews without an extra dependency.
+func Thumbnail(img image.Image, maxWidth, maxHeight int) image.Image {
+	bounds := img.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	if srcW <= maxWidth && srcH <= maxHeight {
+		return img
+	}
+
+	scale := min(float64(maxWidth)/float64(srcW), float64(maxHeight)/float64(srcH))
+	dstW := max(1, int(float64(srcW)*scale))
+	dstH := max(1, int(float64(srcH)*scale))
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+	for y := 0; y < dstH; y++ {
+		y0 := bounds.Min.Y + y*srcH/dstH
+		y1 := max(y0+1, bounds.Min.Y+(y+1)*srcH/dstH)
+
+		for x := 0; x < dstW; x++ {
+			x0 := bounds.Min.X + x*srcW/dstW
+			x1 := max(x0+1, bounds.Min.X+(x+1)*srcW/dstW)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := img.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa)
+					n++
+				}
+			}
+
+			dst.SetRGBA(x, y, color.RGBA{
+				R: uint8(r / n >> 8),
+				G: uint8(g / n >> 8),
+				B: uint8(b / n >> 8),
+				A: uint8(a / n >> 8),
+			})
+		}
+	}
+
+	return dst
+}
+
+// EncodeJPEG encodes img as a JPEG with the given quality (1-100).
+func EncodeJPEG(img image.Image, quality int) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,30 @@
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThumbnail_KeepsAspectRatio(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 1200, 600))
+	for y := 0; y < 600; y++ {
+		for x := 0; x < 1200; x++ {
+			src.Set(x, y, color.RGBA{R: 200, G: 100, B: 50, A: 255})
+		}
+	}
+
+	thumb := Thumbnail(src, 400, 400)
+
+	assert.Equal(t, 400, thumb.Bounds().Dx())
+	assert.Equal(t, 200, thumb.Bounds().Dy())
+	assert.Equal(t, color.RGBA{R: 200, G: 100, B: 50, A: 255}, thumb.At(10, 10))
+}
+
+func TestThumbnail_SmallImageUnchanged(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 100, 80))
+
+	assert.Same(t, src, Thumbnail(src, 400, 400))
+}
@@ -4,6 +4,7 @@ type SuccessResponse struct {
 	Success bool   `json:"success"`
 	Message string `json:"message"`
 	Data    any    `json:"data,omitempty"`
+	Meta    any    `json:"meta,omitempty"`
 }
 
 type ErrorResponse struct {
@@ -22,6 +23,15 @@ func Success(message string, data any) SuccessResponse {
 	}
 }
 
+func SuccessWithMeta(message string, data any, meta any) SuccessResponse {
+	return SuccessResponse{
+		Success: true,
+		Message: message,
+		Data:    data,
+		Meta:    meta,
+	}
+}
+
 func Error(err string, message string, details any) ErrorResponse {
 	return ErrorResponse{
 		Success: false,
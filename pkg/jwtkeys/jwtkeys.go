@@ -0,0 +1,325 @@
+// Package jwtkeys holds the keys tokens are signed and verified with.
+//
+// One key signs new tokens; older keys can stay configured for verification
+// only, so rotating the signing key does not invalidate tokens issued before
+// the rotation. Each asymmetric key is identified by its RFC 7638 thumbprint,
+// sent as the token's kid header, and its public half is published as a JWKS
+// so other services can verify tokens without holding a secret.
+//
+// To rotate, first add the new key to PublicKeyFiles so it is published, then
+// make it the PrivateKeyFile and keep the old key in PublicKeyFiles until
+// every token it signed has expired.
+//
+// A shared HS256 secret is still supported for development. Once a private
+// key is configured the secret is ignored, unless the operator opts in to
+// accepting tokens it signed before the switch until a set time. HS256 tokens
+// carry no kid and the secret is never published.
+package jwtkeys
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// minRSABits is the smallest RSA modulus accepted for signing or verifying.
+const minRSABits = 2048
+
+var ErrUnknownKey = errors.New("token signed with an unknown key")
+
+// Config says where to find the keys. PrivateKeyFile signs new tokens;
+// PublicKeyFiles are accepted for verification only and may hold either
+// public or private keys in PEM form. Secret enables HS256 when no private
+// key is configured. With a private key, the secret is only used to verify
+// tokens, and only until LegacySecretUntil; left zero, it is ignored.
+type Config struct {
+	PrivateKeyFile    string
+	PublicKeyFiles    []string
+	Secret            string
+	LegacySecretUntil time.Time
+	Issuer            string
+	Audience          string
+}
+
+// Key is a single signing or verification key. A key with until set is no
+// longer accepted after that time.
+type Key struct {
+	ID     string
+	Method jwt.SigningMethod
+	sign   any
+	verify any
+	until  time.Time
+}
+
+// KeySet is the signing key plus every key accepted for verification, and
+// the issuer and audience of the tokens they sign.
+type KeySet struct {
+	Issuer   string
+	Audience string
+	signing  *Key
+	keys     map[string]*Key
+	order    []*Key
+}
+
+// JWK is a public key in JSON Web Key form.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKS is a JSON Web Key Set.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// Load reads the configured key files.
+func Load(cfg Config) (*KeySet, error) {
+	set := &KeySet{
+		Issuer:   cfg.Issuer,
+		Audience: cfg.Audience,
+		keys:     make(map[string]*Key),
+	}
+
+	if cfg.PrivateKeyFile != "" {
+		key, err := loadKey(cfg.PrivateKeyFile, true)
+		if err != nil {
+			return nil, err
+		}
+		set.signing = key
+		set.add(key)
+	}
+
+	for _, path := range cfg.PublicKeyFiles {
+		key, err := loadKey(path, false)
+		if err != nil {
+			return nil, err
+		}
+		set.add(key)
+	}
+
+	if cfg.Secret != "" {
+		key := hmacKey(cfg.Secret)
+		switch {
+		case set.signing == nil:
+			set.signing = key
+			set.add(key)
+		case time.Now().Before(cfg.LegacySecretUntil):
+			key.until = cfg.LegacySecretUntil
+			set.add(key)
+		}
+	}
+
+	if set.signing == nil {
+		return nil, errors.New("no JWT signing key configured")
+	}
+
+	return set, nil
+}
+
+// NewHMAC returns a key set that signs and verifies with an HS256 secret.
+func NewHMAC(secret, issuer, audience string) *KeySet {
+	key := hmacKey(secret)
+	set := &KeySet{
+		Issuer:   issuer,
+		Audience: audience,
+		signing:  key,
+		keys:     make(map[string]*Key),
+	}
+	set.add(key)
+
+	return set
+}
+
+// SigningKey returns the key new tokens are signed with.
+func (s *KeySet) SigningKey() *Key {
+	return s.signing
+}
+
+// Sign signs claims with the signing key, naming it in the kid header.
+func (s *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(s.signing.Method, claims)
+	if s.signing.ID != "" {
+		token.Header["kid"] = s.signing.ID
+	}
+
+	return token.SignedString(s.signing.sign)
+}
+
+// Parse verifies tokenStr with the key named by its kid header and decodes
+// it into claims. The token's algorithm must be the one of that key, so a
+// public key can never be used as an HMAC secret.
+func (s *KeySet) Parse(tokenStr string, claims jwt.Claims, opts ...jwt.ParserOption) (*jwt.Token, error) {
+	methods := make([]string, 0, len(s.order))
+	for _, key := range s.order {
+		methods = append(methods, key.Method.Alg())
+	}
+	opts = append(opts, jwt.WithValidMethods(methods))
+
+	return jwt.ParseWithClaims(tokenStr, claims, s.keyFunc, opts...)
+}
+
+// JWKS returns the public keys accepted for verification, signing key first.
+// HS256 secrets are left out.
+func (s *KeySet) JWKS() JWKS {
+	jwks := JWKS{Keys: []JWK{}}
+	for _, key := range s.order {
+		if jwk, ok := toJWK(key); ok {
+			jwks.Keys = append(jwks.Keys, jwk)
+		}
+	}
+
+	return jwks
+}
+
+func (s *KeySet) keyFunc(t *jwt.Token) (any, error) {
+	kid, _ := t.Header["kid"].(string)
+
+	key, ok := s.keys[kid]
+	if !ok || t.Method.Alg() != key.Method.Alg() {
+		return nil, ErrUnknownKey
+	}
+	if !key.until.IsZero() && time.Now().After(key.until) {
+		return nil, ErrUnknownKey
+	}
+
+	return key.verify, nil
+}
+
+func (s *KeySet) add(key *Key) {
+	if _, exists := s.keys[key.ID]; exists {
+		return
+	}
+
+	s.keys[key.ID] = key
+	s.order = append(s.order, key)
+}
+
+func hmacKey(secret string) *Key {
+	return &Key{
+		Method: jwt.SigningMethodHS256,
+		sign:   []byte(secret),
+		verify: []byte(secret),
+	}
+}
+
+// loadKey reads an RSA or Ed25519 key from a PEM file. With signing, the
+// file must hold a private key.
+func loadKey(path string, signing bool) (*Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read JWT key: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("JWT key %s: no PEM data", path)
+	}
+
+	parsed, err := parsePEMBlock(block)
+	if err != nil {
+		return nil, fmt.Errorf("JWT key %s: %w", path, err)
+	}
+
+	key, err := newKey(parsed)
+	if err != nil {
+		return nil, fmt.Errorf("JWT key %s: %w", path, err)
+	}
+
+	if signing && key.sign == nil {
+		return nil, fmt.Errorf("JWT key %s: a private key is required for signing", path)
+	}
+
+	return key, nil
+}
+
+func parsePEMBlock(block *pem.Block) (any, error) {
+	switch block.Type {
+	case "PRIVATE KEY":
+		return x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+}
+
+// newKey wraps a parsed RSA or Ed25519 key, naming it by its thumbprint.
+func newKey(parsed any) (*Key, error) {
+	key := &Key{}
+
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.Method, key.sign, key.verify = jwt.SigningMethodRS256, k, &k.PublicKey
+	case *rsa.PublicKey:
+		key.Method, key.verify = jwt.SigningMethodRS256, k
+	case ed25519.PrivateKey:
+		key.Method, key.sign, key.verify = jwt.SigningMethodEdDSA, k, k.Public()
+	case ed25519.PublicKey:
+		key.Method, key.verify = jwt.SigningMethodEdDSA, k
+	default:
+		return nil, errors.New("only RSA and Ed25519 keys are supported")
+	}
+
+	if pub, ok := key.verify.(*rsa.PublicKey); ok && pub.N.BitLen() < minRSABits {
+		return nil, fmt.Errorf("RSA keys must be at least %d bits", minRSABits)
+	}
+
+	key.ID = Thumbprint(key.verify)
+	return key, nil
+}
+
+// Thumbprint returns the RFC 7638 SHA-256 thumbprint of an RSA or Ed25519
+// public key, base64url encoded, or "" for any other key.
+func Thumbprint(pub any) string {
+	var canonical string
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		canonical = fmt.Sprintf(`{"e":"%s","kty":"RSA","n":"%s"}`, encodeInt(big.NewInt(int64(k.E))), encodeInt(k.N))
+	case ed25519.PublicKey:
+		canonical = fmt.Sprintf(`{"crv":"Ed25519","kty":"OKP","x":"%s"}`, base64.RawURLEncoding.EncodeToString(k))
+	default:
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(canonical))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func toJWK(key *Key) (JWK, bool) {
+	jwk := JWK{Kid: key.ID, Use: "sig", Alg: key.Method.Alg()}
+
+	switch k := key.verify.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty, jwk.N, jwk.E = "RSA", encodeInt(k.N), encodeInt(big.NewInt(int64(k.E)))
+	case ed25519.PublicKey:
+		jwk.Kty, jwk.Crv, jwk.X = "OKP", "Ed25519", base64.RawURLEncoding.EncodeToString(k)
+	default:
+		return JWK{}, false
+	}
+
+	return jwk, true
+}
+
+func encodeInt(n *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(n.Bytes())
+}
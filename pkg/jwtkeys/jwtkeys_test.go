@@ -0,0 +1,192 @@
+package jwtkeys
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeKey(t *testing.T, blockType string, der []byte) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "key.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	assert.NoError(t, os.WriteFile(path, data, 0o600))
+
+	return path
+}
+
+func writeEd25519Key(t *testing.T) (string, string) {
+	t.Helper()
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	privDER, err := x509.MarshalPKCS8PrivateKey(priv)
+	assert.NoError(t, err)
+	pubDER, err := x509.MarshalPKIXPublicKey(pub)
+	assert.NoError(t, err)
+
+	return writeKey(t, "PRIVATE KEY", privDER), writeKey(t, "PUBLIC KEY", pubDER)
+}
+
+func testClaims() jwt.RegisteredClaims {
+	return jwt.RegisteredClaims{
+		Subject:   "user-1",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+	}
+}
+
+func TestThumbprint_RFC7638Example(t *testing.T) {
+	n, err := base64.RawURLEncoding.DecodeString("0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw")
+	assert.NoError(t, err)
+
+	pub := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: 65537}
+
+	assert.Equal(t, "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs", Thumbprint(pub))
+}
+
+func TestKeySet_RotationKeepsOldTokensValid(t *testing.T) {
+	oldPriv, oldPub := writeEd25519Key(t)
+	newPriv, _ := writeEd25519Key(t)
+
+	before, err := Load(Config{PrivateKeyFile: oldPriv})
+	assert.NoError(t, err)
+	token, err := before.Sign(testClaims())
+	assert.NoError(t, err)
+
+	after, err := Load(Config{PrivateKeyFile: newPriv, PublicKeyFiles: []string{oldPub}})
+	assert.NoError(t, err)
+	_, err = after.Parse(token, &jwt.RegisteredClaims{})
+	assert.NoError(t, err)
+
+	retired, err := Load(Config{PrivateKeyFile: newPriv})
+	assert.NoError(t, err)
+	_, err = retired.Parse(token, &jwt.RegisteredClaims{})
+	assert.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestKeySet_RSASignsWithKid(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	keys, err := Load(Config{PrivateKeyFile: writeKey(t, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(priv))})
+	assert.NoError(t, err)
+
+	tokenStr, err := keys.Sign(testClaims())
+	assert.NoError(t, err)
+
+	token, err := keys.Parse(tokenStr, &jwt.RegisteredClaims{})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "RS256", token.Method.Alg())
+		assert.Equal(t, Thumbprint(&priv.PublicKey), token.Header["kid"])
+	}
+}
+
+func TestKeySet_RejectsAlgorithmConfusion(t *testing.T) {
+	priv, _ := writeEd25519Key(t)
+	keys, err := Load(Config{PrivateKeyFile: priv, Secret: "legacy-secret", LegacySecretUntil: time.Now().Add(time.Hour)})
+	assert.NoError(t, err)
+
+	// An HS256 token naming the Ed25519 key must not verify, whatever the
+	// attacker used as the HMAC secret.
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, testClaims())
+	forged.Header["kid"] = keys.SigningKey().ID
+	tokenStr, err := forged.SignedString([]byte("legacy-secret"))
+	assert.NoError(t, err)
+
+	_, err = keys.Parse(tokenStr, &jwt.RegisteredClaims{})
+	assert.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestKeySet_AcceptsLegacyHMACTokensWhenOptedIn(t *testing.T) {
+	legacy := NewHMAC("legacy-secret", "", "")
+	tokenStr, err := legacy.Sign(testClaims())
+	assert.NoError(t, err)
+
+	priv, _ := writeEd25519Key(t)
+	keys, err := Load(Config{PrivateKeyFile: priv, Secret: "legacy-secret", LegacySecretUntil: time.Now().Add(time.Hour)})
+	assert.NoError(t, err)
+
+	_, err = keys.Parse(tokenStr, &jwt.RegisteredClaims{})
+	assert.NoError(t, err)
+	assert.Equal(t, jwt.SigningMethodEdDSA, keys.SigningKey().Method)
+}
+
+func TestKeySet_IgnoresSecretBesidePrivateKey(t *testing.T) {
+	legacy := NewHMAC("legacy-secret", "", "")
+	tokenStr, err := legacy.Sign(testClaims())
+	assert.NoError(t, err)
+
+	priv, _ := writeEd25519Key(t)
+	keys, err := Load(Config{PrivateKeyFile: priv, Secret: "legacy-secret"})
+	assert.NoError(t, err)
+	_, err = keys.Parse(tokenStr, &jwt.RegisteredClaims{})
+	assert.Error(t, err)
+
+	expired, err := Load(Config{PrivateKeyFile: priv, Secret: "legacy-secret", LegacySecretUntil: time.Now().Add(-time.Hour)})
+	assert.NoError(t, err)
+	_, err = expired.Parse(tokenStr, &jwt.RegisteredClaims{})
+	assert.Error(t, err)
+}
+
+func TestKeySet_LegacySecretStopsAtDeadline(t *testing.T) {
+	legacy := NewHMAC("legacy-secret", "", "")
+	tokenStr, err := legacy.Sign(testClaims())
+	assert.NoError(t, err)
+
+	priv, _ := writeEd25519Key(t)
+	keys, err := Load(Config{PrivateKeyFile: priv, Secret: "legacy-secret", LegacySecretUntil: time.Now().Add(time.Hour)})
+	assert.NoError(t, err)
+	for _, key := range keys.keys {
+		if key.Method == jwt.SigningMethodHS256 {
+			key.until = time.Now().Add(-time.Second)
+		}
+	}
+
+	_, err = keys.Parse(tokenStr, &jwt.RegisteredClaims{})
+	assert.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestKeySet_JWKSPublishesOnlyPublicKeys(t *testing.T) {
+	priv, pub := writeEd25519Key(t)
+	keys, err := Load(Config{PrivateKeyFile: priv, PublicKeyFiles: []string{pub}, Secret: "legacy-secret", LegacySecretUntil: time.Now().Add(time.Hour)})
+	assert.NoError(t, err)
+
+	jwks := keys.JWKS()
+
+	if assert.Len(t, jwks.Keys, 1) {
+		assert.Equal(t, "OKP", jwks.Keys[0].Kty)
+		assert.Equal(t, "EdDSA", jwks.Keys[0].Alg)
+		assert.Equal(t, keys.SigningKey().ID, jwks.Keys[0].Kid)
+	}
+}
+
+func TestLoad_RejectsSmallRSAKeys(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 1024)
+	assert.NoError(t, err)
+
+	_, err = Load(Config{PrivateKeyFile: writeKey(t, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(priv))})
+	assert.Error(t, err)
+}
+
+func TestLoad_RequiresSigningKey(t *testing.T) {
+	_, pub := writeEd25519Key(t)
+
+	_, err := Load(Config{PrivateKeyFile: pub})
+	assert.Error(t, err)
+
+	_, err = Load(Config{})
+	assert.Error(t, err)
+}
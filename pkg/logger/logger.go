@@ -5,7 +5,7 @@ import (
 	"os"
 )
 
-var log *slog.Logger
+var log = slog.Default()
 
 func Init(env string) {
 	opts := &slog.HandlerOptions{
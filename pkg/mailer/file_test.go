@@ -0,0 +1,34 @@
+package mailer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileMailer_Send(t *testing.T) {
+	dir := t.TempDir()
+	m, err := NewFileMailer(dir, "noreply@example.com")
+	assert.NoError(t, err)
+
+	err = m.Send(context.Background(), Message{
+		To:      "guest@example.com",
+		Subject: "Verify your email\r\nBcc: attacker@example.com",
+		Body:    "Hello\nWorld",
+	})
+	assert.NoError(t, err)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+
+	content, err := os.ReadFile(files[0])
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "To: guest@example.com\r\n")
+	assert.Contains(t, string(content), "Subject: Verify your emailBcc: attacker@example.com\r\n")
+	assert.True(t, strings.HasSuffix(string(content), "\r\n\r\nHello\r\nWorld"))
+}
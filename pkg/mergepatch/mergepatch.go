@@ -0,0 +1,69 @@
+// Package mergepatch applies JSON Merge Patches as defined by RFC 7396.
+//
+// A merge patch is a JSON document shaped like its target: members of a
+// patch object replace those of the target, null removes them, and nested
+// objects are merged recursively. Anything other than an object replaces the
+// target outright.
+package mergepatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var ErrInvalidPatch = errors.New("invalid merge patch")
+
+// Apply returns target with patch merged into it. Numbers keep their exact
+// text, so large integers survive the round trip.
+func Apply(target, patch []byte) ([]byte, error) {
+	doc, err := decode(target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid merge target: %w", err)
+	}
+
+	changes, err := decode(patch)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+
+	return json.Marshal(merge(doc, changes))
+}
+
+func merge(target, patch any) any {
+	changes, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	doc, ok := target.(map[string]any)
+	if !ok {
+		doc = make(map[string]any, len(changes))
+	}
+
+	for name, value := range changes {
+		if value == nil {
+			delete(doc, name)
+			continue
+		}
+		doc[name] = merge(doc[name], value)
+	}
+
+	return doc
+}
+
+func decode(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var v any
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, errors.New("unexpected data after the JSON document")
+	}
+
+	return v, nil
+}
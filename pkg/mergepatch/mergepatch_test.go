@@ -0,0 +1,52 @@
+package mergepatch
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestApply_RFC7396Examples runs the test cases of RFC 7396 appendix A.
+func TestApply_RFC7396Examples(t *testing.T) {
+	cases := []struct {
+		target, patch, result string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+
+	for _, tc := range cases {
+		result, err := Apply([]byte(tc.target), []byte(tc.patch))
+		assert.NoError(t, err, tc.patch)
+		assert.JSONEq(t, tc.result, string(result), "%s patched with %s", tc.target, tc.patch)
+	}
+}
+
+func TestApply_KeepsNumbersExact(t *testing.T) {
+	result, err := Apply([]byte(`{"id":9007199254740993,"price":10.10}`), []byte(`{"name":"x"}`))
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"id":9007199254740993,"price":10.10,"name":"x"}`, string(result))
+	assert.Contains(t, string(result), "9007199254740993")
+}
+
+func TestApply_InvalidPatch(t *testing.T) {
+	for _, patch := range []string{``, `{"a":`, `{"a":1} {"b":2}`} {
+		_, err := Apply([]byte(`{}`), []byte(patch))
+		assert.True(t, errors.Is(err, ErrInvalidPatch), "patch %q", patch)
+	}
+}
@@ -0,0 +1,221 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// ErrInvalidQuery is wrapped by every error returned from Query.Normalize so
+// handlers can tell client mistakes apart from database failures.
+var ErrInvalidQuery = errors.New("invalid list query")
+
+type Op int
+
+const (
+	OpEqual Op = iota
+	OpILike
+	// OpGte and OpLte compare numbers; Normalize rejects other values.
+	OpGte
+	OpLte
+)
+
+// Filter maps a public query parameter onto a column and comparison.
+type Filter struct {
+	Column string
+	Op     Op
+}
+
+// Options is the allow-list a list endpoint exposes: which fields may be
+// sorted on, which filters exist and what the default ordering is.
+type Options struct {
+	SortFields   map[string]string
+	DefaultSort  string
+	DefaultOrder string
+	Filters      map[string]Filter
+}
+
+// Query carries the page/cursor, sort and filter values a client asked for.
+// Cursors are opaque to clients; they currently encode the offset of the next
+// page so they stay valid regardless of the chosen sort field.
+//
+// A query is normalized once, before it is used: Filter, Paginate and Meta
+// all rely on the defaults and offset Normalize fills in.
+type Query struct {
+	Page    int
+	Limit   int
+	Cursor  string
+	Sort    string
+	Order   string
+	Filters map[string]string
+
+	offset int
+}
+
+type Meta struct {
+	Page       int    `json:"page,omitempty"`
+	Limit      int    `json:"limit"`
+	Total      int64  `json:"total"`
+	TotalPages int    `json:"total_pages"`
+	HasMore    bool   `json:"has_more"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// Normalize applies defaults and limit caps and rejects sort fields, orders,
+// cursors and numeric filter values that are not allowed. Unknown filter keys
+// are dropped.
+func (q *Query) Normalize(opts Options) error {
+	if q.Limit < 1 {
+		q.Limit = DefaultLimit
+	}
+	if q.Limit > MaxLimit {
+		q.Limit = MaxLimit
+	}
+
+	if q.Sort == "" {
+		q.Sort = opts.DefaultSort
+	}
+	if _, ok := opts.SortFields[q.Sort]; !ok {
+		return fmt.Errorf("%w: cannot sort by %q", ErrInvalidQuery, q.Sort)
+	}
+
+	q.Order = strings.ToLower(q.Order)
+	if q.Order == "" {
+		q.Order = opts.DefaultOrder
+	}
+	if q.Order != "asc" && q.Order != "desc" {
+		return fmt.Errorf("%w: order must be asc or desc", ErrInvalidQuery)
+	}
+
+	if q.Cursor != "" {
+		offset, err := decodeCursor(q.Cursor)
+		if err != nil {
+			return fmt.Errorf("%w: malformed cursor", ErrInvalidQuery)
+		}
+		q.Page = 0
+		q.offset = offset
+	} else {
+		if q.Page < 1 {
+			q.Page = 1
+		}
+		q.offset = (q.Page - 1) * q.Limit
+	}
+
+	filters := make(map[string]string, len(q.Filters))
+	for name, value := range q.Filters {
+		filter, ok := opts.Filters[name]
+		if !ok || value == "" {
+			continue
+		}
+		if filter.Op == OpGte || filter.Op == OpLte {
+			if _, err := parseNumber(value); err != nil {
+				return fmt.Errorf("%w: %s must be a number", ErrInvalidQuery, name)
+			}
+		}
+		filters[name] = value
+	}
+	q.Filters = filters
+
+	return nil
+}
+
+// Filter adds the WHERE clauses for the query's allow-listed filters.
+func (q Query) Filter(db *gorm.DB, opts Options) *gorm.DB {
+	for name, value := range q.Filters {
+		filter := opts.Filters[name]
+		switch filter.Op {
+		case OpILike:
+			db = db.Where(filter.Column+" ILIKE ?", "%"+EscapeLike(value)+"%")
+		case OpGte:
+			number, _ := parseNumber(value)
+			db = db.Where(filter.Column+" >= ?", number)
+		case OpLte:
+			number, _ := parseNumber(value)
+			db = db.Where(filter.Column+" <= ?", number)
+		default:
+			db = db.Where(filter.Column+" = ?", value)
+		}
+	}
+
+	return db
+}
+
+// Paginate adds ORDER BY, OFFSET and LIMIT. tieBreaker should be a unique
+// column so pages are stable when the sort field has duplicates.
+func (q Query) Paginate(db *gorm.DB, opts Options, tieBreaker string) *gorm.DB {
+	order := fmt.Sprintf("%s %s", opts.SortFields[q.Sort], strings.ToUpper(q.Order))
+	if tieBreaker != "" {
+		order += ", " + tieBreaker
+	}
+
+	return db.Order(order).Offset(q.offset).Limit(q.Limit)
+}
+
+// Meta builds the pagination metadata for a page holding returned items out
+// of total matches.
+func (q Query) Meta(total int64, returned int) Meta {
+	meta := Meta{
+		Page:       q.Page,
+		Limit:      q.Limit,
+		Total:      total,
+		TotalPages: int(math.Ceil(float64(total) / float64(q.Limit))),
+	}
+
+	next := q.offset + returned
+	if int64(next) < total {
+		meta.HasMore = true
+		meta.NextCursor = encodeCursor(next)
+	}
+
+	return meta
+}
+
+// EscapeLike escapes LIKE/ILIKE wildcards so user input matches literally.
+func EscapeLike(value string) string {
+	return likeEscaper.Replace(value)
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// parseNumber parses a finite number for the comparison filters.
+func parseNumber(value string) (float64, error) {
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(number) || math.IsInf(number, 0) {
+		return 0, errors.New("not a finite number")
+	}
+
+	return number, nil
+}
+
+func encodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte("o:" + strconv.Itoa(offset)))
+}
+
+func decodeCursor(cursor string) (int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+
+	value, ok := strings.CutPrefix(string(raw), "o:")
+	if !ok {
+		return 0, errors.New("unknown cursor format")
+	}
+
+	offset, err := strconv.Atoi(value)
+	if err != nil || offset < 0 {
+		return 0, errors.New("invalid cursor offset")
+	}
+
+	return offset, nil
+}
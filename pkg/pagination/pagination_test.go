@@ -0,0 +1,100 @@
+package pagination
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testOptions = Options{
+	SortFields:   map[string]string{"name": "name", "created_at": "created_at"},
+	DefaultSort:  "created_at",
+	DefaultOrder: "desc",
+	Filters:      map[string]Filter{"name": {Column: "name", Op: OpILike}},
+}
+
+func TestQuery_Normalize_Defaults(t *testing.T) {
+	q := Query{Limit: 500, Filters: map[string]string{"name": "inn", "page": "2", "unknown": "x"}}
+
+	err := q.Normalize(testOptions)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, q.Page)
+	assert.Equal(t, MaxLimit, q.Limit)
+	assert.Equal(t, "created_at", q.Sort)
+	assert.Equal(t, "desc", q.Order)
+	assert.Equal(t, map[string]string{"name": "inn"}, q.Filters)
+}
+
+func TestQuery_Normalize_RejectsUnknownSort(t *testing.T) {
+	q := Query{Sort: "password"}
+
+	err := q.Normalize(testOptions)
+
+	assert.True(t, errors.Is(err, ErrInvalidQuery))
+}
+
+func TestQuery_Normalize_RejectsBadCursor(t *testing.T) {
+	q := Query{Cursor: "not-a-cursor"}
+
+	err := q.Normalize(testOptions)
+
+	assert.True(t, errors.Is(err, ErrInvalidQuery))
+}
+
+func TestQuery_Meta_CursorRoundTrip(t *testing.T) {
+	q := Query{Page: 1, Limit: 10}
+	assert.NoError(t, q.Normalize(testOptions))
+
+	meta := q.Meta(25, 10)
+	assert.Equal(t, 3, meta.TotalPages)
+	assert.True(t, meta.HasMore)
+
+	next := Query{Limit: 10, Cursor: meta.NextCursor}
+	assert.NoError(t, next.Normalize(testOptions))
+	assert.Equal(t, 10, next.offset)
+
+	last := next.Meta(25, 10)
+	assert.True(t, last.HasMore)
+
+	final := Query{Limit: 10, Cursor: last.NextCursor}
+	assert.NoError(t, final.Normalize(testOptions))
+	assert.False(t, final.Meta(25, 5).HasMore)
+}
+
+func TestQuery_Normalize_RejectsNonNumericRange(t *testing.T) {
+	opts := testOptions
+	opts.Filters = map[string]Filter{"min_price": {Column: "price", Op: OpGte}}
+
+	for _, value := range []string{"abc", "NaN", "Inf", "1e400"} {
+		q := Query{Filters: map[string]string{"min_price": value}}
+		assert.True(t, errors.Is(q.Normalize(opts), ErrInvalidQuery), value)
+	}
+
+	q := Query{Filters: map[string]string{"min_price": "99.5"}}
+	assert.NoError(t, q.Normalize(opts))
+	assert.Equal(t, "99.5", q.Filters["min_price"])
+}
+
+func TestQuery_Meta_LastPage(t *testing.T) {
+	q := Query{Page: 3, Limit: 10}
+	assert.NoError(t, q.Normalize(testOptions))
+
+	meta := q.Meta(25, 5)
+	assert.Equal(t, 3, meta.TotalPages)
+	assert.False(t, meta.HasMore)
+	assert.Empty(t, meta.NextCursor)
+}
+
+func TestQuery_Meta_NormalizedLimit(t *testing.T) {
+	for _, limit := range []int{0, -1, 500} {
+		q := Query{Limit: limit}
+		assert.NoError(t, q.Normalize(testOptions))
+
+		meta := q.Meta(250, q.Limit)
+		assert.GreaterOrEqual(t, meta.Limit, 1)
+		assert.LessOrEqual(t, meta.Limit, MaxLimit)
+		assert.Positive(t, meta.TotalPages)
+	}
+}
@@ -0,0 +1,111 @@
+// Package ratelimit implements token-bucket rate limits over a shared store.
+//
+// Each bucket holds up to Limit.Burst tokens and refills at Limit.Requests
+// per Limit.Period. A request takes one token and is refused when the bucket
+// is empty. Buckets live in a Store: MemoryStore suits a single instance,
+// RedisStore shares buckets between instances.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit is a token-bucket rate. The zero Limit means no limit.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+	// Burst is the bucket size; it defaults to Requests.
+	Burst int
+}
+
+// ParseLimit reads a limit written as "requests/period", optionally followed
+// by ":burst", e.g. "10/1m" or "100/1h:20". "off" disables the limit.
+func ParseLimit(s string) (Limit, error) {
+	s = strings.TrimSpace(s)
+	if s == "off" {
+		return Limit{}, nil
+	}
+
+	rate, burst, hasBurst := strings.Cut(s, ":")
+	requests, period, ok := strings.Cut(rate, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("rate limit %q: want requests/period", s)
+	}
+
+	var limit Limit
+	var err error
+	if limit.Requests, err = strconv.Atoi(requests); err != nil || limit.Requests <= 0 {
+		return Limit{}, fmt.Errorf("rate limit %q: invalid request count", s)
+	}
+	if limit.Period, err = time.ParseDuration(period); err != nil || limit.Period <= 0 {
+		return Limit{}, fmt.Errorf("rate limit %q: invalid period", s)
+	}
+	if hasBurst {
+		if limit.Burst, err = strconv.Atoi(burst); err != nil || limit.Burst <= 0 {
+			return Limit{}, fmt.Errorf("rate limit %q: invalid burst", s)
+		}
+	}
+
+	return limit, nil
+}
+
+// Enabled reports whether the limit restricts anything.
+func (l Limit) Enabled() bool {
+	return l.Requests > 0 && l.Period > 0
+}
+
+// Capacity is the number of tokens a full bucket holds.
+func (l Limit) Capacity() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+
+	return l.Requests
+}
+
+// interval is the time it takes to refill one token, in nanoseconds.
+func (l Limit) interval() float64 {
+	return float64(l.Period) / float64(l.Requests)
+}
+
+// Result is the outcome of taking a token.
+type Result struct {
+	Allowed bool
+	// Remaining is the number of whole tokens left in the bucket.
+	Remaining int
+	// RetryAfter is how long until a token is available; zero when allowed.
+	RetryAfter time.Duration
+	// ResetAfter is how long until the bucket is full again.
+	ResetAfter time.Duration
+}
+
+// Store keeps buckets. Implementations must be safe for concurrent use.
+type Store interface {
+	// Take removes a token from the bucket named key, creating a full bucket
+	// when there is none.
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+var ErrNoLimit = errors.New("rate limit is not enabled")
+
+// result works out a Result from the tokens left after a take.
+func result(limit Limit, allowed bool, tokens float64) Result {
+	interval := limit.interval()
+
+	res := Result{
+		Allowed:    allowed,
+		Remaining:  int(math.Floor(tokens)),
+		ResetAfter: time.Duration((float64(limit.Capacity()) - tokens) * interval),
+	}
+	if !allowed {
+		res.RetryAfter = time.Duration((1 - tokens) * interval)
+	}
+
+	return res
+}
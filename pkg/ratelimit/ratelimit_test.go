@@ -0,0 +1,80 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLimit(t *testing.T) {
+	limit, err := ParseLimit("10/1m")
+	assert.NoError(t, err)
+	assert.Equal(t, Limit{Requests: 10, Period: time.Minute}, limit)
+	assert.Equal(t, 10, limit.Capacity())
+
+	limit, err = ParseLimit("100/1h:20")
+	assert.NoError(t, err)
+	assert.Equal(t, Limit{Requests: 100, Period: time.Hour, Burst: 20}, limit)
+	assert.Equal(t, 20, limit.Capacity())
+
+	limit, err = ParseLimit("off")
+	assert.NoError(t, err)
+	assert.False(t, limit.Enabled())
+
+	for _, invalid := range []string{"", "10", "0/1m", "10/0s", "10/soon", "10/1m:0", "x/1m"} {
+		_, err := ParseLimit(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestMemoryStore_TokenBucket(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	limit := Limit{Requests: 60, Period: time.Minute, Burst: 3}
+	ctx := context.Background()
+
+	for i := 2; i >= 0; i-- {
+		res, err := store.Take(ctx, "client", limit)
+		assert.NoError(t, err)
+		assert.True(t, res.Allowed)
+		assert.Equal(t, i, res.Remaining)
+	}
+
+	res, _ := store.Take(ctx, "client", limit)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, time.Second, res.RetryAfter)
+	assert.Equal(t, 3*time.Second, res.ResetAfter)
+
+	// Other clients have their own bucket.
+	res, _ = store.Take(ctx, "other", limit)
+	assert.True(t, res.Allowed)
+
+	// One token is back after a second; the bucket never holds more than
+	// the burst.
+	now = now.Add(time.Second)
+	res, _ = store.Take(ctx, "client", limit)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+
+	now = now.Add(time.Hour)
+	res, _ = store.Take(ctx, "client", limit)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 2, res.Remaining)
+}
+
+func TestMemoryStore_DropsFullBuckets(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	limit := Limit{Requests: 10, Period: time.Second}
+
+	_, _ = store.Take(context.Background(), "a", limit)
+	now = now.Add(2 * memorySweepInterval)
+	_, _ = store.Take(context.Background(), "b", limit)
+
+	assert.NotContains(t, store.buckets, "a")
+	assert.Contains(t, store.buckets, "b")
+}
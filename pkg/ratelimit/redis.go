@@ -0,0 +1,78 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// takeScript refills and takes from a bucket atomically. It reads the clock
+// of the Redis server, so instances do not need synchronised clocks, and lets
+// the key expire once the bucket would be full again. Needs Redis 5 or any
+// server compatible with its scripting.
+var takeScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local interval = tonumber(ARGV[2])
+local clock = redis.call('TIME')
+local now = tonumber(clock[1]) * 1000000 + tonumber(clock[2])
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'updated')
+local tokens = tonumber(state[1])
+if tokens == nil then
+	tokens = capacity
+else
+	tokens = math.min(capacity, tokens + (now - tonumber(state[2])) / interval)
+end
+
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'updated', tostring(now))
+redis.call('PEXPIRE', KEYS[1], math.ceil((capacity - tokens) * interval / 1000) + 1000)
+
+return {allowed, tostring(tokens)}
+`)
+
+// RedisStore keeps buckets in Redis, or a server speaking its protocol, so
+// every instance of the API shares them.
+type RedisStore struct {
+	client redis.Scripter
+	prefix string
+}
+
+// NewRedisStore stores buckets under keys starting with prefix.
+func NewRedisStore(client redis.Scripter, prefix string) *RedisStore {
+	return &RedisStore{client: client, prefix: prefix}
+}
+
+func (s *RedisStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	if !limit.Enabled() {
+		return Result{}, ErrNoLimit
+	}
+
+	// The script works in microseconds, the resolution of Redis TIME.
+	interval := limit.interval() / float64(time.Microsecond)
+
+	reply, err := takeScript.Run(ctx, s.client, []string{s.prefix + key}, limit.Capacity(), interval).Slice()
+	if err != nil {
+		return Result{}, err
+	}
+	if len(reply) != 2 {
+		return Result{}, fmt.Errorf("rate limit script: unexpected reply %v", reply)
+	}
+
+	allowed, _ := reply[0].(int64)
+	raw, _ := reply[1].(string)
+	tokens, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return Result{}, fmt.Errorf("rate limit script: %w", err)
+	}
+
+	return result(limit, allowed == 1, tokens), nil
+}
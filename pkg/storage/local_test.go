@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalStorage_PutAndDelete(t *testing.T) {
+	root := t.TempDir()
+	store, err := NewLocalStorage(root, "http://localhost:8080/media/")
+	assert.NoError(t, err)
+
+	err = store.Put(context.Background(), "hotels/abc/photo.jpg", strings.NewReader("data"), "image/jpeg")
+	assert.NoError(t, err)
+
+	content, err := os.ReadFile(filepath.Join(root, "hotels", "abc", "photo.jpg"))
+	assert.NoError(t, err)
+	assert.Equal(t, "data", string(content))
+	assert.Equal(t, "http://localhost:8080/media/hotels/abc/photo.jpg", store.URL("hotels/abc/photo.jpg"))
+
+	file, err := store.Open(context.Background(), "hotels/abc/photo.jpg")
+	if assert.NoError(t, err) {
+		opened, _ := io.ReadAll(file)
+		file.Close()
+		assert.Equal(t, "data", string(opened))
+	}
+
+	assert.NoError(t, store.Delete(context.Background(), "hotels/abc/photo.jpg"))
+	assert.NoError(t, store.Delete(context.Background(), "hotels/abc/photo.jpg"))
+}
+
+func TestLocalStorage_RejectsEscapingKeys(t *testing.T) {
+	store, err := NewLocalStorage(t.TempDir(), "http://localhost/media")
+	assert.NoError(t, err)
+
+	for _, key := range []string{"../secret", "/etc/passwd", "a/../../b", ""} {
+		err := store.Put(context.Background(), key, strings.NewReader("x"), "text/plain")
+		assert.ErrorIs(t, err, ErrInvalidKey, key)
+	}
+}
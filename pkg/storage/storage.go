@@ -0,0 +1,21 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var ErrInvalidKey = errors.New("invalid storage key")
+
+// Storage persists files such as uploaded media. Keys are slash-separated
+// relative paths such as "hotels/<id>/<photo>.jpg". Implementations must be
+// safe for concurrent use.
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader, contentType string) error
+	// Open returns the content stored under key; the caller must close it.
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	// URL returns the public URL clients use to fetch key.
+	URL(key string) string
+}
@@ -0,0 +1,87 @@
+package totp
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// rfcSecret is the SHA-1 seed from RFC 6238 appendix B, base32 encoded.
+var rfcSecret = encoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestHOTP_RFC4226Vectors(t *testing.T) {
+	expected := []string{
+		"755224", "287082", "359152", "969429", "338314",
+		"254676", "287922", "162583", "399871", "520489",
+	}
+
+	for counter, code := range expected {
+		assert.Equal(t, code, hotp([]byte("12345678901234567890"), uint64(counter), 6))
+	}
+}
+
+func TestHOTP_RFC6238Vectors(t *testing.T) {
+	cases := []struct {
+		unix int64
+		code string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1111111111, "14050471"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+		{20000000000, "65353130"},
+	}
+
+	for _, tc := range cases {
+		step := Step(time.Unix(tc.unix, 0))
+		assert.Equal(t, tc.code, hotp([]byte("12345678901234567890"), uint64(step), 8), tc.unix)
+	}
+}
+
+func TestCode_UsesSixDigitSuffix(t *testing.T) {
+	code, err := Code(rfcSecret, time.Unix(59, 0))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "287082", code)
+}
+
+func TestValidate_Skew(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	previous, _ := Code(rfcSecret, now.Add(-Period))
+	stale, _ := Code(rfcSecret, now.Add(-2*Period))
+
+	step, ok := Validate(rfcSecret, previous, now, 1)
+	assert.True(t, ok)
+	assert.Equal(t, Step(now)-1, step)
+
+	_, ok = Validate(rfcSecret, stale, now, 1)
+	assert.False(t, ok)
+
+	_, ok = Validate(rfcSecret, "12345", now, 1)
+	assert.False(t, ok)
+
+	_, ok = Validate("not base32!", previous, now, 1)
+	assert.False(t, ok)
+}
+
+func TestProvisioningURI(t *testing.T) {
+	uri := ProvisioningURI("JBSWY3DPEHPK3PXP", "Hotel Booking", "admin@example.com")
+
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/Hotel%20Booking:admin@example.com?"))
+	assert.Contains(t, uri, "secret=JBSWY3DPEHPK3PXP")
+	assert.Contains(t, uri, "issuer=Hotel+Booking")
+	assert.Contains(t, uri, "digits=6")
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+
+	assert.NoError(t, err)
+	assert.Len(t, secret, 32)
+
+	_, err = Code(secret, time.Now())
+	assert.NoError(t, err)
+}
@@ -2,52 +2,172 @@ package util
 
 import (
 	"errors"
+	"hotel-booking-api/pkg/jwtkeys"
 	"os"
+	"slices"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
+const (
+	bookingManageAudience      = "booking-manage"
+	twoFactorChallengeAudience = "2fa-challenge"
+)
+
+var signingKeys *jwtkeys.KeySet
+
+// SetJWTKeys installs the keys every token is signed and verified with. Until
+// it is called, tokens use HS256 with the JWT_SECRET environment variable.
+func SetJWTKeys(keys *jwtkeys.KeySet) {
+	signingKeys = keys
+}
+
+func jwtKeys() *jwtkeys.KeySet {
+	if signingKeys != nil {
+		return signingKeys
+	}
+
+	return jwtkeys.NewHMAC(os.Getenv("JWT_SECRET"), "", "")
+}
+
+// JWTClaims are the claims of an access token. MFA is set when the session
+// was established with a second factor.
 type JWTClaims struct {
 	UserID string `json:"user_id"`
 	Role   string `json:"role"`
+	MFA    bool   `json:"mfa,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// BookingManageClaims authorises the holder of a manage-booking link to act
+// on a single booking without logging in.
+type BookingManageClaims struct {
+	BookingID string `json:"booking_id"`
 	jwt.RegisteredClaims
 }
 
-func GenerateJWT(userID, role string) (string, error) {
-	secretKey := os.Getenv("JWT_SECRET")
-	claims := JWTClaims{
+// GenerateJWT issues an access token valid for ttl. Each token carries a
+// unique ID (jti) so it can be revoked before it expires, and the configured
+// issuer and audience so other services can check it was meant for them.
+func GenerateJWT(userID, role string, mfa bool, ttl time.Duration) (string, *JWTClaims, error) {
+	keys := jwtKeys()
+	now := time.Now()
+	claims := &JWTClaims{
 		UserID: userID,
 		Role:   role,
+		MFA:    mfa,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ID:        uuid.NewString(),
+			Issuer:    keys.Issuer,
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
+	if keys.Audience != "" {
+		claims.Audience = jwt.ClaimStrings{keys.Audience}
+	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signedToken, err := token.SignedString([]byte(secretKey))
+	signedToken, err := keys.Sign(claims)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 
-	return signedToken, err
+	return signedToken, claims, nil
 }
 
+// ParseJWT verifies an access token, including its issuer and audience when
+// those are configured. HS256 tokens carrying neither claim were issued
+// before issuers and audiences existed; they are accepted without the check
+// so signing in again is not needed, and are gone once their TTL runs out.
 func ParseJWT(tokenStr string) (*JWTClaims, error) {
-	secretKey := os.Getenv("JWT_SECRET")
-	token, err := jwt.ParseWithClaims(tokenStr, &JWTClaims{}, func(t *jwt.Token) (any, error) {
-		return []byte(secretKey), nil
-	})
+	keys := jwtKeys()
 
+	token, err := keys.Parse(tokenStr, &JWTClaims{})
 	if err != nil {
 		return nil, err
 	}
 
 	claims, ok := token.Claims.(*JWTClaims)
-	if !ok || !token.Valid {
+	if !ok || !token.Valid || claims.UserID == "" || claims.ID == "" {
+		return nil, errors.New("invalid token")
+	}
+
+	legacy := token.Method == jwt.SigningMethodHS256 && claims.Issuer == "" && len(claims.Audience) == 0
+	if !legacy {
+		if keys.Issuer != "" && claims.Issuer != keys.Issuer {
+			return nil, errors.New("invalid token issuer")
+		}
+		if keys.Audience != "" && !slices.Contains(claims.Audience, keys.Audience) {
+			return nil, errors.New("invalid token audience")
+		}
+	}
+
+	return claims, nil
+}
+
+func GenerateBookingManageToken(bookingID string, expiresAt time.Time) (string, error) {
+	keys := jwtKeys()
+	claims := BookingManageClaims{
+		BookingID: bookingID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    keys.Issuer,
+			Audience:  jwt.ClaimStrings{bookingManageAudience},
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	return keys.Sign(claims)
+}
+
+// ParseBookingManageToken verifies a manage-booking token. The issuer is not
+// checked, so links emailed before an issuer was configured keep working.
+func ParseBookingManageToken(tokenStr string) (*BookingManageClaims, error) {
+	token, err := jwtKeys().Parse(tokenStr, &BookingManageClaims{}, jwt.WithAudience(bookingManageAudience))
+
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(*BookingManageClaims)
+	if !ok || !token.Valid || claims.BookingID == "" {
 		return nil, errors.New("invalid token")
 	}
 
 	return claims, nil
 }
+
+// GenerateTwoFactorChallenge issues the token that proves a user passed the
+// password step of a login and may now present a second factor. It carries
+// the user in the subject claim only, so it is never accepted as an access
+// token.
+func GenerateTwoFactorChallenge(userID string, expiresAt time.Time) (string, error) {
+	keys := jwtKeys()
+	claims := jwt.RegisteredClaims{
+		Issuer:    keys.Issuer,
+		Subject:   userID,
+		Audience:  jwt.ClaimStrings{twoFactorChallengeAudience},
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+	}
+
+	return keys.Sign(claims)
+}
+
+// ParseTwoFactorChallenge returns the user ID of a valid challenge token.
+func ParseTwoFactorChallenge(tokenStr string) (string, error) {
+	token, err := jwtKeys().Parse(tokenStr, &jwt.RegisteredClaims{}, jwt.WithAudience(twoFactorChallengeAudience))
+
+	if err != nil {
+		return "", err
+	}
+
+	claims, ok := token.Claims.(*jwt.RegisteredClaims)
+	if !ok || !token.Valid || claims.Subject == "" {
+		return "", errors.New("invalid token")
+	}
+
+	return claims.Subject, nil
+}
@@ -0,0 +1,63 @@
+package util
+
+import (
+	"hotel-booking-api/pkg/jwtkeys"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func useKeys(t *testing.T, keys *jwtkeys.KeySet) {
+	t.Helper()
+
+	SetJWTKeys(keys)
+	t.Cleanup(func() { SetJWTKeys(nil) })
+}
+
+func accessClaims() *JWTClaims {
+	return &JWTClaims{
+		UserID: uuid.NewString(),
+		Role:   "CUSTOMER",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+	}
+}
+
+func TestParseJWT_ChecksIssuerAndAudience(t *testing.T) {
+	useKeys(t, jwtkeys.NewHMAC("secret", "hotel-booking-api", "hotel-booking-api"))
+
+	token, _, err := GenerateJWT(uuid.NewString(), "CUSTOMER", false, time.Minute)
+	assert.NoError(t, err)
+	_, err = ParseJWT(token)
+	assert.NoError(t, err)
+
+	other := accessClaims()
+	other.Issuer = "someone-else"
+	other.Audience = jwt.ClaimStrings{"hotel-booking-api"}
+	token, err = jwtkeys.NewHMAC("secret", "", "").Sign(other)
+	assert.NoError(t, err)
+	_, err = ParseJWT(token)
+	assert.Error(t, err)
+
+	other.Issuer = "hotel-booking-api"
+	other.Audience = jwt.ClaimStrings{"another-service"}
+	token, err = jwtkeys.NewHMAC("secret", "", "").Sign(other)
+	assert.NoError(t, err)
+	_, err = ParseJWT(token)
+	assert.Error(t, err)
+}
+
+func TestParseJWT_AcceptsTokensIssuedBeforeIssuerAndAudience(t *testing.T) {
+	useKeys(t, jwtkeys.NewHMAC("secret", "hotel-booking-api", "hotel-booking-api"))
+
+	token, err := jwtkeys.NewHMAC("secret", "", "").Sign(accessClaims())
+	assert.NoError(t, err)
+
+	_, err = ParseJWT(token)
+	assert.NoError(t, err)
+}
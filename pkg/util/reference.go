@@ -0,0 +1,45 @@
+package util
+
+import (
+	"crypto/rand"
+	"math/big"
+)
+
+const referenceAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// GenerateReference returns a short human-friendly booking reference such as
+// "BK7QX2M9PA". Ambiguous characters (0/O, 1/I) are left out of the alphabet.
+func GenerateReference() (string, error) {
+	code, err := randomCode(8)
+	if err != nil {
+		return "", err
+	}
+
+	return "BK" + code, nil
+}
+
+// GenerateRecoveryCode returns a one-time two-factor recovery code such as
+// "7QX2M-9PAKD", drawn from the same unambiguous alphabet.
+func GenerateRecoveryCode() (string, error) {
+	code, err := randomCode(10)
+	if err != nil {
+		return "", err
+	}
+
+	return code[:5] + "-" + code[5:], nil
+}
+
+func randomCode(length int) (string, error) {
+	code := make([]byte, length)
+	max := big.NewInt(int64(len(referenceAlphabet)))
+
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		code[i] = referenceAlphabet[n.Int64()]
+	}
+
+	return string(code), nil
+}
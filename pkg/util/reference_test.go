@@ -0,0 +1,22 @@
+package util
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateReference(t *testing.T) {
+	reference, err := GenerateReference()
+
+	assert.NoError(t, err)
+	assert.Regexp(t, regexp.MustCompile(`^BK[A-HJ-NP-Z2-9]{8}$`), reference)
+}
+
+func TestGenerateRecoveryCode(t *testing.T) {
+	code, err := GenerateRecoveryCode()
+
+	assert.NoError(t, err)
+	assert.Regexp(t, regexp.MustCompile(`^[A-HJ-NP-Z2-9]{5}-[A-HJ-NP-Z2-9]{5}$`), code)
+}
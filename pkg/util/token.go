@@ -0,0 +1,59 @@
+package util
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// GenerateOpaqueToken returns a random URL-safe token carrying 256 bits of
+// entropy, suitable for refresh tokens and one-time links.
+func GenerateOpaqueToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// apiKeyTag starts every API key, so leaked keys are easy to recognise and
+// to tell apart from bearer tokens.
+const apiKeyTag = "hbk_"
+
+// GenerateAPIKey returns a new API key and its prefix. The prefix is the
+// non-secret start of the key, used to identify it in listings and logs.
+func GenerateAPIKey() (key, prefix string, err error) {
+	id := make([]byte, 4)
+	if _, err := rand.Read(id); err != nil {
+		return "", "", err
+	}
+
+	secret, err := GenerateOpaqueToken()
+	if err != nil {
+		return "", "", err
+	}
+
+	prefix = apiKeyTag + hex.EncodeToString(id)
+	return prefix + "_" + secret, prefix, nil
+}
+
+// HashToken returns the hex SHA-256 digest under which an opaque token is
+// stored, so a leaked table does not leak usable tokens.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// KeyedHash returns the hex HMAC-SHA256 of value under key. Unlike HashToken
+// it is safe for guessable values such as email addresses: without the key
+// the digest cannot be matched against candidates.
+func KeyedHash(key []byte, value string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(value))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
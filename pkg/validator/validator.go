@@ -53,6 +53,20 @@ func formatError(err validator.FieldError) string {
 		return fmt.Sprintf("%s must be one of: %s", field, err.Param())
 	case "gtfield":
 		return fmt.Sprintf("%s must be after %s", field, err.Param())
+	case "datetime":
+		return fmt.Sprintf("%s must be a date in the format %s", field, err.Param())
+	case "required_with":
+		return fmt.Sprintf("%s is required when %s is set", field, strings.ToLower(err.Param()))
+	case "latitude":
+		return fmt.Sprintf("%s must be a latitude between -90 and 90", field)
+	case "longitude":
+		return fmt.Sprintf("%s must be a longitude between -180 and 180", field)
+	case "gtefield":
+		return fmt.Sprintf("%s must be greater than or equal to %s", field, strings.ToLower(err.Param()))
+	case "iso4217":
+		return fmt.Sprintf("%s must be an ISO 4217 currency code such as EUR", field)
+	case "bcp47_language_tag":
+		return fmt.Sprintf("%s must be a language tag such as en or en-GB", field)
 	case "url":
 		return fmt.Sprintf("%s must be a valid URL", field)
 	case "alpha":
@@ -0,0 +1,88 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"hotel-booking-api/internal/domain"
+	"hotel-booking-api/internal/dto/request"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAmenitySearchText_Integration(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	createAdmin(t, "Amenity Admin", "amenity-admin@test.com", "password123")
+	token := login(t, e, "amenity-admin@test.com", "password123")
+
+	send := func(method, path string, body any) *httptest.ResponseRecorder {
+		payload, _ := json.Marshal(body)
+		req := httptest.NewRequest(method, path, bytes.NewReader(payload))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	hotel := domain.Hotel{Name: "Amenity Hotel", Location: "Amenity City"}
+	if err := testDB.Create(&hotel).Error; err != nil {
+		t.Fatalf("Failed to create hotel: %v", err)
+	}
+
+	rec := send(http.MethodPost, "/api/v1/amenities", request.AmenityRequest{
+		Code:     "rooftop_sauna",
+		Name:     "Rooftop sauna",
+		Category: domain.AmenityCategoryGeneral,
+	})
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	var created struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &created)
+
+	rec = send(http.MethodPut, "/api/v1/hotels/"+hotel.ID.String()+"/amenities", request.SetAmenitiesRequest{
+		Codes: []string{"rooftop_sauna"},
+	})
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	reload := func() domain.Hotel {
+		var current domain.Hotel
+		testDB.First(&current, "id = ?", hotel.ID)
+		return current
+	}
+	assert.Equal(t, "Rooftop sauna", reload().AmenityText)
+
+	t.Run("Renaming an amenity rebuilds the hotel search text", func(t *testing.T) {
+		before := reload().Version
+
+		rec := send(http.MethodPut, "/api/v1/amenities/"+created.Data.ID, request.AmenityRequest{
+			Code:         "rooftop_sauna",
+			Name:         "Panorama sauna",
+			Category:     domain.AmenityCategoryGeneral,
+			Translations: map[string]string{"de": "Panoramasauna"},
+		})
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		after := reload()
+		assert.Equal(t, "Panorama sauna Panoramasauna", after.AmenityText)
+		assert.Greater(t, after.Version, before)
+	})
+
+	t.Run("Deleting an amenity removes it from the search text", func(t *testing.T) {
+		before := reload().Version
+
+		rec := send(http.MethodDelete, "/api/v1/amenities/"+created.Data.ID, nil)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		after := reload()
+		assert.Empty(t, after.AmenityText)
+		assert.Greater(t, after.Version, before)
+	})
+}
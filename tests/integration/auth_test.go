@@ -22,7 +22,6 @@ func TestRegisterAndLogin_Integration(t *testing.T) {
 			Name:     "Integration Test User",
 			Email:    "integration@test.com",
 			Password: "password123",
-			Role:     "CUSTOMER",
 		}
 
 		body, _ := json.Marshal(reqBody)
@@ -0,0 +1,84 @@
+package integration
+
+import (
+	"hotel-booking-api/internal/domain"
+	"hotel-booking-api/internal/repository"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHotelAvailability_Integration(t *testing.T) {
+	_, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	booked := createBookableHotel(t, domain.Hotel{Name: "Overlap Booked", Location: "Overlap Bay"})
+	free := createBookableHotel(t, domain.Hotel{Name: "Overlap Free", Location: "Overlap Bay"})
+	soldOut := createBookableHotel(t, domain.Hotel{Name: "Overlap Sold Out", Location: "Overlap Bay"})
+	testDB.Model(&soldOut.Rooms[0]).UpdateColumn("availability", 0)
+
+	day := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 10)
+	date := func(offset int) string { return day.AddDate(0, 0, offset).Format(time.DateOnly) }
+
+	bookings := []domain.Booking{
+		{Reference: "BKOVERLAP1", RoomID: booked.Rooms[0].ID, CheckIn: day, CheckOut: day.AddDate(0, 0, 2),
+			TotalPrice: 200, Status: domain.BookingStatusConfirmed},
+		{Reference: "BKOVERLAP2", RoomID: booked.Rooms[0].ID, CheckIn: day.AddDate(0, 0, 10), CheckOut: day.AddDate(0, 0, 12),
+			TotalPrice: 200, Status: domain.BookingStatusCancelled},
+	}
+	if err := testDB.Create(&bookings).Error; err != nil {
+		t.Fatalf("Failed to create bookings: %v", err)
+	}
+
+	available := func(checkIn, checkOut int) []string {
+		code, page := searchHotels(t, url.Values{
+			"destination": {"Overlap Bay"}, "check_in": {date(checkIn)}, "check_out": {date(checkOut)}, "sort": {"name"}, "order": {"asc"},
+		})
+		assert.Equal(t, http.StatusOK, code)
+
+		var names []string
+		for _, hotel := range page.Data {
+			names = append(names, hotel.Name)
+		}
+		return names
+	}
+
+	t.Run("Overlapping stays exclude the booked room", func(t *testing.T) {
+		assert.Equal(t, []string{free.Name}, available(1, 3))
+		assert.Equal(t, []string{free.Name}, available(-1, 1))
+		assert.Equal(t, []string{free.Name}, available(-2, 4))
+		assert.Equal(t, []string{free.Name}, available(0, 1))
+	})
+
+	t.Run("Check-out day is free for the next check-in", func(t *testing.T) {
+		assert.Equal(t, []string{booked.Name, free.Name}, available(2, 4))
+		assert.Equal(t, []string{booked.Name, free.Name}, available(-2, 0))
+	})
+
+	t.Run("Cancelled bookings do not block", func(t *testing.T) {
+		assert.Equal(t, []string{booked.Name, free.Name}, available(10, 12))
+	})
+
+	t.Run("Sold-out rooms are never offered", func(t *testing.T) {
+		assert.NotContains(t, available(30, 31), soldOut.Name)
+	})
+
+	t.Run("Room lookup applies the same rules", func(t *testing.T) {
+		rooms := repository.NewRoomRepository(testDB)
+
+		overlapping, err := rooms.FindAvailableByHotel(booked.ID.String(), date(1), date(3))
+		assert.NoError(t, err)
+		assert.Empty(t, overlapping)
+
+		adjacent, err := rooms.FindAvailableByHotel(booked.ID.String(), date(2), date(4))
+		assert.NoError(t, err)
+		assert.Len(t, adjacent, 1)
+
+		none, err := rooms.FindAvailableByHotel(soldOut.ID.String(), date(30), date(31))
+		assert.NoError(t, err)
+		assert.Empty(t, none)
+	})
+}
@@ -0,0 +1,95 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"hotel-booking-api/internal/domain"
+	"hotel-booking-api/internal/dto/request"
+	"hotel-booking-api/pkg/database"
+	"hotel-booking-api/pkg/pagination"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type bookingResult struct {
+	ID        string `json:"id"`
+	Reference string `json:"reference"`
+}
+
+// bookAsGuest books room through the public guest endpoint, which needs no
+// account or verified email.
+func bookAsGuest(t *testing.T, room domain.Room, checkIn, checkOut time.Time) (int, bookingResult) {
+	t.Helper()
+
+	body, _ := json.Marshal(request.CreateGuestBookingRequest{
+		Name: "Guest Traveller", Email: "guest@test.com", Phone: "+351000000",
+		RoomID: room.ID.String(), CheckIn: checkIn, CheckOut: checkOut,
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/guest/bookings", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	testE.ServeHTTP(rec, req)
+
+	var resp struct {
+		Data struct {
+			Booking bookingResult `json:"booking"`
+		} `json:"data"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+	return rec.Code, resp.Data.Booking
+}
+
+func TestBookingReference_Integration(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	createAdmin(t, "Booking Admin", "booking-admin@test.com", "password123")
+	token := login(t, e, "booking-admin@test.com", "password123")
+
+	hotel := createBookableHotel(t, domain.Hotel{Name: "Reference Hotel", Location: "Centre", City: "Braga"})
+	checkIn := time.Now().AddDate(0, 0, 10).Truncate(24 * time.Hour)
+
+	code, booking := bookAsGuest(t, hotel.Rooms[0], checkIn, checkIn.AddDate(0, 0, 2))
+	if !assert.Equal(t, http.StatusCreated, code) {
+		return
+	}
+	assert.Regexp(t, `^BK[A-Z2-9]{8}$`, booking.Reference)
+
+	search := func(params url.Values) (int, hotelPage) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/bookings?"+params.Encode(), nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		var page hotelPage
+		json.Unmarshal(rec.Body.Bytes(), &page)
+		return rec.Code, page
+	}
+
+	t.Run("Search by reference ignores case and caps the limit", func(t *testing.T) {
+		code, page := search(url.Values{"reference": {strings.ToLower(booking.Reference)}, "limit": {"500"}})
+		assert.Equal(t, http.StatusOK, code)
+		if assert.Len(t, page.Data, 1) {
+			assert.Equal(t, booking.ID, page.Data[0].ID)
+		}
+		assert.Equal(t, pagination.MaxLimit, page.Meta.Limit)
+	})
+
+	t.Run("Migrating backfills bookings without a reference", func(t *testing.T) {
+		testDB.Model(&domain.Booking{}).Where("id = ?", booking.ID).UpdateColumn("reference", nil)
+
+		if err := database.AutoMigrate(testDB); err != nil {
+			t.Fatalf("Failed to migrate: %v", err)
+		}
+
+		var backfilled domain.Booking
+		testDB.First(&backfilled, "id = ?", booking.ID)
+		assert.Regexp(t, `^BK[A-Z2-9]{8}$`, backfilled.Reference)
+	})
+}
@@ -0,0 +1,77 @@
+package integration
+
+import (
+	"hotel-booking-api/internal/domain"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHotelGeoSearch_Integration(t *testing.T) {
+	_, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	at := func(name string, lat, lng float64) domain.Hotel {
+		return createBookableHotel(t, domain.Hotel{Name: name, Location: "Geo", Latitude: &lat, Longitude: &lng})
+	}
+	// Distances are from Praça do Comércio, Lisbon (38.7075, -9.1364).
+	baixa := at("Geo Baixa", 38.7107, -9.1366)   // ~0.36 km
+	belem := at("Geo Belem", 38.6916, -9.2160)   // ~7.1 km
+	sintra := at("Geo Sintra", 38.8029, -9.3817) // ~23.8 km
+	at("Geo Porto", 41.1579, -8.6291)            // ~274 km
+	fiji := at("Geo Fiji", -17.7134, 178.0650)
+	samoa := at("Geo Samoa", -13.8333, -171.7500)
+	createBookableHotel(t, domain.Hotel{Name: "Geo Unplaced", Location: "Geo"})
+
+	names := func(page searchPage) []string {
+		var names []string
+		for _, hotel := range page.Data {
+			names = append(names, hotel.Name)
+		}
+		return names
+	}
+	near := url.Values{"lat": {"38.7075"}, "lng": {"-9.1364"}, "sort": {"distance"}}
+
+	t.Run("Nearest hotels come first within the default radius", func(t *testing.T) {
+		code, page := searchHotels(t, near)
+		assert.Equal(t, http.StatusOK, code)
+		if assert.Equal(t, []string{baixa.Name, belem.Name}, names(page)) {
+			assert.InDelta(t, 0.36, *page.Data[0].DistanceKm, 0.05)
+			assert.InDelta(t, 7.1, *page.Data[1].DistanceKm, 0.2)
+		}
+	})
+
+	t.Run("Radius widens the search", func(t *testing.T) {
+		params := url.Values{"radius_km": {"30"}}
+		for key, values := range near {
+			params[key] = values
+		}
+		_, page := searchHotels(t, params)
+		assert.Equal(t, []string{baixa.Name, belem.Name, sintra.Name}, names(page))
+
+		params.Set("order", "desc")
+		_, page = searchHotels(t, params)
+		assert.Equal(t, []string{sintra.Name, belem.Name, baixa.Name}, names(page))
+	})
+
+	t.Run("Bounding box keeps hotels inside it", func(t *testing.T) {
+		_, page := searchHotels(t, url.Values{
+			"min_lat": {"38.68"}, "max_lat": {"38.75"}, "min_lng": {"-9.25"}, "max_lng": {"-9.10"}, "sort": {"name"},
+		})
+		assert.Equal(t, []string{baixa.Name, belem.Name}, names(page))
+	})
+
+	t.Run("Bounding box can wrap the antimeridian", func(t *testing.T) {
+		_, page := searchHotels(t, url.Values{
+			"min_lat": {"-20"}, "max_lat": {"-10"}, "min_lng": {"170"}, "max_lng": {"-170"}, "sort": {"name"},
+		})
+		assert.Equal(t, []string{fiji.Name, samoa.Name}, names(page))
+	})
+
+	t.Run("Distance sort needs a centre", func(t *testing.T) {
+		code, _ := searchHotels(t, url.Values{"sort": {"distance"}})
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"hotel-booking-api/pkg/jsonres"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -19,22 +20,9 @@ func TestHotelOperations_Integration(t *testing.T) {
 	var token string
 	var hotelID string
 
-	// Register admin user
-	t.Run("Register admin user", func(t *testing.T) {
-		reqBody := request.RegisterRequest{
-			Name:     "Admin User",
-			Email:    "admin@test.com",
-			Password: "password123",
-			Role:     "ADMIN",
-		}
-
-		body, _ := json.Marshal(reqBody)
-		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/register", bytes.NewReader(body))
-		req.Header.Set("Content-Type", "application/json")
-		rec := httptest.NewRecorder()
-
-		e.ServeHTTP(rec, req)
-		assert.Equal(t, http.StatusCreated, rec.Code)
+	// Admins cannot self-register; provision one directly
+	t.Run("Create admin user", func(t *testing.T) {
+		createAdmin(t, "Admin User", "admin@test.com", "password123")
 	})
 
 	// Login to get token
@@ -112,4 +100,152 @@ func TestHotelOperations_Integration(t *testing.T) {
 		json.Unmarshal(rec.Body.Bytes(), &resp)
 		assert.True(t, resp.Success)
 	})
+
+	// Conditional requests
+	var etag string
+	t.Run("Get hotel returns ETag and honours If-None-Match", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/hotels/"+hotelID, nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		etag = rec.Header().Get("ETag")
+		assert.Equal(t, `"1"`, etag)
+
+		req = httptest.NewRequest(http.MethodGet, "/api/v1/hotels/"+hotelID, nil)
+		req.Header.Set("If-None-Match", etag)
+		rec = httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+	})
+
+	t.Run("Update hotel requires a current If-Match", func(t *testing.T) {
+		update := func(ifMatch string) *httptest.ResponseRecorder {
+			body, _ := json.Marshal(request.UpdateHotelRequest{
+				Name:        "Renamed Hotel",
+				Location:    "Test City",
+				Description: "A test hotel",
+			})
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/hotels/"+hotelID, bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+token)
+			if ifMatch != "" {
+				req.Header.Set("If-Match", ifMatch)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			return rec
+		}
+
+		assert.Equal(t, http.StatusPreconditionRequired, update("").Code)
+
+		rec := update(etag)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, `"2"`, rec.Header().Get("ETag"))
+
+		// The first ETag is stale now.
+		assert.Equal(t, http.StatusPreconditionFailed, update(etag).Code)
+	})
+
+	t.Run("Patch hotel updates only the supplied fields", func(t *testing.T) {
+		patch := func(contentType, body string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodPatch, "/api/v1/hotels/"+hotelID, strings.NewReader(body))
+			req.Header.Set("Content-Type", contentType)
+			req.Header.Set("Authorization", "Bearer "+token)
+			req.Header.Set("If-Match", `"2"`)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			return rec
+		}
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, patch("text/plain", `{}`).Code)
+		assert.Equal(t, http.StatusBadRequest, patch("application/merge-patch+json", `{"stars": 3}`).Code)
+		assert.Equal(t, http.StatusBadRequest, patch("application/merge-patch+json", `{"name": null}`).Code)
+
+		rec := patch("application/merge-patch+json", `{"description": "Patched"}`)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, `"3"`, rec.Header().Get("ETag"))
+
+		var resp struct {
+			Data struct {
+				Name        string `json:"name"`
+				Description string `json:"description"`
+			} `json:"data"`
+			Meta struct {
+				Changed []string `json:"changed"`
+			} `json:"meta"`
+		}
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		assert.Equal(t, "Renamed Hotel", resp.Data.Name)
+		assert.Equal(t, "Patched", resp.Data.Description)
+		assert.Equal(t, []string{"description"}, resp.Meta.Changed)
+	})
+
+	t.Run("Adding a room changes the hotel ETag", func(t *testing.T) {
+		body, _ := json.Marshal(request.CreateRoomRequest{
+			HotelID:       hotelID,
+			RoomType:      "Double",
+			PricePerNight: 120,
+			Capacity:      2,
+			Availability:  3,
+		})
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/rooms", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+
+		req = httptest.NewRequest(http.MethodGet, "/api/v1/hotels/"+hotelID, nil)
+		req.Header.Set("If-None-Match", `"3"`)
+		rec = httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, `"4"`, rec.Header().Get("ETag"))
+	})
+
+	adminRequest := func(method, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Soft delete, restore and purge
+	t.Run("Purge requires a deleted hotel", func(t *testing.T) {
+		rec := adminRequest(http.MethodDelete, "/api/v1/admin/hotels/"+hotelID)
+		assert.Equal(t, http.StatusConflict, rec.Code)
+	})
+
+	t.Run("Delete hotel hides it", func(t *testing.T) {
+		rec := adminRequest(http.MethodDelete, "/api/v1/hotels/"+hotelID)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		rec = adminRequest(http.MethodGet, "/api/v1/hotels/"+hotelID)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+
+		rec = adminRequest(http.MethodGet, "/api/v1/admin/hotels/deleted")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), hotelID)
+	})
+
+	t.Run("Restore hotel", func(t *testing.T) {
+		rec := adminRequest(http.MethodPost, "/api/v1/admin/hotels/"+hotelID+"/restore")
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		rec = adminRequest(http.MethodGet, "/api/v1/hotels/"+hotelID)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("Purge deleted hotel", func(t *testing.T) {
+		rec := adminRequest(http.MethodDelete, "/api/v1/hotels/"+hotelID)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		rec = adminRequest(http.MethodDelete, "/api/v1/admin/hotels/"+hotelID)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		rec = adminRequest(http.MethodPost, "/api/v1/admin/hotels/"+hotelID+"/restore")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
 }
@@ -0,0 +1,93 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"hotel-booking-api/internal/domain"
+	"hotel-booking-api/pkg/pagination"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type hotelPage struct {
+	Data []struct {
+		ID string `json:"id"`
+	} `json:"data"`
+	Meta pagination.Meta `json:"meta"`
+}
+
+func TestListPagination_Integration(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	for i := range 25 {
+		hotel := domain.Hotel{Name: fmt.Sprintf("Paged Hotel %02d", i), Location: "Page City"}
+		if err := testDB.Create(&hotel).Error; err != nil {
+			t.Fatalf("Failed to create hotel: %v", err)
+		}
+	}
+
+	list := func(params url.Values) (int, hotelPage) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/hotels?"+params.Encode(), nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		var page hotelPage
+		json.Unmarshal(rec.Body.Bytes(), &page)
+		return rec.Code, page
+	}
+
+	t.Run("Following next_cursor visits every hotel once", func(t *testing.T) {
+		seen := map[string]bool{}
+		params := url.Values{"limit": {"10"}, "sort": {"name"}, "order": {"asc"}}
+
+		pages := 0
+		for {
+			code, page := list(params)
+			assert.Equal(t, http.StatusOK, code)
+			pages++
+
+			for _, hotel := range page.Data {
+				assert.False(t, seen[hotel.ID], "hotel %s returned twice", hotel.ID)
+				seen[hotel.ID] = true
+			}
+			if page.Meta.NextCursor == "" {
+				assert.False(t, page.Meta.HasMore)
+				break
+			}
+			if pages > 3 {
+				t.Fatal("cursor did not advance")
+			}
+			params.Set("cursor", page.Meta.NextCursor)
+		}
+
+		assert.Equal(t, 3, pages)
+		assert.Len(t, seen, 25)
+	})
+
+	t.Run("Last numbered page has no more", func(t *testing.T) {
+		code, page := list(url.Values{"page": {"3"}, "limit": {"10"}})
+		assert.Equal(t, http.StatusOK, code)
+		assert.Len(t, page.Data, 5)
+		assert.Equal(t, 3, page.Meta.TotalPages)
+		assert.False(t, page.Meta.HasMore)
+	})
+
+	t.Run("Meta reports the limit that was applied", func(t *testing.T) {
+		_, page := list(url.Values{"limit": {"500"}})
+		assert.Equal(t, pagination.MaxLimit, page.Meta.Limit)
+
+		_, page = list(url.Values{"limit": {"0"}})
+		assert.Equal(t, pagination.DefaultLimit, page.Meta.Limit)
+		assert.Equal(t, 2, page.Meta.TotalPages)
+	})
+
+	t.Run("Non-numeric range filter is rejected", func(t *testing.T) {
+		code, _ := list(url.Values{"min_rating": {"abc"}})
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+}
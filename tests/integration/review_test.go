@@ -0,0 +1,135 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"hotel-booking-api/internal/domain"
+	"hotel-booking-api/internal/dto/request"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReviews_Integration(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	createAdmin(t, "Review Admin", "review-admin@test.com", "password123")
+	adminToken := login(t, e, "review-admin@test.com", "password123")
+
+	body, _ := json.Marshal(request.RegisterRequest{Name: "Happy Guest", Email: "reviewer@test.com", Password: "password123"})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/register", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	e.ServeHTTP(httptest.NewRecorder(), req)
+	guestToken := login(t, e, "reviewer@test.com", "password123")
+
+	var guest domain.User
+	testDB.First(&guest, "email = ?", "reviewer@test.com")
+
+	hotel := createBookableHotel(t, domain.Hotel{Name: "Reviewed Hotel", Location: "Review Street"})
+	stay := func(reference, status string) domain.Booking {
+		checkIn := time.Now().AddDate(0, 0, -5)
+		booking := domain.Booking{
+			Reference: reference, UserID: &guest.ID, RoomID: hotel.Rooms[0].ID,
+			CheckIn: checkIn, CheckOut: checkIn.AddDate(0, 0, 2), TotalPrice: 200, Status: status,
+		}
+		if err := testDB.Create(&booking).Error; err != nil {
+			t.Fatalf("Failed to create booking: %v", err)
+		}
+		return booking
+	}
+	completed := stay("BKREVIEW01", domain.BookingStatusCompleted)
+	cancelled := stay("BKREVIEW02", domain.BookingStatusCancelled)
+
+	send := func(method, path, token string, payload any) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(payload)
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+	review := request.CreateReviewRequest{Rating: 4, Cleanliness: 5, Location: 4, Service: 3, Comment: "Good value"}
+	publicReviews := func() []map[string]any {
+		rec := send(http.MethodGet, "/api/v1/hotels/"+hotel.ID.String()+"/reviews", "", nil)
+		var resp struct {
+			Data []map[string]any `json:"data"`
+		}
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		return resp.Data
+	}
+	rating := func() (float64, int) {
+		var current domain.Hotel
+		testDB.First(&current, "id = ?", hotel.ID)
+		return current.AverageRating, current.ReviewCount
+	}
+
+	var reviewID string
+	t.Run("A completed stay can be reviewed once", func(t *testing.T) {
+		rec := send(http.MethodPost, "/api/v1/bookings/"+completed.ID.String()+"/review", guestToken, review)
+		if !assert.Equal(t, http.StatusCreated, rec.Code) {
+			return
+		}
+		var resp struct {
+			Data struct {
+				ID string `json:"id"`
+			} `json:"data"`
+		}
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		reviewID = resp.Data.ID
+
+		rec = send(http.MethodPost, "/api/v1/bookings/"+completed.ID.String()+"/review", guestToken, review)
+		assert.Equal(t, http.StatusConflict, rec.Code)
+
+		average, count := rating()
+		assert.Equal(t, 4.0, average)
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("Cancelled stays cannot be reviewed", func(t *testing.T) {
+		rec := send(http.MethodPost, "/api/v1/bookings/"+cancelled.ID.String()+"/review", guestToken, review)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("Public reviews do not reveal the booking", func(t *testing.T) {
+		reviews := publicReviews()
+		if assert.Len(t, reviews, 1) {
+			assert.Equal(t, "Happy Guest", reviews[0]["reviewer"])
+			assert.NotContains(t, reviews[0], "booking_id")
+			assert.NotContains(t, reviews[0], "user_id")
+		}
+	})
+
+	t.Run("Hidden reviews leave the public list and the rating", func(t *testing.T) {
+		rec := send(http.MethodPatch, "/api/v1/admin/reviews/"+reviewID+"/moderation", adminToken,
+			request.ModerateReviewRequest{Status: domain.ReviewStatusHidden, Note: "Under investigation"})
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		assert.Empty(t, publicReviews())
+		average, count := rating()
+		assert.Zero(t, average)
+		assert.Zero(t, count)
+	})
+
+	t.Run("Guests cannot moderate", func(t *testing.T) {
+		rec := send(http.MethodPatch, "/api/v1/admin/reviews/"+reviewID+"/moderation", guestToken,
+			request.ModerateReviewRequest{Status: domain.ReviewStatusVisible})
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("Restored reviews count again", func(t *testing.T) {
+		rec := send(http.MethodPatch, "/api/v1/admin/reviews/"+reviewID+"/moderation", adminToken,
+			request.ModerateReviewRequest{Status: domain.ReviewStatusVisible})
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		assert.Len(t, publicReviews(), 1)
+		_, count := rating()
+		assert.Equal(t, 1, count)
+	})
+}
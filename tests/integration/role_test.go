@@ -0,0 +1,80 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"hotel-booking-api/internal/domain"
+	"hotel-booking-api/internal/dto/request"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCustomRoleGrants_Integration(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+	defer testDB.Exec("DELETE FROM roles WHERE name = ?", "Auditor")
+
+	createAdmin(t, "Role Admin", "role-admin@test.com", "password123")
+	adminToken := login(t, e, "role-admin@test.com", "password123")
+
+	body, _ := json.Marshal(request.RegisterRequest{Name: "Future Auditor", Email: "auditor@test.com", Password: "password123"})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/register", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	e.ServeHTTP(httptest.NewRecorder(), req)
+
+	var auditor domain.User
+	if err := testDB.First(&auditor, "email = ?", "auditor@test.com").Error; err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+
+	send := func(method, path, token string, payload any) *httptest.ResponseRecorder {
+		var body []byte
+		if payload != nil {
+			body, _ = json.Marshal(payload)
+		}
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("Customers cannot read the audit log", func(t *testing.T) {
+		token := login(t, e, "auditor@test.com", "password123")
+		assert.Equal(t, http.StatusForbidden, send(http.MethodGet, "/api/v1/admin/audit-logs", token, nil).Code)
+	})
+
+	t.Run("System role names are reserved", func(t *testing.T) {
+		rec := send(http.MethodPost, "/api/v1/admin/roles", adminToken, request.RoleRequest{
+			Name: domain.RoleStaff, Permissions: []string{domain.PermAuditRead},
+		})
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("A custom role grants its permissions", func(t *testing.T) {
+		rec := send(http.MethodPost, "/api/v1/admin/roles", adminToken, request.RoleRequest{
+			Name: "Auditor", Permissions: []string{domain.PermAuditRead},
+		})
+		if !assert.Equal(t, http.StatusCreated, rec.Code) {
+			return
+		}
+		var created struct {
+			Data struct {
+				ID string `json:"id"`
+			} `json:"data"`
+		}
+		json.Unmarshal(rec.Body.Bytes(), &created)
+
+		rec = send(http.MethodPut, "/api/v1/admin/users/"+auditor.ID.String()+"/roles", adminToken,
+			request.SetUserRolesRequest{RoleIDs: []string{created.Data.ID}})
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		token := login(t, e, "auditor@test.com", "password123")
+		assert.Equal(t, http.StatusOK, send(http.MethodGet, "/api/v1/admin/audit-logs", token, nil).Code)
+		assert.Equal(t, http.StatusForbidden, send(http.MethodGet, "/api/v1/admin/roles", token, nil).Code)
+	})
+}
@@ -0,0 +1,141 @@
+package integration
+
+import (
+	"encoding/json"
+	"hotel-booking-api/internal/domain"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type searchPage struct {
+	Data []struct {
+		ID         string   `json:"id"`
+		Name       string   `json:"name"`
+		DistanceKm *float64 `json:"distance_km"`
+		Relevance  *float64 `json:"relevance"`
+		Highlight  *struct {
+			Name        string `json:"name"`
+			Description string `json:"description"`
+		} `json:"highlight"`
+	} `json:"data"`
+}
+
+// createBookableHotel stores hotel with one free room, which search requires.
+func createBookableHotel(t *testing.T, hotel domain.Hotel) domain.Hotel {
+	t.Helper()
+
+	hotel.Rooms = []domain.Room{{RoomType: "Double", PricePerNight: 100, Availability: 2, Capacity: 2}}
+	if err := testDB.Create(&hotel).Error; err != nil {
+		t.Fatalf("Failed to create hotel: %v", err)
+	}
+
+	return hotel
+}
+
+func searchHotels(t *testing.T, params url.Values) (int, searchPage) {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/hotels/search?"+params.Encode(), nil)
+	rec := httptest.NewRecorder()
+	testE.ServeHTTP(rec, req)
+
+	var page searchPage
+	json.Unmarshal(rec.Body.Bytes(), &page)
+	return rec.Code, page
+}
+
+func TestHotelTextSearch_Integration(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	harbour := createBookableHotel(t, domain.Hotel{
+		Name: "Harbour Lights", Location: "Old Port", City: "Lisbon", Country: "Portugal",
+		Description: "Rooms above the water.",
+	})
+	inland := createBookableHotel(t, domain.Hotel{
+		Name: "Hillside Inn", Location: "Upper Town", City: "Lisbon", Country: "Portugal",
+		Description: "A short walk down to the harbour.",
+	})
+	spa := createBookableHotel(t, domain.Hotel{
+		Name: "Quiet Rest", Location: "Centre", City: "Porto", Country: "Portugal",
+		Description: "Calm rooms.", AmenityText: "Sauna",
+	})
+
+	t.Run("Name matches rank above description matches", func(t *testing.T) {
+		code, page := searchHotels(t, url.Values{"q": {"harbour"}})
+		assert.Equal(t, http.StatusOK, code)
+		if assert.Len(t, page.Data, 2) {
+			assert.Equal(t, harbour.ID.String(), page.Data[0].ID)
+			assert.Equal(t, inland.ID.String(), page.Data[1].ID)
+			assert.Greater(t, *page.Data[0].Relevance, *page.Data[1].Relevance)
+		}
+	})
+
+	t.Run("Matches are highlighted", func(t *testing.T) {
+		_, page := searchHotels(t, url.Values{"q": {"harbour"}})
+		if assert.NotEmpty(t, page.Data) && assert.NotNil(t, page.Data[0].Highlight) {
+			assert.Equal(t, "<mark>Harbour</mark> Lights", page.Data[0].Highlight.Name)
+		}
+		if assert.Len(t, page.Data, 2) && assert.NotNil(t, page.Data[1].Highlight) {
+			assert.Contains(t, page.Data[1].Highlight.Description, "<mark>harbour</mark>")
+		}
+	})
+
+	t.Run("Amenities are searchable", func(t *testing.T) {
+		_, page := searchHotels(t, url.Values{"q": {"sauna"}})
+		if assert.Len(t, page.Data, 1) {
+			assert.Equal(t, spa.ID.String(), page.Data[0].ID)
+		}
+	})
+
+	t.Run("Destination matches city and country", func(t *testing.T) {
+		_, page := searchHotels(t, url.Values{"destination": {"porto"}})
+		if assert.Len(t, page.Data, 1) {
+			assert.Equal(t, spa.ID.String(), page.Data[0].ID)
+		}
+
+		_, page = searchHotels(t, url.Values{"destination": {"Portugal"}})
+		assert.Len(t, page.Data, 3)
+	})
+
+	t.Run("Partial words match", func(t *testing.T) {
+		_, page := searchHotels(t, url.Values{"q": {"Hillsi"}})
+		if assert.Len(t, page.Data, 1) {
+			assert.Equal(t, inland.ID.String(), page.Data[0].ID)
+		}
+	})
+
+	t.Run("Misspelt words match with pg_trgm", func(t *testing.T) {
+		if !testCfg.Search.Trigram {
+			t.Skip("SEARCH_TRIGRAM is off")
+		}
+
+		_, page := searchHotels(t, url.Values{"q": {"Harbuor"}})
+		if assert.NotEmpty(t, page.Data) {
+			assert.Equal(t, harbour.ID.String(), page.Data[0].ID)
+		}
+	})
+
+	t.Run("Autocomplete suggests destinations and hotels by prefix", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/hotels/autocomplete?q=lis", nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var resp struct {
+			Data []struct {
+				Type string `json:"type"`
+				Text string `json:"text"`
+			} `json:"data"`
+		}
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		if assert.NotEmpty(t, resp.Data) {
+			assert.Equal(t, "destination", resp.Data[0].Type)
+			assert.Equal(t, "Lisbon", resp.Data[0].Text)
+		}
+	})
+}
@@ -1,6 +1,11 @@
 package integration
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"hotel-booking-api/internal/domain"
+	"hotel-booking-api/internal/dto/request"
 	"hotel-booking-api/internal/handler"
 	"hotel-booking-api/internal/middleware"
 	"hotel-booking-api/internal/repository"
@@ -8,8 +13,14 @@ import (
 	"hotel-booking-api/internal/service"
 	"hotel-booking-api/pkg/config"
 	"hotel-booking-api/pkg/database"
+	"hotel-booking-api/pkg/jwtkeys"
 	"hotel-booking-api/pkg/logger"
+	"hotel-booking-api/pkg/mailer"
+	"hotel-booking-api/pkg/storage"
+	"hotel-booking-api/pkg/util"
 	"hotel-booking-api/pkg/validator"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 
@@ -18,13 +29,22 @@ import (
 )
 
 var (
-	testDB *gorm.DB
-	testE  *echo.Echo
+	testDB  *gorm.DB
+	testE   *echo.Echo
+	testCfg *config.Config
 )
 
 func setupTestServer(t *testing.T) (*echo.Echo, func()) {
 	os.Setenv("APP_ENV", "test")
 	os.Setenv("DB_NAME", "hotel_booking_test")
+	// Tests run without extra extensions unless asked to cover pg_trgm
+	if os.Getenv("SEARCH_TRIGRAM") == "" {
+		os.Setenv("SEARCH_TRIGRAM", "false")
+	}
+	// .env ships without a secret
+	if os.Getenv("JWT_SECRET") == "" && os.Getenv("JWT_PRIVATE_KEY_FILE") == "" {
+		os.Setenv("JWT_SECRET", "integration-test-secret")
+	}
 
 	cfg, err := config.Load()
 	if err != nil {
@@ -41,45 +61,144 @@ func setupTestServer(t *testing.T) (*echo.Echo, func()) {
 	if err := database.AutoMigrate(db); err != nil {
 		t.Fatalf("Failed to migrate: %v", err)
 	}
+	if err := repository.MigrateSearchIndexes(db, cfg.Search.Trigram); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
 
 	testDB = db
+	testCfg = cfg
+
+	jwtKeys, err := jwtkeys.Load(jwtkeys.Config{
+		PrivateKeyFile:    cfg.JWT.PrivateKeyFile,
+		PublicKeyFiles:    cfg.JWT.PublicKeyFiles,
+		Secret:            cfg.JWT.SecretKey,
+		LegacySecretUntil: cfg.JWT.LegacySecretUntil,
+		Issuer:            cfg.JWT.Issuer,
+		Audience:          cfg.JWT.Audience,
+	})
+	if err != nil {
+		t.Fatalf("Failed to load JWT keys: %v", err)
+	}
+	util.SetJWTKeys(jwtKeys)
+
+	mediaStorage, err := storage.NewLocalStorage(t.TempDir(), "http://localhost:8080/media")
+	if err != nil {
+		t.Fatalf("Failed to initialise media storage: %v", err)
+	}
+
+	exportStorage, err := storage.NewLocalStorage(t.TempDir(), "")
+	if err != nil {
+		t.Fatalf("Failed to initialise export storage: %v", err)
+	}
+
+	mail, err := mailer.NewFileMailer(t.TempDir(), "no-reply@localhost")
+	if err != nil {
+		t.Fatalf("Failed to initialise mailer: %v", err)
+	}
 
 	validate := validator.New()
 	userRepo := repository.NewUserRepository(db)
-	hotelRepo := repository.NewHotelRepository(db)
+	hotelRepo := repository.NewHotelRepository(db, cfg.Search.Trigram)
 	roomRepo := repository.NewRoomRepository(db)
 	bookingRepo := repository.NewBookingRepository(db)
 	paymentRepo := repository.NewPaymentRepository(db)
+	guestRepo := repository.NewGuestRepository(db)
+	amenityRepo := repository.NewAmenityRepository(db)
+	photoRepo := repository.NewPhotoRepository(db)
+	reviewRepo := repository.NewReviewRepository(db)
+	staffRepo := repository.NewHotelStaffRepository(db)
+	roleRepo := repository.NewRoleRepository(db)
+	sessionRepo := repository.NewSessionRepository(db)
+	userTokenRepo := repository.NewUserTokenRepository(db)
+	loginAttemptRepo := repository.NewLoginAttemptRepository(db)
+	recoveryCodeRepo := repository.NewRecoveryCodeRepository(db)
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	dataExportRepo := repository.NewDataExportRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
 
-	authService := service.NewAuthService(userRepo, validate)
-	hotelService := service.NewHotelService(hotelRepo)
-	roomService := service.NewRoomService(roomRepo, hotelRepo)
-	bookingService := service.NewBookingService(db, bookingRepo, roomRepo, paymentRepo)
+	authService := service.NewAuthService(userRepo, sessionRepo, loginAttemptRepo, recoveryCodeRepo, validate, service.AuthOptions{
+		AccessTTL:             cfg.JWT.AccessTTL,
+		RefreshTTL:            cfg.JWT.RefreshTTL,
+		TOTPIssuer:            cfg.App.Name,
+		RequireAdminTwoFactor: cfg.Auth.RequireAdminTwoFactor,
+		AuditHashKey:          []byte(cfg.Auth.AuditHashKey),
+	})
+	accountService := service.NewAccountService(userRepo, userTokenRepo, sessionRepo, mail, cfg.App.PublicURL)
+	hotelService := service.NewHotelService(hotelRepo, bookingRepo, staffRepo)
+	roomService := service.NewRoomService(roomRepo, hotelRepo, bookingRepo, staffRepo)
+	bookingService := service.NewBookingService(db, bookingRepo, roomRepo, paymentRepo, guestRepo, staffRepo)
 	paymentService := service.NewPaymentService(bookingRepo, paymentRepo, roomRepo)
+	amenityService := service.NewAmenityService(amenityRepo, hotelRepo, roomRepo)
+	reviewService := service.NewReviewService(reviewRepo, bookingRepo, staffRepo)
+	staffService := service.NewStaffService(staffRepo, hotelRepo, userRepo)
+	roleService := service.NewRoleService(roleRepo, userRepo)
+	userService := service.NewUserService(db, userRepo, staffRepo, hotelRepo, sessionRepo)
+	profileService := service.NewProfileService(userRepo, bookingRepo)
+	dataExportService := service.NewDataExportService(dataExportRepo, userRepo, exportStorage, mail, cfg.App.PublicURL)
+	auditService := service.NewAuditService(auditRepo)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo, roleRepo, hotelRepo, staffRepo)
+	photoService := service.NewPhotoService(photoRepo, hotelRepo, roomRepo, mediaStorage, cfg.Storage.MaxUploadMB<<20)
 
-	authHandler := handler.NewAuthHandler(authService)
+	authHandler := handler.NewAuthHandler(authService, accountService)
 	hotelHandler := handler.NewHotelHandler(hotelService)
 	roomHandler := handler.NewRoomHandler(roomService)
 	bookingHandler := handler.NewBookingHandler(bookingService)
 	paymentHandler := handler.NewPaymentHandler(paymentService)
+	guestBookingHandler := handler.NewGuestBookingHandler(bookingService, paymentService)
+	amenityHandler := handler.NewAmenityHandler(amenityService)
+	photoHandler := handler.NewPhotoHandler(photoService)
+	reviewHandler := handler.NewReviewHandler(reviewService)
+	staffHandler := handler.NewStaffHandler(staffService)
+	roleHandler := handler.NewRoleHandler(roleService)
+	userHandler := handler.NewUserHandler(userService)
+	profileHandler := handler.NewProfileHandler(profileService, accountService)
+	dataExportHandler := handler.NewDataExportHandler(dataExportService)
+	jwksHandler := handler.NewJWKSHandler(jwtKeys)
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService)
+	auditHandler := handler.NewAuditHandler(auditService)
 
 	e := echo.New()
 	e.HTTPErrorHandler = middleware.ErrorHandler
 
+	router.SetupJWKSRoutes(e.Group("/.well-known"), jwksHandler)
+
 	api := e.Group("/api/v1")
-	router.SetupAuthRoutes(api, authHandler)
-	router.SetupHotelRoutes(api, hotelHandler, middleware.AuthMiddleware())
-	router.SetupRoomRoutes(api, roomHandler, middleware.AuthMiddleware())
-	router.SetupBookingRoutes(api, bookingHandler, middleware.AuthMiddleware())
-	router.SetupPaymentRoutes(api, paymentHandler)
+	auth := middleware.AuthMiddleware(authService, apiKeyService)
+	// Account and access management is for people, not API keys
+	userAuth := middleware.AuthMiddleware(authService, nil)
+	perm := middleware.NewRequirePermission(roleService, cfg.Auth.RequireAdminTwoFactor)
+	// Every test request comes from the same address, so limits stay off
+	noLimit := func(next echo.HandlerFunc) echo.HandlerFunc { return next }
+	router.SetupAuthRoutes(api, authHandler, userAuth, perm, noLimit)
+	router.SetupHotelRoutes(api, hotelHandler, auth, perm)
+	router.SetupRoomRoutes(api, roomHandler, auth, perm)
+	router.SetupAmenityRoutes(api, amenityHandler, auth, perm)
+	router.SetupPhotoRoutes(api, photoHandler, auth, perm)
+	router.SetupReviewRoutes(api, reviewHandler, auth, perm)
+	router.SetupBookingRoutes(api, bookingHandler, auth, perm, middleware.RequireVerifiedEmail(accountService, cfg.Auth.RequireVerifiedEmailForBooking), noLimit)
+	router.SetupStaffRoutes(api, staffHandler, auth, perm)
+	router.SetupRoleRoutes(api, roleHandler, userAuth, perm)
+	router.SetupUserRoutes(api, userHandler, userAuth, perm)
+	router.SetupProfileRoutes(api, profileHandler, userAuth, perm)
+	router.SetupDataExportRoutes(api, dataExportHandler, userAuth, perm)
+	router.SetupAPIKeyRoutes(api, apiKeyHandler, userAuth, perm)
+	router.SetupAuditRoutes(api, auditHandler, userAuth, perm)
+	router.SetupGuestBookingRoutes(api, guestBookingHandler, noLimit)
+	router.SetupPaymentRoutes(api, paymentHandler, noLimit)
 
 	testE = e
 
+	workers, stopWorkers := context.WithCancel(context.Background())
+	go dataExportService.Run(workers)
+
 	// Cleanup function
 	cleanup := func() {
+		stopWorkers()
+
 		// Clean test data
 		db.Exec("TRUNCATE TABLE payments CASCADE")
 		db.Exec("TRUNCATE TABLE bookings CASCADE")
+		db.Exec("TRUNCATE TABLE guests CASCADE")
 		db.Exec("TRUNCATE TABLE rooms CASCADE")
 		db.Exec("TRUNCATE TABLE hotels CASCADE")
 		db.Exec("TRUNCATE TABLE users CASCADE")
@@ -90,3 +209,49 @@ func setupTestServer(t *testing.T) (*echo.Echo, func()) {
 
 	return e, cleanup
 }
+
+// createAdmin provisions an admin account the way the create-admin command
+// does, since registration only creates customers.
+func createAdmin(t *testing.T, name, email, password string) {
+	t.Helper()
+
+	userService := service.NewUserService(
+		testDB,
+		repository.NewUserRepository(testDB),
+		repository.NewHotelStaffRepository(testDB),
+		repository.NewHotelRepository(testDB, testCfg.Search.Trigram),
+		repository.NewSessionRepository(testDB),
+	)
+
+	_, err := userService.CreateUser(service.Actor{Role: domain.RoleAdmin}, service.NewUserInput{
+		Name:     name,
+		Email:    email,
+		Password: password,
+		Role:     domain.RoleAdmin,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create admin: %v", err)
+	}
+}
+
+// login signs in through the API and returns the access token.
+func login(t *testing.T, e *echo.Echo, email, password string) string {
+	t.Helper()
+
+	body, _ := json.Marshal(request.LoginRequest{Email: email, Password: password})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Failed to log in as %s: %d %s", email, rec.Code, rec.Body.String())
+	}
+
+	var resp struct {
+		Data struct {
+			Token string `json:"token"`
+		} `json:"data"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+	return resp.Data.Token
+}
@@ -0,0 +1,69 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"hotel-booking-api/internal/domain"
+	"hotel-booking-api/internal/dto/request"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStaffRoleSync_Integration(t *testing.T) {
+	e, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	createAdmin(t, "Staff Admin", "staff-admin@test.com", "password123")
+	token := login(t, e, "staff-admin@test.com", "password123")
+
+	first := domain.Hotel{Name: "First Hotel", Location: "Staff City"}
+	second := domain.Hotel{Name: "Second Hotel", Location: "Staff City"}
+	user := domain.User{Name: "Future Manager", Email: "future-manager@test.com", Password: "x", Role: domain.RoleCustomer}
+	for _, record := range []any{&first, &second, &user} {
+		if err := testDB.Create(record).Error; err != nil {
+			t.Fatalf("Failed to create record: %v", err)
+		}
+	}
+
+	send := func(method string, hotel domain.Hotel, role string) int {
+		var body []byte
+		if role != "" {
+			body, _ = json.Marshal(request.AssignStaffRequest{Role: role})
+		}
+		path := "/api/v1/admin/hotels/" + hotel.ID.String() + "/staff/" + user.ID.String()
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	role := func() string {
+		var current domain.User
+		testDB.Select("role").First(&current, "id = ?", user.ID)
+		return current.Role
+	}
+
+	t.Run("Assigning staff promotes a customer", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, send(http.MethodPut, first, domain.RoleStaff))
+		assert.Equal(t, domain.RoleStaff, role())
+	})
+
+	t.Run("A manager assignment anywhere outranks staff", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, send(http.MethodPut, second, domain.RoleHotelManager))
+		assert.Equal(t, domain.RoleHotelManager, role())
+	})
+
+	t.Run("Removing the manager assignment demotes to staff", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, send(http.MethodDelete, second, ""))
+		assert.Equal(t, domain.RoleStaff, role())
+	})
+
+	t.Run("Removing the last assignment demotes to customer", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, send(http.MethodDelete, first, ""))
+		assert.Equal(t, domain.RoleCustomer, role())
+	})
+}